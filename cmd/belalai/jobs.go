@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// startJobs run periodic job in background, they stop when shutdown begin
+// every replica run them, so a job must be safe to run concurrently
+func startJobs(db *pgxpool.Pool, bg *utils.Background, cfg *configs.Config) {
+	transactionRepository := repository.NewTransactionRepository(db)
+	bg.Every(context.Background(), cfg.Trash.PurgeInterval, func(ctx context.Context) {
+		// hidden transaction past retention can't be restored anymore, it stay hidden
+		purged, err := transactionRepository.PurgeTrash(ctx, cfg.Trash.Retention)
+		if err != nil {
+			slog.Error("failed purge trash", "err", err)
+			return
+		}
+		if purged > 0 {
+			slog.Info("trash purged", "count", purged)
+		}
+	})
+}
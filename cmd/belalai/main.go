@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	// timezone database for runtime image without tzdata (alpine)
+	_ "time/tzdata"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
+	"github.com/Belalai-E-Wallet-Backend/internal/metrics"
+	"github.com/Belalai-E-Wallet-Backend/internal/routers"
+	"github.com/Belalai-E-Wallet-Backend/internal/tracing"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// @title 											Belalai E-Wallet
+// @version 										1.0
+// @description 								E-wallet team belalai
+// @host												127.0.0.1:3000/api/
+// @securityDefinitions.apikey 	JWTtoken
+// @in header
+// @name Authorization
+func main() {
+	// without command the server is started
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "serve":
+		case "migrate":
+			os.Exit(runMigrate(os.Args[2:]))
+		case "seed":
+			os.Exit(runSeed(os.Args[2:]))
+		default:
+			fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+			os.Exit(2)
+		}
+	}
+	os.Exit(serve())
+}
+
+const usage = `usage:
+  belalai [serve]                   start HTTP server
+  belalai migrate up                apply all pending migration
+  belalai migrate down [N|all]      revert the last N migration (default 1)
+  belalai migrate status            show current version and pending migration
+  belalai migrate force VERSION     set version and clear dirty flag without running migration
+  belalai seed [-users N] [flags]   create payment method, users and a year of random history (see seed -h)
+`
+
+// serve run HTTP server until SIGTERM, exit code is not 0 when it can't start or stop on error
+// so supervisor (systemd, k8s, docker) restart it and alert
+func serve() int {
+	// load & validate config, stop early when something is missing
+	cfg, err := configs.LoadConfig()
+	if err != nil {
+		slog.Error("invalid config", "err", err)
+		return 1
+	}
+
+	// structured JSON log, secret & PII is redacted before written
+	// std log package is also routed into this logger
+	slog.SetDefault(logger.New(os.Stdout, cfg.Log.Level, cfg.Log.Format))
+	if cfg.IsProduction() {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	// tracing, span is exported to stdout or OTLP collector
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Options{
+		ServiceName: cfg.Trace.ServiceName,
+		Environment: cfg.App.Env,
+		Exporter:    cfg.Trace.Exporter,
+		Endpoint:    cfg.Trace.Endpoint,
+		SampleRatio: cfg.Trace.SampleRatio,
+	})
+	if err != nil {
+		slog.Error("failed init tracing", "err", err)
+		return 1
+	}
+
+	// Inisialization databae for this project
+	db, err := configs.InitDB(cfg.DB, tracing.PgxTracer{})
+	if err != nil {
+		slog.Error("failed to connect db", "err", err)
+		return 1
+	}
+
+	err = configs.PingDB(db)
+	if err != nil {
+		slog.Error("ping to db failed", "err", err)
+		db.Close()
+		return 1
+	}
+
+	slog.Info("db connected")
+
+	// replica started at the same time wait each other on advisory lock
+	if cfg.DB.AutoMigrate {
+		if err := migrateUp(context.Background(), db); err != nil {
+			slog.Error("failed auto migrate", "err", err)
+			db.Close()
+			return 1
+		}
+	}
+
+	if err := metrics.RegisterPgxPool(db); err != nil {
+		slog.Warn("failed register pgxpool metrics", "err", err)
+	}
+
+	// inisialization redish
+	rdb := configs.InitRedis(cfg.Redis)
+	rdb.AddHook(metrics.RedisHook{})
+	rdb.AddHook(tracing.RedisHook{})
+	cmd := rdb.Ping(context.Background())
+	if cmd.Err() != nil {
+		slog.Error("failed ping on redis", "err", cmd.Err())
+		rdb.Close()
+		db.Close()
+		return 1
+	}
+	slog.Info("redis connected")
+
+	// inisialization mail transport (smtp, file or memory)
+	mailer, err := utils.NewMailer(cfg.Mail)
+	if err != nil {
+		slog.Error("failed init mailer", "err", err)
+		rdb.Close()
+		db.Close()
+		return 1
+	}
+
+	// task running outside request (ex: email), drained on shutdown
+	bg := utils.NewBackground()
+	startJobs(db, bg, cfg)
+
+	// Inisialization engine gin, HTTP framework
+	router := routers.InitRouter(db, rdb, mailer, bg, cfg)
+	srv := &http.Server{
+		Addr:              cfg.App.Addr(),
+		Handler:           router,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+	// stream & websocket is not tracked by Shutdown, tell them to close
+	srv.RegisterOnShutdown(bg.Stop)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("server listening", "addr", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	code := 0
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			slog.Error("server stopped", "err", err)
+			code = 1
+		}
+	case <-ctx.Done():
+		slog.Info("shutdown signal received, draining request")
+		// readyz report draining, give load balancer time to stop sending traffic
+		bg.Stop()
+		time.Sleep(cfg.Server.DrainDelay)
+	}
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	// 1. stop accepting request & wait in-flight handler
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("failed graceful shutdown HTTP server", "err", err)
+	}
+	// 2. wait background task (ex: sending email) which may still use db & redis
+	bg.Stop()
+	if err := bg.Wait(shutdownCtx); err != nil {
+		slog.Warn("background task not finished", "err", err)
+	}
+	// 3. close connection
+	db.Close()
+	slog.Info("db closed")
+	if err := rdb.Close(); err != nil {
+		slog.Error("failed close redis", "err", err)
+	}
+	slog.Info("redis closed")
+	// flush remaining span
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("failed flush tracing", "err", err)
+	}
+	return code
+}
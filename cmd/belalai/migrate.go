@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/Belalai-E-Wallet-Backend/db"
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// runMigrate handle `belalai migrate ...`, only database config is needed
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	cfg, err := configs.LoadDBConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid config:", err)
+		return 1
+	}
+	c, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := configs.InitDB(cfg, nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to connect db:", err)
+		return 1
+	}
+	defer pool.Close()
+
+	switch args[0] {
+	case "up":
+		err = migrateUp(c, pool)
+	case "down":
+		err = migrateDown(c, pool, args[1:])
+	case "status":
+		err = migrateStatus(c, pool)
+	case "force":
+		err = migrateForce(c, pool, args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate command %q\n\n%s", args[0], usage)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "migrate", args[0]+":", err)
+		return 1
+	}
+	return 0
+}
+
+// migrateUp is also used by auto migrate on server startup
+func migrateUp(c context.Context, pool *pgxpool.Pool) error {
+	migrator, err := db.NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up(c)
+	for _, m := range applied {
+		slog.Info("migration applied", "version", m.Version, "name", m.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		slog.Info("no pending migration")
+	}
+	return nil
+}
+
+func migrateDown(c context.Context, pool *pgxpool.Pool, args []string) error {
+	n := 1
+	if len(args) > 0 {
+		if args[0] == "all" {
+			n = -1
+		} else if n, _ = strconv.Atoi(args[0]); n <= 0 {
+			return fmt.Errorf("N must be a positive number or all, got %q", args[0])
+		}
+	}
+
+	migrator, err := db.NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		n = len(migrator.Migrations())
+	}
+	reverted, err := migrator.Down(c, n)
+	for _, m := range reverted {
+		slog.Info("migration reverted", "version", m.Version, "name", m.Name)
+	}
+	return err
+}
+
+func migrateStatus(c context.Context, pool *pgxpool.Pool) error {
+	migrator, err := db.NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+	version, dirty, err := migrator.Version(c)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("version: %d", version)
+	if dirty {
+		fmt.Print(" (dirty)")
+	}
+	fmt.Println()
+	for _, m := range migrator.Migrations() {
+		state := "pending"
+		if m.Version <= version {
+			state = "applied"
+		}
+		fmt.Printf("  %06d  %-8s %s\n", m.Version, state, m.Name)
+	}
+	return nil
+}
+
+func migrateForce(c context.Context, pool *pgxpool.Pool, args []string) error {
+	if len(args) == 0 {
+		return errors.New("VERSION is required")
+	}
+	version, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("VERSION must be a number, got %q", args[0])
+	}
+
+	migrator, err := db.NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+	if err := migrator.Force(c, uint(version)); err != nil {
+		return err
+	}
+	slog.Info("migration version forced", "version", version)
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+	"github.com/Belalai-E-Wallet-Backend/internal/seed"
+)
+
+// runSeed handle `belalai seed ...`, schema must be migrated first
+func runSeed(args []string) int {
+	opt := seed.Options{}
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	flags.IntVar(&opt.Users, "users", 20, "number of user to create")
+	flags.IntVar(&opt.Months, "months", 12, "history is spread across the last N months")
+	flags.StringVar(&opt.Password, "password", "Belalai123!", "password of every seeded user")
+	flags.StringVar(&opt.PIN, "pin", "123456", "pin of every seeded user")
+	flags.Uint64Var(&opt.Seed, "seed", 0, "random seed to reproduce the same data, 0 pick a random one")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := configs.LoadDBConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid config:", err)
+		return 1
+	}
+	c, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := configs.InitDB(cfg, nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to connect db:", err)
+		return 1
+	}
+	defer pool.Close()
+
+	result, err := seed.Run(c, pool, opt)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "seed:", err)
+		return 1
+	}
+	slog.Info("seed finished", "users", result.Users, "topups", result.TopUps, "transfers", result.Transfers, "seed", result.Seed)
+	fmt.Printf("login with seedN@%s (ex: seed1@%s), password %q and pin %q\n", seed.EmailDomain, seed.EmailDomain, opt.Password, opt.PIN)
+	return 0
+}
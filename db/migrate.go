@@ -0,0 +1,193 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrDirty mean last migration failed half way (ex: run by old migrate CLI), schema must be fixed by hand then forced
+var ErrDirty = errors.New("database is dirty, fix the schema manually then run migrate force VERSION")
+
+// schema_migrations is the same table of golang-migrate, so database migrated by the CLI keep working
+const (
+	createVersionTable = `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`
+	// only one process migrate the same schema at a time (ex: many replica starting with auto migrate)
+	lockKey = `hashtext('belalai:' || current_schema() || ':schema_migrations')`
+)
+
+// Migrator apply embedded migration, every migration run in its own transaction together with version update
+type Migrator struct {
+	db         *pgxpool.Pool
+	migrations []Migration
+}
+
+func NewMigrator(db *pgxpool.Pool) (*Migrator, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Migrations return all embedded migration sorted by version
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Version return current version of database, 0 when nothing is migrated yet
+func (m *Migrator) Version(c context.Context) (uint, bool, error) {
+	conn, err := m.db.Acquire(c)
+	if err != nil {
+		return 0, false, err
+	}
+	defer conn.Release()
+
+	var exists bool
+	if err := conn.QueryRow(c, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); err != nil {
+		return 0, false, err
+	}
+	if !exists {
+		return 0, false, nil
+	}
+	return version(c, conn.Conn())
+}
+
+// Up apply every pending migration, return the applied one
+func (m *Migrator) Up(c context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(c, func(conn *pgx.Conn, current uint) error {
+		for _, migration := range m.migrations {
+			if migration.Version <= current {
+				continue
+			}
+			if err := apply(c, conn, migration.Up, migration.Version); err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down revert the last n applied migration, return the reverted one
+func (m *Migrator) Down(c context.Context, n int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.withLock(c, func(conn *pgx.Conn, current uint) error {
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < n; i-- {
+			migration := m.migrations[i]
+			if migration.Version > current {
+				continue
+			}
+			var previous uint
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			if err := apply(c, conn, migration.Down, previous); err != nil {
+				return fmt.Errorf("migration %d_%s down: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Force set version without running migration and clear dirty flag, used after schema is fixed by hand
+func (m *Migrator) Force(c context.Context, v uint) error {
+	if v != 0 && !slices.ContainsFunc(m.migrations, func(migration Migration) bool { return migration.Version == v }) {
+		return fmt.Errorf("version %d doesn't exist", v)
+	}
+	conn, err := m.db.Acquire(c)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	if err := lock(c, conn.Conn()); err != nil {
+		return err
+	}
+	defer unlock(conn.Conn())
+	return pgx.BeginFunc(c, conn, func(tx pgx.Tx) error {
+		return setVersion(c, tx, v)
+	})
+}
+
+// withLock hold advisory lock on a single connection while fn run, refuse to run on dirty database
+func (m *Migrator) withLock(c context.Context, fn func(conn *pgx.Conn, current uint) error) error {
+	conn, err := m.db.Acquire(c)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	if err := lock(c, conn.Conn()); err != nil {
+		return err
+	}
+	defer unlock(conn.Conn())
+
+	current, dirty, err := version(c, conn.Conn())
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("version %d: %w", current, ErrDirty)
+	}
+	return fn(conn.Conn(), current)
+}
+
+// table is created after lock is held, concurrent CREATE TABLE IF NOT EXISTS can conflict with each other
+func lock(c context.Context, conn *pgx.Conn) error {
+	if _, err := conn.Exec(c, "SELECT pg_advisory_lock("+lockKey+")"); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	if _, err := conn.Exec(c, createVersionTable); err != nil {
+		unlock(conn)
+		return err
+	}
+	return nil
+}
+
+// unlock use its own context, request context may be already canceled
+func unlock(conn *pgx.Conn) {
+	conn.Exec(context.Background(), "SELECT pg_advisory_unlock("+lockKey+")")
+}
+
+func version(c context.Context, conn *pgx.Conn) (uint, bool, error) {
+	var v int64
+	var dirty bool
+	err := conn.QueryRow(c, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&v, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return uint(v), dirty, nil
+}
+
+// apply run sql and move version in one transaction, failed migration leave nothing behind
+func apply(c context.Context, conn *pgx.Conn, sql string, v uint) error {
+	return pgx.BeginFunc(c, conn, func(tx pgx.Tx) error {
+		// without argument pgx use simple protocol, so file may contain many statement
+		if _, err := tx.Exec(c, sql); err != nil {
+			return err
+		}
+		return setVersion(c, tx, v)
+	})
+}
+
+// version 0 mean empty schema, like golang-migrate the table has no row
+func setVersion(c context.Context, tx pgx.Tx, v uint) error {
+	if _, err := tx.Exec(c, "DELETE FROM schema_migrations"); err != nil {
+		return err
+	}
+	if v == 0 {
+		return nil
+	}
+	_, err := tx.Exec(c, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, FALSE)", int64(v))
+	return err
+}
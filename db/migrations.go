@@ -0,0 +1,84 @@
+package db
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migrations is all sql migration file, embedded so binary know which schema version it need
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
+
+// Migration is one version of schema, file name is VERSION_NAME.up.sql & VERSION_NAME.down.sql
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// LoadMigrations read embedded migration sorted by version, every version must have up & down file
+func LoadMigrations() ([]Migration, error) {
+	files, err := fs.Glob(Migrations, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[uint]*Migration{}
+	for _, file := range files {
+		name := strings.TrimPrefix(file, "migrations/")
+		prefix, rest, ok := strings.Cut(name, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: name must be VERSION_NAME.up.sql or VERSION_NAME.down.sql", name)
+		}
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: invalid version: %w", name, err)
+		}
+		sql, err := fs.ReadFile(Migrations, file)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[uint(version)]
+		if !ok {
+			m = &Migration{Version: uint(version)}
+			byVersion[uint(version)] = m
+		}
+		switch {
+		case strings.HasSuffix(rest, ".up.sql"):
+			m.Name, m.Up = strings.TrimSuffix(rest, ".up.sql"), string(sql)
+		case strings.HasSuffix(rest, ".down.sql"):
+			m.Down = string(sql)
+		default:
+			return nil, fmt.Errorf("migration %s: name must end with .up.sql or .down.sql", name)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d: up and down file are required", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// LatestVersion return highest version from migration file name (ex: 000009_xxx.up.sql -> 9)
+func LatestVersion() (uint, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
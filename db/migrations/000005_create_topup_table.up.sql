@@ -7,6 +7,6 @@ CREATE TABLE topup (
     topup_status topup_status NOT NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    deleted_at TIMESTAMP DEFAULT NULL
+    deleted_at TIMESTAMP DEFAULT NULL,
     CONSTRAINT fk_topup_payment FOREIGN KEY (payment_id) REFERENCES payment_method(id)
 );
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE profile ADD COLUMN language VARCHAR(5) NOT NULL DEFAULT 'id';
\ No newline at end of file
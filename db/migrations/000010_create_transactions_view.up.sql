@@ -0,0 +1,56 @@
+-- one row per transaction per owner: transfer appear once for sender (Send) and once for receiver (Transfer)
+-- new transaction type is added here as another UNION ALL with the same columns
+CREATE VIEW transactions AS
+SELECT
+    t.id,
+    'transfer' AS kind,
+    'Send' AS transaction_type,
+    ws.user_id,
+    ws.id AS wallet_id,
+    wr.user_id AS counterparty_user_id,
+    NULL::INT AS payment_id,
+    t.amount,
+    0 AS tax,
+    t.transfer_status::TEXT AS status,
+    COALESCE(t.notes, '') AS notes,
+    t.created_at,
+    t.deleted_by_sender AS deleted
+FROM transfer t
+JOIN wallets ws ON ws.id = t.sender_wallet_id
+JOIN wallets wr ON wr.id = t.receiver_wallet_id
+UNION ALL
+SELECT
+    t.id,
+    'transfer',
+    'Transfer',
+    wr.user_id,
+    wr.id,
+    ws.user_id,
+    NULL::INT,
+    t.amount,
+    0,
+    t.transfer_status::TEXT,
+    COALESCE(t.notes, ''),
+    t.created_at,
+    t.deleted_by_receiver
+FROM transfer t
+JOIN wallets ws ON ws.id = t.sender_wallet_id
+JOIN wallets wr ON wr.id = t.receiver_wallet_id
+UNION ALL
+SELECT
+    tp.id,
+    'topup',
+    'Topup',
+    w.user_id,
+    w.id,
+    NULL::INT,
+    tp.payment_id,
+    tp.amount,
+    COALESCE(tp.tax, 0),
+    tp.topup_status::TEXT,
+    '',
+    tp.created_at,
+    tp.deleted_at IS NOT NULL
+FROM topup tp
+JOIN wallets_topup wt ON wt.topup_id = tp.id
+JOIN wallets w ON w.id = wt.wallets_id;
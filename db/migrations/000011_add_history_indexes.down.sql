@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_profile_fullname_trgm;
+DROP INDEX IF EXISTS idx_transfer_notes_trgm;
+-- pg_trgm is kept, it may be used outside this migration
+DROP INDEX IF EXISTS idx_topup_created_at;
+DROP INDEX IF EXISTS idx_wallets_topup_topup_id;
+DROP INDEX IF EXISTS idx_transfer_receiver_created_at;
+DROP INDEX IF EXISTS idx_transfer_sender_created_at;
+DROP INDEX IF EXISTS idx_wallets_user_id;
@@ -0,0 +1,11 @@
+-- history of a user start from its wallet, then walk transfer & topup newest first
+CREATE INDEX IF NOT EXISTS idx_wallets_user_id ON wallets (user_id);
+CREATE INDEX IF NOT EXISTS idx_transfer_sender_created_at ON transfer (sender_wallet_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_transfer_receiver_created_at ON transfer (receiver_wallet_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_wallets_topup_topup_id ON wallets_topup (topup_id);
+CREATE INDEX IF NOT EXISTS idx_topup_created_at ON topup (created_at DESC, id DESC);
+
+-- free text search use ILIKE '%q%', only trigram index can serve it
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS idx_transfer_notes_trgm ON transfer USING GIN (notes gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_profile_fullname_trgm ON profile USING GIN (fullname gin_trgm_ops);
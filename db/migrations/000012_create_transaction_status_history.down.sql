@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS topup_status_history ON topup;
+DROP TRIGGER IF EXISTS transfer_status_history ON transfer;
+DROP FUNCTION IF EXISTS record_transaction_status();
+DROP TABLE IF EXISTS transaction_status_history;
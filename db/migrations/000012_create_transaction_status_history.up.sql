@@ -0,0 +1,41 @@
+-- every status of transfer & topup, shown as timeline on transaction detail
+-- filled by trigger so every writer (app, seed, manual fix) is recorded
+CREATE TABLE transaction_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    kind TEXT NOT NULL,
+    transaction_id INT NOT NULL,
+    status TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_transaction_status_history_transaction ON transaction_status_history (kind, transaction_id, created_at);
+
+-- argument is kind and name of status column, update without status change isn't recorded
+CREATE FUNCTION record_transaction_status() RETURNS TRIGGER AS $$
+DECLARE
+    new_status TEXT := to_jsonb(NEW) ->> TG_ARGV[1];
+BEGIN
+    IF TG_OP = 'UPDATE' AND new_status IS NOT DISTINCT FROM (to_jsonb(OLD) ->> TG_ARGV[1]) THEN
+        RETURN NEW;
+    END IF;
+    INSERT INTO transaction_status_history (kind, transaction_id, status, created_at)
+    VALUES (
+        TG_ARGV[0],
+        NEW.id,
+        new_status,
+        CASE WHEN TG_OP = 'INSERT' THEN COALESCE(NEW.created_at, CURRENT_TIMESTAMP) ELSE CURRENT_TIMESTAMP END
+    );
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER transfer_status_history AFTER INSERT OR UPDATE OF transfer_status ON transfer
+FOR EACH ROW EXECUTE FUNCTION record_transaction_status('transfer', 'transfer_status');
+
+CREATE TRIGGER topup_status_history AFTER INSERT OR UPDATE OF topup_status ON topup
+FOR EACH ROW EXECUTE FUNCTION record_transaction_status('topup', 'topup_status');
+
+-- status change before this migration is unknown, existing row start with its current status
+INSERT INTO transaction_status_history (kind, transaction_id, status, created_at)
+SELECT 'transfer', id, transfer_status::TEXT, COALESCE(created_at, CURRENT_TIMESTAMP) FROM transfer;
+INSERT INTO transaction_status_history (kind, transaction_id, status, created_at)
+SELECT 'topup', id, topup_status::TEXT, COALESCE(created_at, CURRENT_TIMESTAMP) FROM topup;
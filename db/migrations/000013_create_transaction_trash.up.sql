@@ -0,0 +1,23 @@
+-- transaction hidden by user which can still be restored
+-- row is purged after retention period, hidden flag stay so hiding become permanent
+CREATE TABLE transaction_trash (
+    user_id INT NOT NULL,
+    kind TEXT NOT NULL,
+    transaction_id INT NOT NULL,
+    deleted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, kind, transaction_id),
+    CONSTRAINT fk_transaction_trash_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_transaction_trash_deleted_at ON transaction_trash (deleted_at);
+CREATE INDEX idx_transaction_trash_user_deleted_at ON transaction_trash (user_id, deleted_at DESC);
+
+-- transaction hidden before trash existed get a full retention period from now
+INSERT INTO transaction_trash (user_id, kind, transaction_id)
+SELECT user_id, kind, id FROM transactions WHERE deleted AND kind = 'transfer';
+
+INSERT INTO transaction_trash (user_id, kind, transaction_id)
+SELECT w.user_id, 'topup', tp.id
+FROM topup tp
+JOIN wallets_topup wt ON wt.topup_id = tp.id
+JOIN wallets w ON w.id = wt.wallets_id
+WHERE tp.deleted_at IS NOT NULL;
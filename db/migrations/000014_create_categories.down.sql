@@ -0,0 +1,3 @@
+DROP FUNCTION IF EXISTS categorize;
+DROP TABLE IF EXISTS transaction_category;
+DROP TABLE IF EXISTS category_rule;
@@ -0,0 +1,82 @@
+-- keyword rule assigning spending category to outgoing transfer
+-- rule of user (user_id) is tried before default rule (user_id NULL), oldest first
+CREATE TABLE category_rule (
+    id SERIAL PRIMARY KEY,
+    user_id INT NULL,
+    field TEXT NOT NULL CHECK (field IN ('notes', 'counterparty')),
+    keyword TEXT NOT NULL,
+    category TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_category_rule_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_category_rule_user_id ON category_rule (user_id);
+
+-- keyword is lowercase, it is matched as substring of lowercase notes
+-- keep it in sync with defaultCategoryRules of repository/memory
+INSERT INTO category_rule (field, keyword, category) VALUES
+    ('notes', 'makan', 'food'),
+    ('notes', 'food', 'food'),
+    ('notes', 'kopi', 'food'),
+    ('notes', 'coffee', 'food'),
+    ('notes', 'resto', 'food'),
+    ('notes', 'warung', 'food'),
+    ('notes', 'gojek', 'transport'),
+    ('notes', 'grab', 'transport'),
+    ('notes', 'ojek', 'transport'),
+    ('notes', 'bensin', 'transport'),
+    ('notes', 'parkir', 'transport'),
+    ('notes', 'taksi', 'transport'),
+    ('notes', 'kereta', 'transport'),
+    ('notes', 'belanja', 'shopping'),
+    ('notes', 'tokopedia', 'shopping'),
+    ('notes', 'shopee', 'shopping'),
+    ('notes', 'baju', 'shopping'),
+    ('notes', 'listrik', 'bills'),
+    ('notes', 'pulsa', 'bills'),
+    ('notes', 'internet', 'bills'),
+    ('notes', 'wifi', 'bills'),
+    ('notes', 'tagihan', 'bills'),
+    ('notes', 'bpjs', 'bills'),
+    ('notes', 'netflix', 'entertainment'),
+    ('notes', 'spotify', 'entertainment'),
+    ('notes', 'bioskop', 'entertainment'),
+    ('notes', 'konser', 'entertainment'),
+    ('notes', 'game', 'entertainment'),
+    ('notes', 'obat', 'health'),
+    ('notes', 'dokter', 'health'),
+    ('notes', 'apotek', 'health'),
+    ('notes', 'klinik', 'health'),
+    ('notes', 'sekolah', 'education'),
+    ('notes', 'kuliah', 'education'),
+    ('notes', 'spp', 'education'),
+    ('notes', 'kursus', 'education'),
+    ('notes', 'keluarga', 'family'),
+    ('notes', 'ortu', 'family'),
+    ('notes', 'arisan', 'family');
+
+-- category picked by user for own transaction, win over any rule
+CREATE TABLE transaction_category (
+    user_id INT NOT NULL,
+    kind TEXT NOT NULL,
+    transaction_id INT NOT NULL,
+    category TEXT NOT NULL,
+    PRIMARY KEY (user_id, kind, transaction_id),
+    CONSTRAINT fk_transaction_category_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- categorize is category of one row of transactions view for its owner, it is computed when read
+-- so changing rule apply to old transaction too: picked by user, topup & received transfer by type,
+-- sent transfer by the first matching rule, otherwise other
+CREATE FUNCTION categorize(p_user_id INT, p_kind TEXT, p_id INT, p_type TEXT, p_notes TEXT, p_counterparty TEXT)
+RETURNS TEXT LANGUAGE SQL STABLE AS $$
+    SELECT COALESCE(
+        (SELECT tc.category FROM transaction_category tc
+            WHERE tc.user_id = p_user_id AND tc.kind = p_kind AND tc.transaction_id = p_id),
+        CASE p_type WHEN 'Topup' THEN 'topup' WHEN 'Transfer' THEN 'income' END,
+        (SELECT r.category FROM category_rule r
+            WHERE (r.user_id = p_user_id OR r.user_id IS NULL)
+                AND STRPOS(LOWER(CASE r.field WHEN 'notes' THEN p_notes ELSE p_counterparty END), r.keyword) > 0
+            ORDER BY r.user_id IS NULL, r.id
+            LIMIT 1),
+        'other')
+$$;
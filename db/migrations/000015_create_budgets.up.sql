@@ -0,0 +1,22 @@
+-- monthly spending limit of user, empty category is overall budget of every spending
+-- spent amount isn't stored, it is summed from sent transfer of the month like chart category
+CREATE TABLE budget (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    category TEXT NOT NULL DEFAULT '',
+    amount INT NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    CONSTRAINT uq_budget_user_category UNIQUE (user_id, category),
+    CONSTRAINT fk_budget_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- threshold (percent) of budget already notified on a month, so crossing it is notified once
+CREATE TABLE budget_alert (
+    budget_id INT NOT NULL,
+    month DATE NOT NULL,
+    threshold INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (budget_id, month, threshold),
+    CONSTRAINT fk_budget_alert_budget FOREIGN KEY (budget_id) REFERENCES budget(id) ON DELETE CASCADE
+);
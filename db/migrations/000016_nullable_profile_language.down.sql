@@ -0,0 +1,3 @@
+UPDATE profile SET language = 'id' WHERE language IS NULL;
+ALTER TABLE profile ALTER COLUMN language SET DEFAULT 'id';
+ALTER TABLE profile ALTER COLUMN language SET NOT NULL;
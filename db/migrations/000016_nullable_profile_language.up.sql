@@ -0,0 +1,5 @@
+-- NULL language mean user hasn't picked one, message follow Accept-Language of the device
+-- 'id' was only the column default and can't be told apart from a picked language, so it is reset
+ALTER TABLE profile ALTER COLUMN language DROP NOT NULL;
+ALTER TABLE profile ALTER COLUMN language DROP DEFAULT;
+UPDATE profile SET language = NULL WHERE language = 'id';
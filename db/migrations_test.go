@@ -0,0 +1,30 @@
+package db
+
+import "testing"
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no migration embedded")
+	}
+	// golang-migrate -seq create version without gap
+	for i, m := range migrations {
+		if m.Version != uint(i+1) {
+			t.Errorf("migration %d has version %d, want %d", i, m.Version, i+1)
+		}
+		if m.Name == "" {
+			t.Errorf("migration %d has no name", m.Version)
+		}
+	}
+
+	latest, err := LatestVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != migrations[len(migrations)-1].Version {
+		t.Errorf("LatestVersion() = %d, want %d", latest, migrations[len(migrations)-1].Version)
+	}
+}
@@ -15,7 +15,149 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth": {
+        "/dev/mails": {
+            "get": {
+                "description": "List emails captured by file/memory mail transport, newest first. Only available on local development",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dev"
+                ],
+                "summary": "List captured emails",
+                "responses": {
+                    "200": {
+                        "description": "Captured emails",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseData"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dev"
+                ],
+                "summary": "Delete all captured emails",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/dev/mails/{id}": {
+            "get": {
+                "description": "Render captured email in browser, use format=text for plain-text alternative or format=json for raw data",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "dev"
+                ],
+                "summary": "View captured email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Mail ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "html (default), text or json",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Email body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Mail Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Always ok while the process is able to serve HTTP, doesn't check dependency",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Check postgres, redis, mail transport and migration version. Return 503 when one of them is down or server is draining on shutdown",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthStatus"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/auth": {
             "post": {
                 "description": "login using email and password and return as response with JWT token",
                 "consumes": [
@@ -90,7 +232,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/change-password": {
+        "/v1/auth/change-password": {
             "patch": {
                 "security": [
                     {
@@ -147,7 +289,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/change-pin": {
+        "/v1/auth/change-pin": {
             "patch": {
                 "security": [
                     {
@@ -204,7 +346,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/confirm-pin": {
+        "/v1/auth/confirm-pin": {
             "post": {
                 "security": [
                     {
@@ -241,15 +383,15 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Invalid request, PIN_INVALID or PIN_NOT_SET",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid PIN or unauthorized",
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
                         }
                     },
                     "500": {
@@ -261,7 +403,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/forgot-password": {
+        "/v1/auth/forgot-password": {
             "post": {
                 "description": "Send a reset password link with token to the user's email",
                 "consumes": [
@@ -307,7 +449,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/forgot-pin": {
+        "/v1/auth/forgot-pin": {
             "post": {
                 "description": "Send a reset PIN link with token to the user's email",
                 "consumes": [
@@ -353,7 +495,59 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/reset-password": {
+        "/v1/auth/register": {
+            "post": {
+                "description": "Register new user with input email \u0026 password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "register"
+                ],
+                "summary": "Register new user",
+                "parameters": [
+                    {
+                        "description": "Input email and password new user",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AuthRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.BadRequestResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "EMAIL_ALREADY_REGISTERED",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/auth/reset-password": {
             "post": {
                 "description": "Reset user password using the token received via email",
                 "consumes": [
@@ -399,7 +593,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/reset-pin": {
+        "/v1/auth/reset-pin": {
             "post": {
                 "description": "Reset user PIN using the token received via email",
                 "consumes": [
@@ -445,7 +639,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/update-pin": {
+        "/v1/auth/update-pin": {
             "patch": {
                 "security": [
                     {
@@ -502,7 +696,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/balance": {
+        "/v1/balance": {
             "get": {
                 "security": [
                     {
@@ -548,14 +742,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/chart/{duration}": {
+        "/v1/budgets": {
             "get": {
                 "security": [
                     {
-                        "JWTtoken": []
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Mendapatkan data chart keuangan pengguna yang sudah diautentikasi (berdasarkan ID pengguna dari token JWT) dengan filter durasi tertentu.",
+                "description": "Every budget with spent, remaining and percentage on month (default this month). Month follow APP_TIMEZONE, overall budget (empty category) spent every category",
                 "consumes": [
                     "application/json"
                 ],
@@ -563,48 +757,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Chart"
+                    "budget"
                 ],
-                "summary": "Mendapatkan data chart berdasarkan durasi filter",
+                "summary": "Get budgets progress",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Durasi filter data chart (contoh: 'seven_days', 'five_weeks', 'twelve_months')",
-                        "name": "duration",
-                        "in": "path",
-                        "required": true
+                        "description": "Month (YYYY-MM)",
+                        "name": "month",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Data chart berhasil diambil",
+                        "description": "Success Response",
                         "schema": {
-                            "$ref": "#/definitions/models.ChartDataResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ResponseData"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.BudgetsProgress"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid month",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.UnauthorizedResponse"
                         }
                     },
                     "500": {
-                        "description": "Kesalahan server internal",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.InternalErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/profile": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
-                        "JWTtoken": []
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Mengambil detail profil pengguna yang sedang login (berdasarkan ID dari token JWT).",
+                "description": "Create budget of spending category (empty category is overall budget) or replace its amount. Reaching 80% and 100% of it is notified once a month, changing the amount notify it again",
                 "consumes": [
                     "application/json"
                 ],
@@ -612,12 +821,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "budget"
+                ],
+                "summary": "Set budget",
+                "parameters": [
+                    {
+                        "description": "Budget",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BudgetRequest"
+                        }
+                    }
                 ],
-                "summary": "Mendapatkan detail profil pengguna",
                 "responses": {
                     "200": {
-                        "description": "Detail profil berhasil diambil",
+                        "description": "Success Response",
                         "schema": {
                             "allOf": [
                                 {
@@ -626,94 +846,1751 @@ const docTemplate = `{
                                 {
                                     "type": "object",
                                     "properties": {
-                                        "Data": {
-                                            "$ref": "#/definitions/models.ProfileResponse"
+                                        "data": {
+                                            "$ref": "#/definitions/models.Budget"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Invalid body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "401": {
-                        "description": "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.UnauthorizedResponse"
                         }
                     },
-                    "404": {
-                        "description": "Profil pengguna tidak ditemukan",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.NotFoundResponse"
+                            "$ref": "#/definitions/models.InternalErrorResponse"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/v1/budgets/{id}": {
+            "delete": {
                 "security": [
                     {
-                        "JWTtoken": []
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Memperbarui nama lengkap, nomor telepon, email, dan/atau gambar profil pengguna yang sedang login.",
+                "description": "Remove budget, its spending isn't tracked anymore",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "budget"
                 ],
-                "summary": "Memperbarui detail profil pengguna",
+                "summary": "Delete budget",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Nama lengkap pengguna",
-                        "name": "fullname",
-                        "in": "formData",
+                        "type": "integer",
+                        "description": "Budget ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Nomor telepon pengguna",
-                        "name": "phone",
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Budget not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/category": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Category of transaction, only spending category can be used by rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "category"
+                ],
+                "summary": "Get categories",
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ResponseData"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Category"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/category/rules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rule of user in the order they are tried, before the default rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "category"
+                ],
+                "summary": "Get category rules",
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ResponseData"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.CategoryRule"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rule apply to every sent transfer, the old one too, unless user picked its category. Max 50 rule per user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "category"
+                ],
+                "summary": "Create category rule",
+                "parameters": [
+                    {
+                        "description": "Rule",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CategoryRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Success Response",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ResponseData"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CategoryRule"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid body or too many rule",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/category/rules/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Transfer categorized by the rule go back to the next matching rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "category"
+                ],
+                "summary": "Delete category rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chart": {
+            "get": {
+                "security": [
+                    {
+                        "JWTtoken": []
+                    }
+                ],
+                "description": "Pemasukan \u0026 pengeluaran per hari, minggu (label hari senin) atau bulan dari tanggal from sampai to (keduanya termasuk), dihitung pada timezone tz (default APP_TIMEZONE). Maksimal 366 bucket.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chart"
+                ],
+                "summary": "Mendapatkan data chart dengan rentang tanggal sendiri",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tanggal mulai (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "description": "Ukuran bucket (default: day)",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone, contoh: Asia/Jakarta",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Data chart berhasil diambil",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChartDataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Rentang, granularity atau timezone tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Kesalahan server internal",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chart/categories": {
+            "get": {
+                "security": [
+                    {
+                        "JWTtoken": []
+                    }
+                ],
+                "description": "Total transfer keluar yang sukses per kategori dari tanggal from sampai to (keduanya termasuk, default awal bulan ini sampai hari ini) pada timezone tz, urut dari yang terbesar.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chart"
+                ],
+                "summary": "Pengeluaran per kategori",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tanggal mulai (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone, contoh: Asia/Jakarta",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pengeluaran per kategori berhasil diambil",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ResponseData"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CategoryBreakdown"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Rentang atau timezone tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Kesalahan server internal",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chart/compare": {
+            "get": {
+                "security": [
+                    {
+                        "JWTtoken": []
+                    }
+                ],
+                "description": "Pemasukan, pengeluaran dan pengeluaran per kategori pada month (default bulan ini) dibandingkan dengan bulan sebelumnya, dihitung pada timezone tz. percentage null ketika bulan sebelumnya 0.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chart"
+                ],
+                "summary": "Perbandingan bulan ini dengan bulan sebelumnya",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bulan (YYYY-MM)",
+                        "name": "month",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone, contoh: Asia/Jakarta",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Perbandingan berhasil diambil",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ResponseData"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.MonthComparison"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bulan atau timezone tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Kesalahan server internal",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chart/counterparties": {
+            "get": {
+                "security": [
+                    {
+                        "JWTtoken": []
+                    }
+                ],
+                "description": "Pengguna yang paling banyak menerima transfer sukses dari pengguna dari tanggal from sampai to (keduanya termasuk, default awal bulan ini sampai hari ini) pada timezone tz, urut dari nominal terbesar.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chart"
+                ],
+                "summary": "Penerima transfer terbanyak",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tanggal mulai (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone, contoh: Asia/Jakarta",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah pengguna (default: 5, maksimal 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Penerima terbanyak berhasil diambil",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ResponseData"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.CounterpartyTotal"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Rentang, limit atau timezone tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Kesalahan server internal",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chart/{duration}": {
+            "get": {
+                "security": [
+                    {
+                        "JWTtoken": []
+                    }
+                ],
+                "description": "Mendapatkan data chart keuangan pengguna yang sudah diautentikasi (berdasarkan ID pengguna dari token JWT) dengan filter durasi tertentu. Hari ini dan bucket dihitung pada timezone tz (default APP_TIMEZONE).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chart"
+                ],
+                "summary": "Mendapatkan data chart berdasarkan durasi filter",
+                "parameters": [
+                    {
+                        "enum": [
+                            "seven_days",
+                            "five_weeks",
+                            "twelve_months"
+                        ],
+                        "type": "string",
+                        "description": "Durasi filter data chart",
+                        "name": "duration",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone, contoh: Asia/Jakarta",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Data chart berhasil diambil",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChartDataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Durasi atau timezone tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Kesalahan server internal",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/events/stream": {
+            "get": {
+                "security": [
+                    {
+                        "JWTtoken": []
+                    }
+                ],
+                "description": "Push balance changes and new transactions to all connected devices of authenticated user. Token can be sent as query because EventSource can't set header",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Stream balance and transaction events (Server-Sent Events)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "JWT token, used when Authorization header can't be set",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream of events",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/events/ws": {
+            "get": {
+                "security": [
+                    {
+                        "JWTtoken": []
+                    }
+                ],
+                "description": "Same events as /events/stream but delivered as websocket text message (JSON)",
+                "tags": [
+                    "events"
+                ],
+                "summary": "Stream balance and transaction events (WebSocket)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "JWT token, used when Authorization header can't be set",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching protocol",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/profile": {
+            "get": {
+                "security": [
+                    {
+                        "JWTtoken": []
+                    }
+                ],
+                "description": "Mengambil detail profil pengguna yang sedang login (berdasarkan ID dari token JWT).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Mendapatkan detail profil pengguna",
+                "responses": {
+                    "200": {
+                        "description": "Detail profil berhasil diambil",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ResponseData"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "Data": {
+                                            "$ref": "#/definitions/models.ProfileResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Profil pengguna tidak ditemukan",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotFoundResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "JWTtoken": []
+                    }
+                ],
+                "description": "Memperbarui nama lengkap, nomor telepon, email, dan/atau gambar profil pengguna yang sedang login.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Memperbarui detail profil pengguna",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nama lengkap pengguna",
+                        "name": "fullname",
                         "in": "formData",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Alamat email pengguna (opsional)",
-                        "name": "email",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "Gambar profil baru (format file)",
-                        "name": "profile_picture",
-                        "in": "formData"
+                        "description": "Nomor telepon pengguna",
+                        "name": "phone",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Alamat email pengguna (opsional)",
+                        "name": "email",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bahasa untuk email \u0026 notifikasi: 'id' atau 'en' (opsional)",
+                        "name": "language",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Gambar profil baru (format file)",
+                        "name": "profile_picture",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profil berhasil diperbarui",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Permintaan tidak valid (contoh: data form binding gagal, kesalahan upload file)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Kesalahan server internal",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/profile/avatar": {
+            "delete": {
+                "security": [
+                    {
+                        "JWTtoken": []
+                    }
+                ],
+                "description": "Menghapus gambar profil (avatar) pengguna yang sedang login.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Menghapus gambar profil",
+                "responses": {
+                    "200": {
+                        "description": "Gambar profil berhasil dihapus",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Kesalahan server internal saat menghapus avatar",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/receipt/verify/{code}": {
+            "get": {
+                "description": "Check that receipt code is issued by this app and show what the receipt prove, name is masked. No login is needed, so anyone given the receipt can check it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Verify receipt",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Receipt code, ex: TRF-20260101-00000123-K7Q2M4XW9D3FJ8HA",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Receipt is valid",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ResponseData"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "Data": {
+                                            "$ref": "#/definitions/models.ReceiptVerification"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Receipt is invalid or not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/topup": {
+            "post": {
+                "security": [
+                    {
+                        "JWTtoken": []
+                    }
+                ],
+                "description": "Create a new topup transaction with selected payment method",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TopUp"
+                ],
+                "summary": "Create topup transaction",
+                "parameters": [
+                    {
+                        "description": "Topup request payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TopUpRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseData"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/topup/methods": {
+            "get": {
+                "security": [
+                    {
+                        "JWTtoken": []
+                    }
+                ],
+                "description": "Retrieve list of supported payment methods for topup",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TopUp"
+                ],
+                "summary": "Get available payment methods",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseData"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/transaction/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get transfer history for authenticated user (excluding soft deleted). Send next_cursor / prev_cursor of the response as cursor to read the next / previous page, page is ignored when cursor is set",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Get user transaction history with pagination",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from next_cursor or prev_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created from date, inclusive (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created until date, inclusive (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "sent",
+                                "received",
+                                "topup",
+                                "withdrawal"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Transaction type, repeat for many type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "success",
+                                "pending",
+                                "failed"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Transaction status, repeat for many status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum amount",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum amount",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID of the other side of transfer",
+                        "name": "counterparty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search notes and counterparty name",
+                        "name": "q",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response with Transaction History Data",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseData"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid cursor or filter",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/transaction/history/all": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get complete transaction history including transfers and topups for authenticated user, newest first. Send next_cursor / prev_cursor of the response as cursor to read the next / previous page, page is ignored when cursor is set",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Get all user transaction history (transfer + topup)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from next_cursor or prev_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created from date, inclusive (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created until date, inclusive (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "sent",
+                                "received",
+                                "topup",
+                                "withdrawal"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Transaction type, repeat for many type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "success",
+                                "pending",
+                                "failed"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Transaction status, repeat for many status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum amount",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum amount",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID of the other side of transfer",
+                        "name": "counterparty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search notes and counterparty name",
+                        "name": "q",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response with Complete Transaction History Data",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseData"
+                        }
+                    },
+                    "204": {
+                        "description": "User has no transaction",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseData"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid cursor or filter",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/transaction/statement": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Opening balance, every success transfer \u0026 topup with running balance and closing balance of the period. Soft deleted transaction is included. CSV is always in english with plain number, PDF follow language of the request. With email=true statement is generated in background and sent as attachment to email of user (in language of user). PDF and emailed statement is built in memory so its period is at most 12 months, CSV download is streamed without limit",
+                "produces": [
+                    "text/csv",
+                    "application/pdf",
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Download or email account statement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date, inclusive (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "pdf"
+                        ],
+                        "type": "string",
+                        "description": "Statement format (default: pdf)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Send to email instead of download",
+                        "name": "email",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Statement file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "202": {
+                        "description": "Statement is being sent to email",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period or format, or PDF / email period longer than 12 months",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/transaction/topup/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get topup with payment method, fee, status timeline and receipt code. Only owner of the topup can see it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topup"
+                ],
+                "summary": "Get topup detail",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Topup ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ResponseData"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "Data": {
+                                            "$ref": "#/definitions/models.TransactionDetail"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Topup Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft delete topup for authenticated user, it can be restored from trash until retention period end",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topup"
+                ],
+                "summary": "Soft delete topup",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Topup ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "$ref": "#/definitions/models.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Topup is not owned by user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Topup Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/transaction/topup/{id}/category": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Category picked by user win over the default topup category, empty category reset it. Response is category of topup after the change",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "topup"
+                ],
+                "summary": "Set topup category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Topup ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Category",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ResponseData"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CategoryRequest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or category",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Topup not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/transaction/topup/{id}/receipt": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download receipt of topup as PDF or PNG, in language of the request. QR code on receipt lead to its verification",
+                "produces": [
+                    "application/pdf",
+                    "image/png"
+                ],
+                "tags": [
+                    "topup"
+                ],
+                "summary": "Download topup receipt",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Topup ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pdf",
+                            "png"
+                        ],
+                        "type": "string",
+                        "description": "Receipt format (default: pdf)",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Profil berhasil diperbarui",
+                        "description": "Receipt file",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Permintaan tidak valid (contoh: data form binding gagal, kesalahan upload file)",
+                        "description": "Invalid ID or format",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.UnauthorizedResponse"
                         }
                     },
+                    "404": {
+                        "description": "Topup Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotFoundResponse"
+                        }
+                    },
                     "500": {
-                        "description": "Kesalahan server internal",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.InternalErrorResponse"
                         }
@@ -721,14 +2598,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/profile/avatar": {
-            "delete": {
+        "/v1/transaction/topup/{id}/restore": {
+            "post": {
                 "security": [
                     {
-                        "JWTtoken": []
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Menghapus gambar profil (avatar) pengguna yang sedang login.",
+                "description": "Show topup hidden by user on history again, only while it is still in trash",
                 "consumes": [
                     "application/json"
                 ],
@@ -736,24 +2613,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "topup"
+                ],
+                "summary": "Restore topup",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Topup ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Menghapus gambar profil",
                 "responses": {
                     "200": {
-                        "description": "Gambar profil berhasil dihapus",
+                        "description": "Success Response",
                         "schema": {
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "401": {
-                        "description": "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.UnauthorizedResponse"
                         }
                     },
+                    "404": {
+                        "description": "Topup is not in trash",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotFoundResponse"
+                        }
+                    },
                     "500": {
-                        "description": "Kesalahan server internal saat menghapus avatar",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.InternalErrorResponse"
                         }
@@ -761,14 +2659,66 @@ const docTemplate = `{
                 }
             }
         },
-        "/topup": {
+        "/v1/transaction/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Transfer \u0026 topup hidden by user, last hidden first. Item can be restored until purge_at, after that it stay hidden for good",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Get trash",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response with Trash Data",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResponseData"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
                 "security": [
                     {
-                        "JWTtoken": []
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Create a new topup transaction with selected payment method",
+                "description": "Hide up to 100 transfer \u0026 topup at once, like DELETE of each one. Item which isn't user's or is already hidden is skipped",
                 "consumes": [
                     "application/json"
                 ],
@@ -776,29 +2726,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "TopUp"
+                    "transaction"
                 ],
-                "summary": "Create topup transaction",
+                "summary": "Hide many transaction",
                 "parameters": [
                     {
-                        "description": "Topup request payload",
-                        "name": "request",
+                        "description": "Transaction to hide",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.TopUpRequest"
+                            "$ref": "#/definitions/models.BulkTransactionRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "Success Response",
                         "schema": {
-                            "$ref": "#/definitions/models.ResponseData"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ResponseData"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "Data": {
+                                            "$ref": "#/definitions/models.BulkTransactionResult"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid body",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -806,26 +2768,26 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.InternalErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/topup/methods": {
-            "get": {
+        "/v1/transaction/trash/restore": {
+            "post": {
                 "security": [
                     {
-                        "JWTtoken": []
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve list of supported payment methods for topup",
+                "description": "Restore up to 100 transfer \u0026 topup at once. Item which isn't in trash of user is skipped",
                 "consumes": [
                     "application/json"
                 ],
@@ -833,39 +2795,68 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "TopUp"
+                    "transaction"
+                ],
+                "summary": "Restore many transaction",
+                "parameters": [
+                    {
+                        "description": "Transaction to restore",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BulkTransactionRequest"
+                        }
+                    }
                 ],
-                "summary": "Get available payment methods",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Success Response",
                         "schema": {
-                            "$ref": "#/definitions/models.ResponseData"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ResponseData"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "Data": {
+                                            "$ref": "#/definitions/models.BulkTransactionResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.InternalErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/transaction/history": {
+        "/v1/transaction/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get transaction history for authenticated user (excluding soft deleted) with pagination support",
+                "description": "Get transfer with both party, fee, status timeline and receipt code. Only sender and receiver can see it",
                 "consumes": [
                     "application/json"
                 ],
@@ -875,26 +2866,92 @@ const docTemplate = `{
                 "tags": [
                     "transaction"
                 ],
-                "summary": "Get user transaction history with pagination",
+                "summary": "Get transfer detail",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page number (default: 1)",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Response",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ResponseData"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "Data": {
+                                            "$ref": "#/definitions/models.TransactionDetail"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnauthorizedResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.InternalErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft delete transaction for authenticated user, it can be restored from trash until retention period end",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Soft delete transaction",
+                "parameters": [
                     {
                         "type": "integer",
-                        "description": "Items per page (default: 10)",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success Response with Transaction History Data",
+                        "description": "Success Response",
                         "schema": {
-                            "$ref": "#/definitions/models.ResponseData"
+                            "$ref": "#/definitions/models.Response"
                         }
                     },
                     "401": {
@@ -904,7 +2961,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Transaction History Not Found",
+                        "description": "Transaction Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.NotFoundResponse"
                         }
@@ -918,14 +2975,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/transaction/history/all": {
-            "get": {
+        "/v1/transaction/{id}/category": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get complete transaction history including transfers and topups for authenticated user",
+                "description": "Category picked by user win over any rule, empty category let rule decide again. Response is category of transfer after the change",
                 "consumes": [
                     "application/json"
                 ],
@@ -935,12 +2992,48 @@ const docTemplate = `{
                 "tags": [
                     "transaction"
                 ],
-                "summary": "Get all user transaction history (transfer + topup)",
+                "summary": "Set transfer category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Category",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CategoryRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Success Response with Complete Transaction History Data",
+                        "description": "Success Response",
                         "schema": {
-                            "$ref": "#/definitions/models.ResponseData"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ResponseData"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CategoryRequest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or category",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "401": {
@@ -950,7 +3043,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Transaction History Not Found",
+                        "description": "Transaction not found",
                         "schema": {
                             "$ref": "#/definitions/models.NotFoundResponse"
                         }
@@ -964,38 +3057,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/transaction/topup/{id}": {
-            "delete": {
+        "/v1/transaction/{id}/receipt": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Soft delete topup for authenticated user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Download receipt of transfer as PDF or PNG, in language of the request. QR code on receipt lead to its verification",
                 "produces": [
-                    "application/json"
+                    "application/pdf",
+                    "image/png"
                 ],
                 "tags": [
-                    "topup"
+                    "transaction"
                 ],
-                "summary": "Soft delete topup",
+                "summary": "Download transfer receipt",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Topup ID",
+                        "description": "Transaction ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "pdf",
+                            "png"
+                        ],
+                        "type": "string",
+                        "description": "Receipt format (default: pdf)",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success Response",
+                        "description": "Receipt file",
                         "schema": {
-                            "$ref": "#/definitions/models.Response"
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "401": {
@@ -1005,7 +3112,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Topup Not Found",
+                        "description": "Transaction Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.NotFoundResponse"
                         }
@@ -1019,14 +3126,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/transaction/{id}": {
-            "delete": {
+        "/v1/transaction/{id}/restore": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Soft delete transaction for authenticated user",
+                "description": "Show transfer hidden by user on history again, only while it is still in trash",
                 "consumes": [
                     "application/json"
                 ],
@@ -1036,7 +3143,7 @@ const docTemplate = `{
                 "tags": [
                     "transaction"
                 ],
-                "summary": "Soft delete transaction",
+                "summary": "Restore transfer",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1053,6 +3160,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Response"
                         }
                     },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -1060,7 +3173,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Transaction Not Found",
+                        "description": "Transaction is not in trash",
                         "schema": {
                             "$ref": "#/definitions/models.NotFoundResponse"
                         }
@@ -1074,14 +3187,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/transfer": {
+        "/v1/transfer": {
             "get": {
                 "security": [
                     {
                         "JWTtoken": []
                     }
                 ],
-                "description": "Mendapatkan daftar pengguna dengan opsi pencarian dan paginasi. Digunakan untuk memilih pengguna tujuan transfer.",
+                "description": "Mendapatkan daftar pengguna dengan opsi pencarian dan paginasi. Digunakan untuk memilih pengguna tujuan transfer. Kirim next_cursor / prev_cursor sebagai cursor untuk halaman berikutnya / sebelumnya, jika cursor diisi page diabaikan dan data berbentuk CursorProfileResponse (tanpa total).",
                 "consumes": [
                     "application/json"
                 ],
@@ -1099,6 +3212,12 @@ const docTemplate = `{
                         "name": "search",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "Cursor dari next_cursor atau prev_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Nomor halaman untuk paginasi (default: 1)",
@@ -1125,6 +3244,12 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Cursor tidak valid",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "401": {
                         "description": "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang",
                         "schema": {
@@ -1194,49 +3319,365 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.AuthRequest": {
+        }
+    },
+    "definitions": {
+        "models.AuthRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "is_pin_exist": {
+                    "type": "boolean"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BadRequestResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "error": {
+                    "type": "string",
+                    "example": "Example bad request error..."
+                },
+                "error_code": {
+                    "type": "string",
+                    "example": "VALIDATION_FAILED"
+                },
+                "is_success": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "models.Budget": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BudgetProgress": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "percentage": {
+                    "description": "spent of amount, 0-100+ with 2 decimal",
+                    "type": "number"
+                },
+                "remaining": {
+                    "description": "negative when spending is over the budget",
+                    "type": "integer"
+                },
+                "spent": {
+                    "type": "integer"
+                },
+                "threshold": {
+                    "description": "highest of BudgetThresholds reached, 0 when none",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BudgetRequest": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1500000
+                },
+                "category": {
+                    "type": "string",
+                    "enum": [
+                        "food",
+                        "transport",
+                        "shopping",
+                        "bills",
+                        "entertainment",
+                        "health",
+                        "education",
+                        "family",
+                        "other"
+                    ],
+                    "example": "food"
+                }
+            }
+        },
+        "models.BudgetsProgress": {
+            "type": "object",
+            "properties": {
+                "budgets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BudgetProgress"
+                    }
+                },
+                "month": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BulkTransactionRequest": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionRef"
+                    }
+                }
+            }
+        },
+        "models.BulkTransactionResult": {
+            "type": "object",
+            "properties": {
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionRef"
+                    }
+                },
+                "updated": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionRef"
+                    }
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "example": "food"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Makanan \u0026 Minuman"
+                },
+                "spending": {
+                    "description": "Spending category can be used by rule",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CategoryBreakdown": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategoryTotal"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CategoryChange": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "category": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string"
+                "current": {
+                    "type": "integer"
+                },
+                "difference": {
+                    "type": "integer"
+                },
+                "percentage": {
+                    "description": "nil when previous month is 0",
+                    "type": "number"
+                },
+                "previous": {
+                    "type": "integer"
                 }
             }
         },
-        "models.AuthResponse": {
+        "models.CategoryRequest": {
             "type": "object",
             "properties": {
-                "is_pin_exist": {
-                    "type": "boolean"
+                "category": {
+                    "type": "string",
+                    "enum": [
+                        "food",
+                        "transport",
+                        "shopping",
+                        "bills",
+                        "entertainment",
+                        "health",
+                        "education",
+                        "family",
+                        "other",
+                        "income",
+                        "topup"
+                    ],
+                    "example": "food"
+                }
+            }
+        },
+        "models.CategoryRule": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
                 },
-                "token": {
+                "created_at": {
+                    "type": "string"
+                },
+                "field": {
+                    "description": "notes or counterparty (name of receiver)",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "keyword": {
+                    "description": "lowercase, matched case insensitive as part of the field",
                     "type": "string"
                 }
             }
         },
-        "models.BadRequestResponse": {
+        "models.CategoryRuleRequest": {
             "type": "object",
+            "required": [
+                "category",
+                "field",
+                "keyword"
+            ],
             "properties": {
-                "code": {
-                    "type": "integer",
-                    "example": 400
+                "category": {
+                    "type": "string",
+                    "enum": [
+                        "food",
+                        "transport",
+                        "shopping",
+                        "bills",
+                        "entertainment",
+                        "health",
+                        "education",
+                        "family",
+                        "other"
+                    ],
+                    "example": "food"
                 },
-                "error": {
+                "field": {
                     "type": "string",
-                    "example": "Example bad request error..."
+                    "enum": [
+                        "notes",
+                        "counterparty"
+                    ],
+                    "example": "notes"
                 },
-                "is_success": {
-                    "type": "boolean",
-                    "example": false
+                "keyword": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "kopi"
+                }
+            }
+        },
+        "models.CategoryTotal": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "percentage": {
+                    "description": "share of amount from total spending, 0-100 with 2 decimal",
+                    "type": "number"
+                }
+            }
+        },
+        "models.Change": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "type": "integer"
+                },
+                "difference": {
+                    "type": "integer"
+                },
+                "percentage": {
+                    "description": "nil when previous month is 0",
+                    "type": "number"
+                },
+                "previous": {
+                    "type": "integer"
                 }
             }
         },
@@ -1281,6 +3722,10 @@ const docTemplate = `{
                         "type": "integer"
                     }
                 },
+                "granularity": {
+                    "description": "day, week or month, label of week is its monday",
+                    "type": "string"
+                },
                 "income_data": {
                     "type": "array",
                     "items": {
@@ -1292,6 +3737,9 @@ const docTemplate = `{
                     "items": {
                         "type": "string"
                     }
+                },
+                "timezone": {
+                    "type": "string"
                 }
             }
         },
@@ -1330,6 +3778,43 @@ const docTemplate = `{
                 }
             }
         },
+        "models.CounterpartyTotal": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "profile_picture": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DependencyStatus": {
+            "type": "object",
+            "properties": {
+                "detail": {},
+                "error": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "number",
+                    "example": 1.25
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "models.ErrorResponse": {
             "type": "object",
             "properties": {
@@ -1337,10 +3822,18 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 200
                 },
+                "details": {
+                    "type": "string",
+                    "example": "Detail of the error..."
+                },
                 "error": {
                     "type": "string",
                     "example": "Error message..."
                 },
+                "error_code": {
+                    "type": "string",
+                    "example": "INTERNAL_ERROR"
+                },
                 "is_success": {
                     "type": "boolean",
                     "example": true
@@ -1355,6 +3848,19 @@ const docTemplate = `{
                 }
             }
         },
+        "models.EventType": {
+            "type": "string",
+            "enum": [
+                "balance.updated",
+                "transaction.created",
+                "budget.alert"
+            ],
+            "x-enum-varnames": [
+                "EventBalanceUpdated",
+                "EventTransactionCreated",
+                "EventBudgetAlert"
+            ]
+        },
         "models.ForgotPasswordOrPINRequest": {
             "type": "object",
             "required": [
@@ -1366,6 +3872,21 @@ const docTemplate = `{
                 }
             }
         },
+        "models.HealthStatus": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.DependencyStatus"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "models.InternalErrorResponse": {
             "type": "object",
             "properties": {
@@ -1377,6 +3898,10 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Example Internal server error..."
                 },
+                "error_code": {
+                    "type": "string",
+                    "example": "INTERNAL_ERROR"
+                },
                 "is_success": {
                     "type": "boolean",
                     "example": false
@@ -1389,9 +3914,15 @@ const docTemplate = `{
                 "limit": {
                     "type": "integer"
                 },
+                "next_cursor": {
+                    "type": "string"
+                },
                 "page": {
                     "type": "integer"
                 },
+                "prev_cursor": {
+                    "type": "string"
+                },
                 "total": {
                     "type": "integer"
                 },
@@ -1406,6 +3937,32 @@ const docTemplate = `{
                 }
             }
         },
+        "models.MonthComparison": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategoryChange"
+                    }
+                },
+                "expense": {
+                    "$ref": "#/definitions/models.Change"
+                },
+                "income": {
+                    "$ref": "#/definitions/models.Change"
+                },
+                "month": {
+                    "type": "string"
+                },
+                "previous_month": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
         "models.NotFoundResponse": {
             "type": "object",
             "properties": {
@@ -1417,6 +3974,10 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Not Found"
                 },
+                "error_code": {
+                    "type": "string",
+                    "example": "NOT_FOUND"
+                },
                 "is_success": {
                     "type": "boolean",
                     "example": false
@@ -1435,6 +3996,9 @@ const docTemplate = `{
                 "fullname": {
                     "type": "string"
                 },
+                "language": {
+                    "type": "string"
+                },
                 "phone": {
                     "type": "string"
                 },
@@ -1449,6 +4013,41 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ReceiptVerification": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "fee": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "receiver": {
+                    "type": "string"
+                },
+                "reference_number": {
+                    "type": "string"
+                },
+                "sender": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
         "models.ResetPINRequest": {
             "type": "object",
             "required": [
@@ -1553,6 +4152,114 @@ const docTemplate = `{
                 }
             }
         },
+        "models.TransactionDetail": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "fee": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "receipt_code": {
+                    "description": "ReceiptCode is signed reference number, anyone can check it on receipt verify endpoint",
+                    "type": "string"
+                },
+                "receiver": {
+                    "$ref": "#/definitions/models.TransactionParty"
+                },
+                "reference_number": {
+                    "type": "string"
+                },
+                "sender": {
+                    "$ref": "#/definitions/models.TransactionParty"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "timeline": {
+                    "description": "oldest first, the last one is the current status",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionStatusEvent"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "transaction_type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TransactionParty": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "profile_picture": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "wallet_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TransactionRef": {
+            "type": "object",
+            "required": [
+                "id",
+                "kind"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 12
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "transfer",
+                        "topup"
+                    ],
+                    "example": "transfer"
+                }
+            }
+        },
+        "models.TransactionStatusEvent": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "models.TransferBody": {
             "type": "object",
             "required": [
@@ -1591,11 +4298,27 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Unauthorized : please login again..."
                 },
+                "error_code": {
+                    "type": "string",
+                    "example": "TOKEN_EXPIRED"
+                },
                 "is_success": {
                     "type": "boolean",
                     "example": false
                 }
             }
+        },
+        "models.UserEvent": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "data": {},
+                "type": {
+                    "$ref": "#/definitions/models.EventType"
+                }
+            }
         }
     },
     "securityDefinitions": {
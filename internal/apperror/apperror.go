@@ -0,0 +1,165 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Code is stable machine-readable error code, client must switch on this instead of message
+type Code string
+
+const (
+	BadRequest       Code = "BAD_REQUEST"
+	ValidationFailed Code = "VALIDATION_FAILED"
+	InvalidID        Code = "INVALID_ID"
+	CursorInvalid    Code = "CURSOR_INVALID"
+	FileTooLarge     Code = "FILE_TOO_LARGE"
+	FileTypeInvalid  Code = "FILE_TYPE_INVALID"
+
+	Unauthorized   Code = "UNAUTHORIZED"
+	TokenMissing   Code = "TOKEN_MISSING"
+	TokenMalformed Code = "TOKEN_MALFORMED"
+	TokenInvalid   Code = "TOKEN_INVALID"
+	TokenExpired   Code = "TOKEN_EXPIRED"
+	TokenRevoked   Code = "TOKEN_REVOKED"
+
+	InvalidCredentials     Code = "INVALID_CREDENTIALS"
+	EmailInvalid           Code = "EMAIL_INVALID"
+	EmailAlreadyRegistered Code = "EMAIL_ALREADY_REGISTERED"
+	PasswordWeak           Code = "PASSWORD_WEAK"
+	PasswordInvalid        Code = "PASSWORD_INVALID"
+	PINInvalid             Code = "PIN_INVALID"
+	PINNotSet              Code = "PIN_NOT_SET"
+	ResetTokenInvalid      Code = "RESET_TOKEN_INVALID"
+
+	InsufficientBalance Code = "INSUFFICIENT_BALANCE"
+	SelfTransfer        Code = "SELF_TRANSFER"
+
+	Forbidden            Code = "FORBIDDEN"
+	NotFound             Code = "NOT_FOUND"
+	RouteNotFound        Code = "ROUTE_NOT_FOUND"
+	UserNotFound         Code = "USER_NOT_FOUND"
+	ProfileNotFound      Code = "PROFILE_NOT_FOUND"
+	WalletNotFound       Code = "WALLET_NOT_FOUND"
+	TransactionNotFound  Code = "TRANSACTION_NOT_FOUND"
+	TopUpNotFound        Code = "TOPUP_NOT_FOUND"
+	MailNotFound         Code = "MAIL_NOT_FOUND"
+	CategoryRuleNotFound Code = "CATEGORY_RULE_NOT_FOUND"
+	BudgetNotFound       Code = "BUDGET_NOT_FOUND"
+	ReceiptInvalid       Code = "RECEIPT_INVALID"
+	APIVersionGone       Code = "API_VERSION_GONE"
+
+	Internal Code = "INTERNAL_ERROR"
+)
+
+var statuses = map[Code]int{
+	BadRequest:       http.StatusBadRequest,
+	ValidationFailed: http.StatusBadRequest,
+	InvalidID:        http.StatusBadRequest,
+	CursorInvalid:    http.StatusBadRequest,
+	FileTooLarge:     http.StatusBadRequest,
+	FileTypeInvalid:  http.StatusBadRequest,
+
+	Unauthorized:   http.StatusUnauthorized,
+	TokenMissing:   http.StatusUnauthorized,
+	TokenMalformed: http.StatusUnauthorized,
+	TokenInvalid:   http.StatusUnauthorized,
+	TokenExpired:   http.StatusUnauthorized,
+	TokenRevoked:   http.StatusUnauthorized,
+
+	// wrong password / pin is not authentication failure of the token,
+	// so it use 400 and client doesn't logout user on it
+	InvalidCredentials:     http.StatusBadRequest,
+	EmailInvalid:           http.StatusBadRequest,
+	EmailAlreadyRegistered: http.StatusConflict,
+	PasswordWeak:           http.StatusBadRequest,
+	PasswordInvalid:        http.StatusBadRequest,
+	PINInvalid:             http.StatusBadRequest,
+	PINNotSet:              http.StatusBadRequest,
+	ResetTokenInvalid:      http.StatusBadRequest,
+
+	InsufficientBalance: http.StatusBadRequest,
+	SelfTransfer:        http.StatusBadRequest,
+
+	Forbidden:            http.StatusForbidden,
+	NotFound:             http.StatusNotFound,
+	RouteNotFound:        http.StatusNotFound,
+	UserNotFound:         http.StatusNotFound,
+	ProfileNotFound:      http.StatusNotFound,
+	WalletNotFound:       http.StatusNotFound,
+	TransactionNotFound:  http.StatusNotFound,
+	TopUpNotFound:        http.StatusNotFound,
+	MailNotFound:         http.StatusNotFound,
+	CategoryRuleNotFound: http.StatusNotFound,
+	BudgetNotFound:       http.StatusNotFound,
+	ReceiptInvalid:       http.StatusNotFound,
+	APIVersionGone:       http.StatusGone,
+
+	Internal: http.StatusInternalServerError,
+}
+
+// Status return http status of code, unknown code is treated as internal error
+func (c Code) Status() int {
+	if s, ok := statuses[c]; ok {
+		return s
+	}
+	return http.StatusInternalServerError
+}
+
+// Error is error returned to client
+// Detail is shown to client (ex: which field is invalid), Err is the cause and only logged
+type Error struct {
+	Code   Code
+	Detail string
+	Err    error
+}
+
+func New(code Code) *Error {
+	return &Error{Code: code}
+}
+
+// Wrap keep the cause of error so it is logged by access log
+func Wrap(code Code, err error) *Error {
+	return &Error{Code: code, Err: err}
+}
+
+// Validation is error of binding/validating request body, message of validator is shown as detail
+func Validation(err error) *Error {
+	return &Error{Code: ValidationFailed, Detail: err.Error()}
+}
+
+// WithDetail return copy of error with detail, so shared error value is never mutated
+func (e *Error) WithDetail(detail string) *Error {
+	cp := *e
+	cp.Detail = detail
+	return &cp
+}
+
+func (e *Error) Status() int {
+	return e.Code.Status()
+}
+
+func (e *Error) Error() string {
+	msg := string(e.Code)
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// From convert any error to *Error, error which isn't *Error become internal error
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Wrap(Internal, err)
+}
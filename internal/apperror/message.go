@@ -0,0 +1,8 @@
+package apperror
+
+import "github.com/Belalai-E-Wallet-Backend/internal/i18n"
+
+// Message return localized message of code, message is kept in i18n catalog with key "error.<CODE>"
+func (c Code) Message(lang string) string {
+	return i18n.Translate(lang, "error."+string(c))
+}
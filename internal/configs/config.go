@@ -0,0 +1,425 @@
+package configs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Config is all configuration of this project
+// loaded once on startup, then passed down to router, handler, and repository
+type Config struct {
+	App     AppConfig
+	Server  ServerConfig
+	DB      DBConfig
+	Redis   RedisConfig
+	JWT     JWTConfig
+	Mail    MailConfig
+	Receipt ReceiptConfig
+	Trash   TrashConfig
+	Trace   TraceConfig
+	Log     LogConfig
+	Legacy  LegacyConfig
+}
+
+type AppConfig struct {
+	Env         string
+	Port        string
+	FrontendURL string
+	// IANA timezone used when user doesn't send one (ex: chart bucket)
+	Timezone string
+}
+
+// ServerConfig is timeout of HTTP server
+type ServerConfig struct {
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// max time to drain in-flight request & background task on shutdown
+	ShutdownTimeout time.Duration
+	// wait before stop accepting request, while readyz report draining
+	DrainDelay time.Duration
+}
+
+// LegacyConfig control routes still served on root path (without /v1) for old mobile app
+// after SunsetAt, legacy routes response 410 Gone
+type LegacyConfig struct {
+	Enabled      bool
+	DeprecatedAt time.Time
+	SunsetAt     time.Time
+}
+
+type DBConfig struct {
+	User string
+	Pass string
+	Host string
+	Port string
+	Name string
+	// apply pending migration on startup, safe with many replica (advisory lock)
+	AutoMigrate bool
+}
+
+type RedisConfig struct {
+	User string
+	Pass string
+	Host string
+	Port string
+}
+
+type JWTConfig struct {
+	Secret string
+	Issuer string
+	TTL    time.Duration
+}
+
+// ReceiptConfig is signing of shareable receipt
+type ReceiptConfig struct {
+	// key to sign receipt code, default to JWT secret
+	Secret string
+	// public page to verify receipt, code is appended (ex: https://belalai.id/receipt -> https://belalai.id/receipt/CODE)
+	// empty put only the code on QR code
+	VerifyURL string
+}
+
+// TrashConfig is how long hidden transaction can be restored
+type TrashConfig struct {
+	Retention time.Duration
+	// how often expired trash is purged
+	PurgeInterval time.Duration
+}
+
+type MailConfig struct {
+	// smtp, file or memory
+	Transport string
+	// directory for file transport
+	Dir      string
+	SMTP     SMTPConfig
+	Branding BrandingConfig
+}
+
+type SMTPConfig struct {
+	Host string
+	Port int
+	User string
+	Pass string
+	From string
+}
+
+type LogConfig struct {
+	// debug, info, warn or error
+	Level string
+	// json or text
+	Format string
+}
+
+type TraceConfig struct {
+	ServiceName string
+	// none, stdout or otlp
+	Exporter string
+	// OTLP/HTTP collector url, ex: http://localhost:4318
+	Endpoint    string
+	SampleRatio float64
+}
+
+type BrandingConfig struct {
+	AppName      string
+	LogoURL      string
+	PrimaryColor string
+	SupportEmail string
+}
+
+// LoadConfig read config from env
+// env file is loaded first (CONFIG_FILE or .env), but never override env already set
+func LoadConfig() (*Config, error) {
+	if err := loadEnvFile(); err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	cfg := &Config{
+		App: AppConfig{
+			Env:         getEnv("APP_ENV", "development"),
+			Port:        getEnv("APP_PORT", "2409"),
+			FrontendURL: os.Getenv("FRONTEND_URL"),
+			Timezone:    getEnv("APP_TIMEZONE", "Asia/Jakarta"),
+		},
+		Server: ServerConfig{
+			ReadTimeout:       getEnvDuration("HTTP_READ_TIMEOUT", 15*time.Second, &errs),
+			ReadHeaderTimeout: getEnvDuration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second, &errs),
+			WriteTimeout:      getEnvDuration("HTTP_WRITE_TIMEOUT", 30*time.Second, &errs),
+			IdleTimeout:       getEnvDuration("HTTP_IDLE_TIMEOUT", 60*time.Second, &errs),
+			ShutdownTimeout:   getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second, &errs),
+			DrainDelay:        getEnvDuration("SHUTDOWN_DRAIN_DELAY", 0, &errs),
+		},
+		DB: loadDBConfig(&errs),
+		Redis: RedisConfig{
+			User: os.Getenv("RDB_USER"),
+			Pass: os.Getenv("RDB_PWD"),
+			Host: os.Getenv("RDB_HOST"),
+			Port: getEnv("RDB_PORT", "6379"),
+		},
+		JWT: JWTConfig{
+			Secret: os.Getenv("JWT_SECRET"),
+			Issuer: os.Getenv("JWT_ISSUER"),
+			TTL:    getEnvDuration("JWT_TTL", 30*time.Minute, &errs),
+		},
+		Mail: MailConfig{
+			Transport: strings.ToLower(getEnv("MAIL_TRANSPORT", "smtp")),
+			Dir:       getEnv("MAIL_DIR", "tmp/mails"),
+			SMTP: SMTPConfig{
+				Host: os.Getenv("SMTP_HOST"),
+				Port: getEnvInt("SMTP_PORT", 587, &errs),
+				User: os.Getenv("SMTP_USER"),
+				Pass: os.Getenv("SMTP_PASS"),
+				From: os.Getenv("SMTP_FROM"),
+			},
+			Branding: BrandingConfig{
+				AppName:      getEnv("APP_NAME", "Belalai E-Wallet"),
+				LogoURL:      os.Getenv("MAIL_LOGO_URL"),
+				PrimaryColor: getEnv("MAIL_PRIMARY_COLOR", "#4F46E5"),
+				SupportEmail: os.Getenv("MAIL_SUPPORT_EMAIL"),
+			},
+		},
+		Receipt: ReceiptConfig{
+			Secret:    os.Getenv("RECEIPT_SECRET"),
+			VerifyURL: os.Getenv("RECEIPT_VERIFY_URL"),
+		},
+		Trash: TrashConfig{
+			Retention:     getEnvDuration("TRASH_RETENTION", 30*24*time.Hour, &errs),
+			PurgeInterval: getEnvDuration("TRASH_PURGE_INTERVAL", time.Hour, &errs),
+		},
+		Log: LogConfig{
+			Level:  strings.ToLower(getEnv("LOG_LEVEL", "info")),
+			Format: strings.ToLower(getEnv("LOG_FORMAT", "json")),
+		},
+		Trace: TraceConfig{
+			ServiceName: getEnv("OTEL_SERVICE_NAME", "belalai-e-wallet"),
+			Exporter:    strings.ToLower(getEnv("TRACE_EXPORTER", "none")),
+			Endpoint:    os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+			SampleRatio: getEnvFloat("TRACE_SAMPLE_RATIO", 1, &errs),
+		},
+		Legacy: LegacyConfig{
+			Enabled:      getEnvBool("LEGACY_ROUTES", true, &errs),
+			DeprecatedAt: getEnvDate("LEGACY_DEPRECATED_AT", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), &errs),
+			SunsetAt:     getEnvDate("LEGACY_SUNSET_AT", time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC), &errs),
+		},
+	}
+	if cfg.Receipt.Secret == "" {
+		cfg.Receipt.Secret = cfg.JWT.Secret
+	}
+
+	if err := errors.Join(append(errs, cfg.Validate())...); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadDBConfig read only database config, for command which doesn't start the server (ex: migrate)
+func LoadDBConfig() (DBConfig, error) {
+	if err := loadEnvFile(); err != nil {
+		return DBConfig{}, err
+	}
+	var errs []error
+	cfg := loadDBConfig(&errs)
+	if err := errors.Join(append(errs, cfg.Validate())...); err != nil {
+		return DBConfig{}, err
+	}
+	return cfg, nil
+}
+
+// env file is CONFIG_FILE or .env, missing file is ignored
+func loadEnvFile() error {
+	envFile := os.Getenv("CONFIG_FILE")
+	if envFile == "" {
+		envFile = ".env"
+	}
+	if err := godotenv.Load(envFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("load config file %s: %w", envFile, err)
+	}
+	return nil
+}
+
+func loadDBConfig(errs *[]error) DBConfig {
+	return DBConfig{
+		User:        os.Getenv("DBUSER"),
+		Pass:        os.Getenv("DBPASS"),
+		Host:        os.Getenv("DBHOST"),
+		Port:        getEnv("DBPORT", "5432"),
+		Name:        os.Getenv("DBNAME"),
+		AutoMigrate: getEnvBool("DB_AUTO_MIGRATE", false, errs),
+	}
+}
+
+// Validate check required config, all problem is reported at once
+func (c *Config) Validate() error {
+	var errs []error
+	required := func(value, env string) {
+		if strings.TrimSpace(value) == "" {
+			errs = append(errs, fmt.Errorf("%s is required", env))
+		}
+	}
+
+	required(c.App.Port, "APP_PORT")
+	if _, err := time.LoadLocation(c.App.Timezone); err != nil {
+		errs = append(errs, fmt.Errorf("APP_TIMEZONE %q is invalid: %w", c.App.Timezone, err))
+	}
+	if err := c.DB.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	required(c.Redis.Host, "RDB_HOST")
+	required(c.JWT.Secret, "JWT_SECRET")
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("SHUTDOWN_TIMEOUT must be greater than 0"))
+	}
+	if c.JWT.TTL <= 0 {
+		errs = append(errs, errors.New("JWT_TTL must be greater than 0"))
+	}
+	if c.Trash.Retention <= 0 {
+		errs = append(errs, errors.New("TRASH_RETENTION must be greater than 0"))
+	}
+	if c.Trash.PurgeInterval <= 0 {
+		errs = append(errs, errors.New("TRASH_PURGE_INTERVAL must be greater than 0"))
+	}
+
+	switch c.Mail.Transport {
+	case "smtp":
+		required(c.Mail.SMTP.Host, "SMTP_HOST")
+		required(c.Mail.SMTP.User, "SMTP_USER")
+		required(c.Mail.SMTP.Pass, "SMTP_PASS")
+		required(c.Mail.SMTP.From, "SMTP_FROM")
+	case "file":
+		required(c.Mail.Dir, "MAIL_DIR")
+	case "memory":
+	default:
+		errs = append(errs, fmt.Errorf("MAIL_TRANSPORT %q is invalid, use smtp, file or memory", c.Mail.Transport))
+	}
+	// captured mail isn't delivered and is readable by anyone on /dev/mails
+	if c.IsProduction() && c.Mail.Transport != "smtp" {
+		errs = append(errs, fmt.Errorf("MAIL_TRANSPORT %q isn't allowed on production, use smtp", c.Mail.Transport))
+	}
+
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("LOG_LEVEL %q is invalid, use debug, info, warn or error", c.Log.Level))
+	}
+	if c.Log.Format != "json" && c.Log.Format != "text" {
+		errs = append(errs, fmt.Errorf("LOG_FORMAT %q is invalid, use json or text", c.Log.Format))
+	}
+
+	switch c.Trace.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		errs = append(errs, fmt.Errorf("TRACE_EXPORTER %q is invalid, use none, stdout or otlp", c.Trace.Exporter))
+	}
+	if c.Trace.SampleRatio < 0 || c.Trace.SampleRatio > 1 {
+		errs = append(errs, errors.New("TRACE_SAMPLE_RATIO must be between 0 and 1"))
+	}
+
+	if c.Legacy.Enabled && !c.Legacy.SunsetAt.After(c.Legacy.DeprecatedAt) {
+		errs = append(errs, errors.New("LEGACY_SUNSET_AT must be after LEGACY_DEPRECATED_AT"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (d DBConfig) Validate() error {
+	var errs []error
+	required := func(value, env string) {
+		if strings.TrimSpace(value) == "" {
+			errs = append(errs, fmt.Errorf("%s is required", env))
+		}
+	}
+	required(d.User, "DBUSER")
+	required(d.Host, "DBHOST")
+	required(d.Name, "DBNAME")
+	return errors.Join(errs...)
+}
+
+func (c *Config) IsProduction() bool {
+	return c.App.Env == "production"
+}
+
+// Addr is listen address of HTTP server
+func (a AppConfig) Addr() string {
+	return ":" + a.Port
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int, errs *[]error) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s must be a number, got %q", key, value))
+		return fallback
+	}
+	return number
+}
+
+func getEnvFloat(key string, fallback float64, errs *[]error) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s must be a number, got %q", key, value))
+		return fallback
+	}
+	return number
+}
+
+func getEnvDuration(key string, fallback time.Duration, errs *[]error) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s must be a duration (ex: 30m, 1h), got %q", key, value))
+		return fallback
+	}
+	return duration
+}
+
+func getEnvBool(key string, fallback bool, errs *[]error) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s must be true or false, got %q", key, value))
+		return fallback
+	}
+	return b
+}
+
+// date is in UTC with format YYYY-MM-DD
+func getEnvDate(key string, fallback time.Time, errs *[]error) time.Time {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s must be a date (ex: 2026-12-31), got %q", key, value))
+		return fallback
+	}
+	return date
+}
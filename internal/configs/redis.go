@@ -2,21 +2,14 @@ package configs
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/redis/go-redis/v9"
 )
 
-func InitRedis() *redis.Client {
-	// data redish on .env
-	RdbUser := os.Getenv("RDB_USER")
-	RdbPass := os.Getenv("RDB_PWD")
-	RdbHost := os.Getenv("RDB_HOST")
-	RdbPort := os.Getenv("RDB_PORT")
-
+func InitRedis(cfg RedisConfig) *redis.Client {
 	return redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", RdbHost, RdbPort),
-		Username: RdbUser,
-		Password: RdbPass,
+		Addr:     fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
+		Username: cfg.User,
+		Password: cfg.Pass,
 	})
 }
@@ -1,15 +1,19 @@
 package handler
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
-	"os"
-	"regexp"
 	"strconv"
-	"strings"
 	"time"
 
+	"github.com/Belalai-E-Wallet-Backend/internal/apperror"
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+	"github.com/Belalai-E-Wallet-Backend/internal/i18n"
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
+	"github.com/Belalai-E-Wallet-Backend/internal/metrics"
 	"github.com/Belalai-E-Wallet-Backend/internal/models"
 	"github.com/Belalai-E-Wallet-Backend/internal/repository"
 	"github.com/Belalai-E-Wallet-Backend/internal/utils"
@@ -18,16 +22,19 @@ import (
 )
 
 type AuthHandler struct {
-	ar *repository.AuthRepository
+	ar     repository.AuthRepo
+	mailer utils.Mailer
+	bg     *utils.Background
+	cfg    *configs.Config
 }
 
-func NewAuthHandler(ar *repository.AuthRepository) *AuthHandler {
-	return &AuthHandler{ar: ar}
+func NewAuthHandler(ar repository.AuthRepo, mailer utils.Mailer, bg *utils.Background, cfg *configs.Config) *AuthHandler {
+	return &AuthHandler{ar: ar, mailer: mailer, bg: bg, cfg: cfg}
 }
 
 // Login
 // @tags 				login
-// @router 	 		/auth 	[POST]
+// @router 	 		/v1/auth 	[POST]
 // @Summary 		Login registered user
 // @Description login using email and password and return as response with JWT token
 // @Param 			body		body	 models.AuthRequest  	true 		"Input email and password"
@@ -39,49 +46,19 @@ func NewAuthHandler(ar *repository.AuthRepository) *AuthHandler {
 func (a *AuthHandler) Login(ctx *gin.Context) {
 	var body models.AuthRequest
 	if err := ctx.ShouldBind(&body); err != nil {
-		// check if failed binding bcs input not match with model require
-		log.Println("error when binding \ncause", err)
-		if strings.Contains(err.Error(), "required") {
-			ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Response: models.Response{
-					IsSuccess: false,
-					Code:      400,
-				},
-				Err: "Email or password cannot be empty",
-			})
-			return
-		}
-		// else binding error because server, log the error
-		log.Println("Internal Server Error.\nCause: ", err.Error())
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      500,
-			},
-			Err: "internal server error",
-		})
+		// input not match with model require
+		ctx.Error(apperror.Validation(err))
 		return
 	}
 	// get userdata and validate user
 	user, err := a.ar.GetEmail(ctx.Request.Context(), body.Email)
 	if err != nil {
-		if strings.Contains(err.Error(), "user not found") {
-			ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Response: models.Response{
-					IsSuccess: false,
-					Code:      400,
-				},
-				Err: "Email or Password is incorrect",
-			})
+		// unknown email get same error as wrong password
+		if errors.Is(err, repository.ErrUserNotFound) {
+			ctx.Error(apperror.New(apperror.InvalidCredentials))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      500,
-			},
-			Err: "internal server error",
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 	// check if pin is exist
@@ -98,51 +75,31 @@ func (a *AuthHandler) Login(ctx *gin.Context) {
 	hc := pkg.NewHashConfig()
 	isMatched, err := hc.CompareHashAndPassword(body.Password, user.Password)
 	if err != nil {
-		log.Println("Internal Server Error.\nCause: ", err.Error())
-		re := regexp.MustCompile("hash|crypto|argon2id|format")
-		if re.Match([]byte(err.Error())) {
-			log.Println("Error during Hashing")
-		}
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      500,
-			},
-			Err: "internal server error",
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 	// if not match sen https status as response
 	if !isMatched {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      400,
-			},
-			Err: "Email or Password is incorrect",
-		})
+		ctx.Error(apperror.New(apperror.InvalidCredentials))
 		return
 	}
 	// If match, generate jwt token and send as response
-	claim := pkg.NewJWTClaims(user.ID, "user")
-	jwtToken, err := claim.GenToken()
+	claim := pkg.NewJWTClaims(user.ID, "user", a.cfg.JWT.Issuer, a.cfg.JWT.TTL)
+	jwtToken, err := claim.GenToken(a.cfg.JWT.Secret)
 	if err != nil {
-		log.Println("Internal Server Error.\nCause: ", err.Error())
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      500,
-			},
-			Err: "internal server error",
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
+	// user who hasn't picked language has it removed, failed only make message follow Accept-Language instead of profile preference
+	if err := a.ar.SaveLanguage(ctx.Request.Context(), user.ID, user.Language); err != nil {
+		logger.FromContext(ctx).Warn("Failed save user language", "err", err)
+	}
 	// return token as response success
 	ctx.JSON(http.StatusOK, models.ResponseData{
 		Response: models.Response{
 			IsSuccess: true,
 			Code:      http.StatusOK,
-			Msg:       "login successfully",
+			Msg:       i18n.T(ctx, i18n.MsgLoginSuccess),
 		},
 		Data: models.AuthResponse{
 			Token:      jwtToken,
@@ -152,13 +109,15 @@ func (a *AuthHandler) Login(ctx *gin.Context) {
 }
 
 // Register
-// @Tags				/auth/register [POST]
+// @Tags				register
+// @Router			/v1/auth/register [POST]
 // @Summary 		Register new user
 // @Description	Register new user with input email & password
 // @Param				body		body 		 models.AuthRequest 	true		"Input email and password new user"
 // @accept			json
 // @produce			json
 // @failure 		400			{object} 	models.BadRequestResponse "Bad Request"
+// @failure 		409			{object} 	models.ErrorResponse "EMAIL_ALREADY_REGISTERED"
 // @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
 // @success			200			{object}  models.Response
 func (a *AuthHandler) Register(ctx *gin.Context) {
@@ -166,25 +125,13 @@ func (a *AuthHandler) Register(ctx *gin.Context) {
 
 	// Binding data and show if there is error when binding data
 	if err := ctx.ShouldBind(&body); err != nil {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      500,
-			},
-			Err: "Failed binding data ...",
-		})
+		ctx.Error(apperror.Validation(err))
 		return
 	}
 
 	// validate user register
 	if err := utils.RegisterValidation(body); err != nil {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      400,
-			},
-			Err: err.Error(),
-		})
+		ctx.Error(err)
 		return
 	} else {
 		// hash new password
@@ -192,59 +139,50 @@ func (a *AuthHandler) Register(ctx *gin.Context) {
 		hc.UseRecommended()
 		hash, err := hc.GenHash(body.Password)
 		if err != nil {
-			log.Println("Failed hash new password ...", err)
-			ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Response: models.Response{
-					IsSuccess: false,
-					Code:      500,
-				},
-				Err: err.Error(),
-			})
+			ctx.Error(apperror.Wrap(apperror.Internal, err))
 			return
 		}
 		// if inputs is already valid format,
 		// input and check if the email already registered
+		// new user doesn't have language preference yet, welcome mail use language of the device
+		lang := i18n.FromContext(ctx)
 		user := models.User{
 			Email:    body.Email,
 			Password: hash,
 		}
 		if err := a.ar.CreateAccount(ctx.Request.Context(), &user); err != nil {
-			log.Println("error cause: ", err)
-			ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Response: models.Response{
-					IsSuccess: false,
-					Code:      400,
-				},
-				Err: err.Error(),
-			})
+			if errors.Is(err, repository.ErrEmailRegistered) {
+				ctx.Error(apperror.New(apperror.EmailAlreadyRegistered))
+				return
+			}
+			ctx.Error(apperror.Wrap(apperror.Internal, err))
 			return
 		}
 
-		go func() {
-			err := utils.Send(utils.SendOptions{
-				To:         []string{body.Email},
-				Subject:    "Welcome to Russel Pay!",
-				Body:       fmt.Sprintf("<h2>Hello %s!</h2><p>Terima kasih sudah mendaftar di Russel Pay.</p>", body.Email),
-				BodyIsHTML: true,
+		a.bg.Go(ctx.Request.Context(), func(c context.Context) {
+			err := utils.SendTemplate(c, a.mailer, body.Email, utils.MailWelcome, lang, utils.MailData{
+				Brand: a.cfg.Mail.Branding,
+				Name:  body.Email,
+				Data:  map[string]any{"Link": a.cfg.App.FrontendURL},
 			})
 			if err != nil {
-				log.Println("Failed to send registration email:", err)
+				logger.FromContext(c).Error("Failed to send registration email", "err", err)
 			} else {
-				log.Printf("Email registration sent to %s\n", body.Email)
+				logger.FromContext(c).Info("Email registration sent", "email", body.Email)
 			}
-		}()
+		})
 
 		ctx.JSON(http.StatusOK, models.Response{
 			IsSuccess: true,
 			Code:      200,
-			Msg:       "user register successfully",
+			Msg:       i18n.T(ctx, i18n.MsgRegisterSuccess),
 		})
 	}
 }
 
 // ChangePassword
 // @Tags        auth
-// @Router      /auth/change-password [PATCH]
+// @Router      /v1/auth/change-password [PATCH]
 // @Summary     Change current user password
 // @Description Change the current user password by providing old password and new password
 // @Accept      json
@@ -258,92 +196,53 @@ func (a *AuthHandler) Register(ctx *gin.Context) {
 func (a *AuthHandler) ChangePassword(ctx *gin.Context) {
 	userId, err := utils.GetUserFromCtx(ctx)
 	if err != nil {
-		log.Println("error cause: ", err)
-		ctx.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusUnauthorized,
-			},
-			Err: err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
 	var body models.ChangePasswordRequest
 	if err := ctx.ShouldBind(&body); err != nil {
-		log.Println("error cause: ", err)
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusBadRequest,
-			},
-			Err: err.Error(),
-		})
+		ctx.Error(apperror.Validation(err))
 		return
 	}
 
 	pwdFromDB, err := a.ar.VerifyPassword(ctx, userId)
 	if err != nil {
-		log.Println("error cause: ", err)
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: err.Error(),
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	hashConfig := pkg.NewHashConfig()
 	ok, _ := hashConfig.CompareHashAndPassword(body.OldPassword, pwdFromDB)
 	if !ok {
-		ctx.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusUnauthorized,
-			},
-			Err: "invalid old password",
-		})
+		ctx.Error(apperror.New(apperror.PasswordInvalid))
 		return
 	}
 
 	hashConfig.UseRecommended()
 	hashedPwd, err := hashConfig.GenHash(body.NewPassword)
 	if err != nil {
-		log.Println("error cause: ", err)
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: err.Error(),
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	if err := a.ar.UpdatePassword(ctx, userId, hashedPwd); err != nil {
-		log.Println("error cause: ", err)
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: err.Error(),
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
+	a.sendSecurityAlert(ctx, userId, "password_changed")
+
 	ctx.JSON(http.StatusOK, models.Response{
 		IsSuccess: true,
 		Code:      http.StatusOK,
-		Msg:       "Password changed successfully",
+		Msg:       i18n.T(ctx, i18n.MsgPasswordChanged),
 	})
 }
 
 // ChangePIN
 // @Tags        auth
-// @Router      /auth/change-pin [PATCH]
+// @Router      /v1/auth/change-pin [PATCH]
 // @Summary     Change current user PIN
 // @Description Change the current user PIN by providing old PIN and new PIN (min 6 characters)
 // @Accept      json
@@ -357,92 +256,58 @@ func (a *AuthHandler) ChangePassword(ctx *gin.Context) {
 func (a *AuthHandler) ChangePIN(ctx *gin.Context) {
 	userId, err := utils.GetUserFromCtx(ctx)
 	if err != nil {
-		log.Println("error cause: ", err)
-		ctx.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusUnauthorized,
-			},
-			Err: err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
 	var body models.ChangePINRequest
 	if err := ctx.ShouldBind(&body); err != nil {
-		log.Println("error cause: ", err)
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusBadRequest,
-			},
-			Err: err.Error(),
-		})
+		ctx.Error(apperror.Validation(err))
 		return
 	}
 
 	pinDB, err := a.ar.VerifyPIN(ctx, userId)
 	if err != nil {
-		log.Println("error cause: ", err)
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: err.Error(),
-		})
+		if errors.Is(err, repository.ErrPINNotSet) {
+			ctx.Error(apperror.New(apperror.PINNotSet))
+			return
+		}
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	hashConfig := pkg.NewHashConfig()
 	ok, _ := hashConfig.CompareHashAndPassword(body.OldPIN, pinDB)
 	if !ok {
-		ctx.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusUnauthorized,
-			},
-			Err: "invalid old pin",
-		})
+		metrics.ObservePINFailure(metrics.PINSourceChange)
+		ctx.Error(apperror.New(apperror.PINInvalid))
 		return
 	}
 
 	hashConfig.UseRecommended()
 	hashedPin, err := hashConfig.GenHash(body.NewPIN)
 	if err != nil {
-		log.Println("error cause: ", err)
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: err.Error(),
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	if err := a.ar.UpdatePIN(ctx, userId, hashedPin); err != nil {
-		log.Println("error cause: ", err)
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: err.Error(),
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
+	a.sendSecurityAlert(ctx, userId, "pin_changed")
+
 	ctx.JSON(http.StatusOK, models.Response{
 		IsSuccess: true,
 		Code:      http.StatusOK,
-		Msg:       "PIN changed successfully",
+		Msg:       i18n.T(ctx, i18n.MsgPINChanged),
 	})
 }
 
 // UpdatePIN
 // @Tags        auth
-// @Router      /auth/update-pin [PATCH]
+// @Router      /v1/auth/update-pin [PATCH]
 // @Summary     Update current user PIN directly
 // @Description Update the current user PIN directly without old PIN (min 6 characters)
 // @Accept      json
@@ -456,27 +321,13 @@ func (a *AuthHandler) ChangePIN(ctx *gin.Context) {
 func (a *AuthHandler) UpdatePIN(ctx *gin.Context) {
 	userId, err := utils.GetUserFromCtx(ctx)
 	if err != nil {
-		log.Println("error cause: ", err)
-		ctx.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusUnauthorized,
-			},
-			Err: err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
 	var body models.SetPINRequest
 	if err := ctx.ShouldBind(&body); err != nil {
-		log.Println("error cause: ", err)
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusBadRequest,
-			},
-			Err: err.Error(),
-		})
+		ctx.Error(apperror.Validation(err))
 		return
 	}
 
@@ -485,39 +336,25 @@ func (a *AuthHandler) UpdatePIN(ctx *gin.Context) {
 
 	hashedPin, err := hashConfig.GenHash(body.PIN)
 	if err != nil {
-		log.Println("error cause: ", err)
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: err.Error(),
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	if err := a.ar.UpdatePIN(ctx, userId, hashedPin); err != nil {
-		log.Println("error cause: ", err)
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: err.Error(),
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	ctx.JSON(http.StatusOK, models.Response{
 		IsSuccess: true,
 		Code:      http.StatusOK,
-		Msg:       "PIN updated successfully",
+		Msg:       i18n.T(ctx, i18n.MsgPINUpdated),
 	})
 }
 
 // Logout
 // @Tags			logout
-// @Router			/auth [DELETE]
+// @Router			/v1/auth [DELETE]
 // @Summary 		Logout user by blacklist their token
 // @Description	Logout user by blacklist their token on redis
 // @Security 		JWTtoken
@@ -529,19 +366,13 @@ func (a *AuthHandler) Logout(ctx *gin.Context) {
 	bearerToken := ctx.GetHeader("Authorization")
 
 	if err := a.ar.BlacklistToken(ctx.Request.Context(), bearerToken); err != nil {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      500,
-			},
-			Err: err.Error(),
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	} else {
 		ctx.JSON(http.StatusOK, models.Response{
 			IsSuccess: true,
 			Code:      200,
-			Msg:       "Logout successfully",
+			Msg:       i18n.T(ctx, i18n.MsgLogoutSuccess),
 		})
 	}
 }
@@ -556,69 +387,53 @@ func (a *AuthHandler) Logout(ctx *gin.Context) {
 // @Success     200 {object} models.Response
 // @Failure     400 {object} models.ErrorResponse "Invalid email format"
 // @Failure     500 {object} models.InternalErrorResponse "Internal Server Error"
-// @Router      /auth/forgot-password [post]
+// @Router      /v1/auth/forgot-password [post]
 func (a *AuthHandler) ForgotPassword(ctx *gin.Context) {
 	var body models.ForgotPasswordOrPINRequest
 	if err := ctx.ShouldBind(&body); err != nil {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusBadRequest,
-			},
-			Err: "Invalid email format",
-		})
+		ctx.Error(apperror.Validation(err))
 		return
 	}
 
 	user, err := a.ar.GetEmailForSMPT(ctx.Request.Context(), body.Email)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: "User not registered",
-		})
+		if errors.Is(err, repository.ErrUserNotFound) {
+			ctx.Error(apperror.New(apperror.UserNotFound))
+			return
+		}
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	token, err := utils.GenerateRandomToken(32)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: "Failed to generate token",
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	key := "reset:pwd:" + token
-	if err := a.ar.SaveResetToken(ctx, key, fmt.Sprintf("%d", user.ID), 15*time.Minute); err != nil {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: "Failed to save reset token",
-		})
+	if err := a.ar.SaveResetToken(ctx, key, fmt.Sprintf("%d", user.UserID), 15*time.Minute); err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
-	frontendURL := os.Getenv("FRONTEND_URL")
+	frontendURL := a.cfg.App.FrontendURL
 	resetLink := fmt.Sprintf("%s/reset-password?token=%s", frontendURL, token)
-	go utils.Send(utils.SendOptions{
-		To:         []string{body.Email},
-		Subject:    "Reset Password",
-		Body:       fmt.Sprintf("<p>Klik link berikut untuk reset password Anda:</p><p><a href='%s'>Reset Password</a></p>", resetLink),
-		BodyIsHTML: true,
+	a.bg.Go(ctx.Request.Context(), func(c context.Context) {
+		err := utils.SendTemplate(c, a.mailer, user.Email, utils.MailResetPassword, mailLanguage(c, user), utils.MailData{
+			Brand: a.cfg.Mail.Branding,
+			Name:  recipientName(user),
+			Data:  map[string]any{"Link": resetLink, "ExpireMinutes": 15},
+		})
+		if err != nil {
+			logger.FromContext(c).Error("Failed to send reset email", "err", err)
+		}
 	})
 
 	ctx.JSON(http.StatusOK, models.Response{
 		IsSuccess: true,
 		Code:      http.StatusOK,
-		Msg:       "Link reset password was sent to email",
+		Msg:       i18n.T(ctx, i18n.MsgResetPasswordLinkSent),
 	})
 }
 
@@ -632,43 +447,25 @@ func (a *AuthHandler) ForgotPassword(ctx *gin.Context) {
 // @Success     200 {object} models.Response
 // @Failure     400 {object} models.ErrorResponse "Invalid or expired token"
 // @Failure     500 {object} models.InternalErrorResponse "Internal Server Error"
-// @Router      /auth/reset-password [post]
+// @Router      /v1/auth/reset-password [post]
 func (a *AuthHandler) ResetPassword(ctx *gin.Context) {
 	var body models.ResetPasswordRequest
 	if err := ctx.ShouldBind(&body); err != nil {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusBadRequest,
-			},
-			Err: "token and new password required",
-		})
+		ctx.Error(apperror.Validation(err))
 		return
 	}
 
 	key := "reset:pwd:" + body.Token
 	userIdStr, err := a.ar.GetResetToken(ctx, key)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusBadRequest,
-			},
-			Err: "invalid or expired token",
-		})
+		ctx.Error(apperror.Wrap(apperror.ResetTokenInvalid, err))
 		return
 	}
 	defer a.ar.DeleteResetToken(ctx, key)
 
 	userId, err := strconv.Atoi(userIdStr)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: "failed to parse user id",
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
@@ -677,31 +474,21 @@ func (a *AuthHandler) ResetPassword(ctx *gin.Context) {
 	hc.UseRecommended()
 	hashedPwd, err := hc.GenHash(body.NewPassword)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: "failed to hash password",
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	if err := a.ar.UpdatePassword(ctx, userId, hashedPwd); err != nil {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: "failed to update password",
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
+	a.sendSecurityAlert(ctx, userId, "password_reset")
+
 	ctx.JSON(http.StatusOK, models.Response{
 		IsSuccess: true,
 		Code:      http.StatusOK,
-		Msg:       "Password reset successfully",
+		Msg:       i18n.T(ctx, i18n.MsgPasswordReset),
 	})
 }
 
@@ -715,70 +502,54 @@ func (a *AuthHandler) ResetPassword(ctx *gin.Context) {
 // @Success     200 {object} models.Response
 // @Failure     400 {object} models.ErrorResponse "Invalid email format"
 // @Failure     500 {object} models.InternalErrorResponse "Internal Server Error"
-// @Router      /auth/forgot-pin [post]
+// @Router      /v1/auth/forgot-pin [post]
 func (a *AuthHandler) ForgotPIN(ctx *gin.Context) {
 	var body models.ForgotPasswordOrPINRequest
 	if err := ctx.ShouldBind(&body); err != nil {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusBadRequest,
-			},
-			Err: "invalid email format",
-		})
+		ctx.Error(apperror.Validation(err))
 		return
 	}
 
 	user, err := a.ar.GetEmailForSMPT(ctx.Request.Context(), body.Email)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: "User not registered",
-		})
+		if errors.Is(err, repository.ErrUserNotFound) {
+			ctx.Error(apperror.New(apperror.UserNotFound))
+			return
+		}
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	token, err := utils.GenerateRandomToken(32)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: "failed to generate token",
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	key := "reset:pin:" + token
-	if err := a.ar.SaveResetToken(ctx, key, fmt.Sprintf("%d", user.ID), 15*time.Minute); err != nil {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: "failed to save reset token",
-		})
+	if err := a.ar.SaveResetToken(ctx, key, fmt.Sprintf("%d", user.UserID), 15*time.Minute); err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
-	frontendURL := os.Getenv("FRONTEND_URL")
+	frontendURL := a.cfg.App.FrontendURL
 	resetLink := fmt.Sprintf("%s/reset-pin?token=%s", frontendURL, token)
-	go utils.Send(utils.SendOptions{
-		To:         []string{body.Email},
-		Subject:    "Reset PIN",
-		Body:       fmt.Sprintf("<p>Klik link berikut untuk reset PIN Anda:</p><p><a href='%s'>Reset PIN</a></p>", resetLink),
-		BodyIsHTML: true,
+	a.bg.Go(ctx.Request.Context(), func(c context.Context) {
+		err := utils.SendTemplate(c, a.mailer, user.Email, utils.MailResetPIN, mailLanguage(c, user), utils.MailData{
+			Brand: a.cfg.Mail.Branding,
+			Name:  recipientName(user),
+			Data:  map[string]any{"Link": resetLink, "ExpireMinutes": 15},
+		})
+		if err != nil {
+			logger.FromContext(c).Error("Failed to send reset email", "err", err)
+		}
 	})
 
 	ctx.JSON(http.StatusOK, models.ResponseData{
 		Response: models.Response{
 			IsSuccess: true,
 			Code:      http.StatusOK,
-			Msg:       "Link reset password was sent to email",
+			Msg:       i18n.T(ctx, i18n.MsgResetPINLinkSent),
 		},
 	})
 }
@@ -793,43 +564,25 @@ func (a *AuthHandler) ForgotPIN(ctx *gin.Context) {
 // @Success     200 {object} models.Response
 // @Failure     400 {object} models.ErrorResponse "Invalid or expired token"
 // @Failure     500 {object} models.InternalErrorResponse "Internal Server Error"
-// @Router      /auth/reset-pin [post]
+// @Router      /v1/auth/reset-pin [post]
 func (a *AuthHandler) ResetPIN(ctx *gin.Context) {
 	var body models.ResetPINRequest
 	if err := ctx.ShouldBind(&body); err != nil {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusBadRequest,
-			},
-			Err: "token and new pin required",
-		})
+		ctx.Error(apperror.Validation(err))
 		return
 	}
 
 	key := "reset:pin:" + body.Token
 	userIdStr, err := a.ar.GetResetToken(ctx, key)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusBadRequest,
-			},
-			Err: "invalid or expired token",
-		})
+		ctx.Error(apperror.Wrap(apperror.ResetTokenInvalid, err))
 		return
 	}
 	defer a.ar.DeleteResetToken(ctx, key)
 
 	userId, err := strconv.Atoi(userIdStr)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: "failed to parse user id",
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
@@ -837,31 +590,21 @@ func (a *AuthHandler) ResetPIN(ctx *gin.Context) {
 	hc.UseRecommended()
 	hashedPin, err := hc.GenHash(body.NewPIN)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: "failed to hash pin",
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	if err := a.ar.UpdatePIN(ctx, userId, hashedPin); err != nil {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: "failed to update pin",
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
+	a.sendSecurityAlert(ctx, userId, "pin_reset")
+
 	ctx.JSON(http.StatusOK, models.Response{
 		IsSuccess: true,
 		Code:      http.StatusOK,
-		Msg:       "PIN reset successfully",
+		Msg:       i18n.T(ctx, i18n.MsgPINReset),
 	})
 }
 
@@ -874,63 +617,81 @@ func (a *AuthHandler) ResetPIN(ctx *gin.Context) {
 // @Produce     json
 // @Param       body body models.ConfirmPayment true "PIN confirmation"
 // @Success     200 {object} models.Response
-// @Failure     400 {object} models.ErrorResponse "Invalid request"
-// @Failure     401 {object} models.ErrorResponse "Invalid PIN or unauthorized"
+// @Failure     400 {object} models.ErrorResponse "Invalid request, PIN_INVALID or PIN_NOT_SET"
+// @Failure     401 {object} models.UnauthorizedResponse "Unauthorized"
 // @Failure     500 {object} models.InternalErrorResponse "Internal Server Error"
-// @Router      /auth/confirm-pin [post]
+// @Router      /v1/auth/confirm-pin [post]
 func (a *AuthHandler) ConfirmPIN(ctx *gin.Context) {
 	userId, err := utils.GetUserFromCtx(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusUnauthorized,
-			},
-			Err: err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
 	var body models.ConfirmPayment
 	if err := ctx.ShouldBindJSON(&body); err != nil {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusBadRequest,
-			},
-			Err: err.Error(),
-		})
+		ctx.Error(apperror.Validation(err))
 		return
 	}
 
 	pinDB, err := a.ar.VerifyPIN(ctx, userId)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: err.Error(),
-		})
+		if errors.Is(err, repository.ErrPINNotSet) {
+			ctx.Error(apperror.New(apperror.PINNotSet))
+			return
+		}
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	hashConfig := pkg.NewHashConfig()
 	ok, err := hashConfig.CompareHashAndPassword(body.PIN, pinDB)
 	if err != nil || !ok {
-		ctx.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusUnauthorized,
-			},
-			Err: "invalid pin",
-		})
+		metrics.ObservePINFailure(metrics.PINSourceConfirm)
+		ctx.Error(apperror.New(apperror.PINInvalid))
 		return
 	}
 
 	ctx.JSON(http.StatusOK, models.Response{
 		IsSuccess: true,
 		Code:      http.StatusOK,
-		Msg:       "PIN verified successfully, payment confirmed",
+		Msg:       i18n.T(ctx, i18n.MsgPINConfirmed),
+	})
+}
+
+// mailLanguage is language of mail to the user doing the request: language picked on profile, else language of the request
+func mailLanguage(c context.Context, r *models.MailRecipient) string {
+	return cmp.Or(r.Language, i18n.FromContext(c))
+}
+
+// name used on email greeting, fallback to email if user not set fullname yet
+func recipientName(r *models.MailRecipient) string {
+	if r.Fullname != "" {
+		return r.Fullname
+	}
+	return r.Email
+}
+
+// send security alert email in background, failure only logged
+func (a *AuthHandler) sendSecurityAlert(ctx *gin.Context, userId int, event string) {
+	data := map[string]any{
+		"Event":     event,
+		"Time":      time.Now(),
+		"IP":        ctx.ClientIP(),
+		"UserAgent": ctx.Request.UserAgent(),
+	}
+	a.bg.Go(ctx.Request.Context(), func(c context.Context) {
+		recipient, err := a.ar.GetMailRecipient(c, userId)
+		if err != nil {
+			logger.FromContext(c).Error("Failed get security alert recipient", "err", err)
+			return
+		}
+		if err := utils.SendTemplate(c, a.mailer, recipient.Email, utils.MailSecurityAlert, mailLanguage(c, recipient), utils.MailData{
+			Brand: a.cfg.Mail.Branding,
+			Name:  recipientName(recipient),
+			Data:  data,
+		}); err != nil {
+			logger.FromContext(c).Error("Failed to send security alert email", "err", err)
+		}
 	})
 }
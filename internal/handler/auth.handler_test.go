@@ -0,0 +1,86 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+)
+
+func TestLogin(t *testing.T) {
+	env := newTestEnv(t)
+	env.store.AddUser("budi@mail.com", hash(t, "Rahasia#123"), hash(t, "123456"), 0)
+	env.store.AddUser("nopin@mail.com", hash(t, "Rahasia#123"), "", 0)
+
+	tests := []struct {
+		name       string
+		body       any
+		status     int
+		errCode    string
+		isPinExist bool
+	}{
+		{name: "success", body: models.AuthRequest{Email: "budi@mail.com", Password: "Rahasia#123"}, status: http.StatusOK, isPinExist: true},
+		{name: "pin not set", body: models.AuthRequest{Email: "nopin@mail.com", Password: "Rahasia#123"}, status: http.StatusOK},
+		{name: "wrong password", body: models.AuthRequest{Email: "budi@mail.com", Password: "salah"}, status: http.StatusBadRequest, errCode: "INVALID_CREDENTIALS"},
+		{name: "unknown email", body: models.AuthRequest{Email: "siapa@mail.com", Password: "Rahasia#123"}, status: http.StatusBadRequest, errCode: "INVALID_CREDENTIALS"},
+		{name: "invalid body", body: map[string]string{"email": "budi"}, status: http.StatusBadRequest, errCode: "VALIDATION_FAILED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, res := env.do(http.MethodPost, "/auth", tt.body, 0)
+			if tt.errCode != "" {
+				assertError(t, rec, res, tt.status, tt.errCode)
+				return
+			}
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.status, rec.Body.String())
+			}
+			data := decodeData[models.AuthResponse](t, res)
+			if data.Token == "" {
+				t.Error("token is empty")
+			}
+			if data.IsPinExist != tt.isPinExist {
+				t.Errorf("is_pin_exist = %v, want %v", data.IsPinExist, tt.isPinExist)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	env := newTestEnv(t)
+	env.store.AddUser("budi@mail.com", hash(t, "Rahasia#123"), "", 0)
+
+	tests := []struct {
+		name    string
+		body    any
+		status  int
+		errCode string
+	}{
+		{name: "success", body: models.AuthRequest{Email: "ani@mail.com", Password: "Rahasia#123"}, status: http.StatusOK},
+		{name: "email already registered", body: models.AuthRequest{Email: "budi@mail.com", Password: "Rahasia#123"}, status: http.StatusConflict, errCode: "EMAIL_ALREADY_REGISTERED"},
+		{name: "weak password", body: models.AuthRequest{Email: "cici@mail.com", Password: "rahasia"}, status: http.StatusBadRequest, errCode: "PASSWORD_WEAK"},
+		{name: "missing password", body: map[string]string{"email": "cici@mail.com"}, status: http.StatusBadRequest, errCode: "VALIDATION_FAILED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, res := env.do(http.MethodPost, "/auth/register", tt.body, 0)
+			if tt.errCode != "" {
+				assertError(t, rec, res, tt.status, tt.errCode)
+				return
+			}
+			if rec.Code != tt.status || !res.IsSuccess {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.status, rec.Body.String())
+			}
+		})
+	}
+
+	// registered user can login and receive welcome email
+	rec, _ := env.do(http.MethodPost, "/auth", models.AuthRequest{Email: "ani@mail.com", Password: "Rahasia#123"}, 0)
+	if rec.Code != http.StatusOK {
+		t.Errorf("login after register status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	mails := env.sentMails()
+	if len(mails) != 1 || len(mails[0].To) != 1 || mails[0].To[0] != "ani@mail.com" {
+		t.Errorf("welcome mail = %+v, want 1 mail to ani@mail.com", mails)
+	}
+}
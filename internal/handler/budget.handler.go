@@ -0,0 +1,275 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/apperror"
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+	"github.com/Belalai-E-Wallet-Backend/internal/i18n"
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// BudgetHandler serve budget endpoints and check budget after outgoing transfer,
+// spending is summed with chart repository so it follow category rule of user
+type BudgetHandler struct {
+	br     repository.BudgetRepo
+	cr     repository.ChartRepo
+	mailer utils.Mailer
+	bg     *utils.Background
+	cfg    *configs.Config
+}
+
+func NewBudgetHandler(br repository.BudgetRepo, cr repository.ChartRepo, mailer utils.Mailer, bg *utils.Background, cfg *configs.Config) *BudgetHandler {
+	return &BudgetHandler{br: br, cr: cr, mailer: mailer, bg: bg, cfg: cfg}
+}
+
+// GetBudgets - Budget of user with spending of a month
+// @tags 			budget
+// @router 	 		/v1/budgets 	[GET]
+// @Summary 		Get budgets progress
+// @Description 	Every budget with spent, remaining and percentage on month (default this month). Month follow APP_TIMEZONE, overall budget (empty category) spent every category
+// @accept 			json
+// @produce 		json
+// @Security 		BearerAuth
+// @Param			month	query	string	false	"Month (YYYY-MM)"
+// @failure 		400			{object} 	models.ErrorResponse "Invalid month"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{object}  	models.ResponseData{data=models.BudgetsProgress} "Success Response"
+func (b *BudgetHandler) GetBudgets(ctx *gin.Context) {
+	userID, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var query models.BudgetQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.Validation(err))
+		return
+	}
+	r, err := b.month(time.Now())
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+	if query.Month != nil {
+		start := time.Date(query.Month.Year(), query.Month.Month(), 1, 0, 0, 0, 0, r.Location)
+		if r, err = b.month(start); err != nil {
+			ctx.Error(apperror.Wrap(apperror.Internal, err))
+			return
+		}
+	}
+
+	progress, err := b.progress(ctx.Request.Context(), userID, r)
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.ResponseData{
+		Response: models.Response{
+			IsSuccess: true,
+			Code:      http.StatusOK,
+			Msg:       i18n.T(ctx, i18n.MsgBudgetsFetched),
+		},
+		Data: progress,
+	})
+}
+
+// SaveBudget - Set monthly budget of category
+// @tags 			budget
+// @router 	 		/v1/budgets 	[PUT]
+// @Summary 		Set budget
+// @Description 	Create budget of spending category (empty category is overall budget) or replace its amount. Reaching 80% and 100% of it is notified once a month, changing the amount notify it again
+// @accept 			json
+// @produce 		json
+// @Security 		BearerAuth
+// @Param			body	body	models.BudgetRequest	true	"Budget"
+// @failure 		400			{object} 	models.ErrorResponse "Invalid body"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{object}  	models.ResponseData{data=models.Budget} "Success Response"
+func (b *BudgetHandler) SaveBudget(ctx *gin.Context) {
+	userID, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var body models.BudgetRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.Error(apperror.Validation(err))
+		return
+	}
+
+	budget := models.Budget{Category: body.Category, Amount: body.Amount}
+	if err := b.br.SaveBudget(ctx.Request.Context(), userID, &budget); err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.ResponseData{
+		Response: models.Response{
+			IsSuccess: true,
+			Code:      http.StatusOK,
+			Msg:       i18n.T(ctx, i18n.MsgBudgetSaved),
+		},
+		Data: budget,
+	})
+}
+
+// DeleteBudget - Remove budget of user
+// @tags 			budget
+// @router 	 		/v1/budgets/{id} 	[DELETE]
+// @Summary 		Delete budget
+// @Description 	Remove budget, its spending isn't tracked anymore
+// @accept 			json
+// @produce 		json
+// @Security 		BearerAuth
+// @Param			id	path	int	true	"Budget ID"
+// @failure 		400			{object} 	models.ErrorResponse "Invalid ID"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		404			{object} 	models.NotFoundResponse "Budget not found"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{object}  	models.Response "Success Response"
+func (b *BudgetHandler) DeleteBudget(ctx *gin.Context) {
+	userID, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.InvalidID, err))
+		return
+	}
+
+	if err := b.br.DeleteBudget(ctx.Request.Context(), userID, id); err != nil {
+		if errors.Is(err, repository.ErrBudgetNotFound) {
+			ctx.Error(apperror.New(apperror.BudgetNotFound))
+			return
+		}
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.Response{
+		IsSuccess: true,
+		Code:      http.StatusOK,
+		Msg:       i18n.T(ctx, i18n.MsgBudgetDeleted),
+	})
+}
+
+// month is range of month of t in APP_TIMEZONE, alert and progress use the same month
+func (b *BudgetHandler) month(t time.Time) (models.ChartRange, error) {
+	loc, err := time.LoadLocation(b.cfg.App.Timezone)
+	if err != nil {
+		return models.ChartRange{}, err
+	}
+	t = t.In(loc)
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	return models.NewChartRange(start, start.AddDate(0, 1, -1), "month", loc)
+}
+
+func (b *BudgetHandler) progress(c context.Context, userID int, r models.ChartRange) (models.BudgetsProgress, error) {
+	budgets, err := b.br.GetBudgets(c, userID)
+	if err != nil {
+		return models.BudgetsProgress{}, err
+	}
+	var totals []models.CategoryTotal
+	// user without budget doesn't need spending
+	if len(budgets) > 0 {
+		if totals, err = b.cr.GetCategoryTotals(c, userID, r); err != nil {
+			return models.BudgetsProgress{}, err
+		}
+	}
+	return models.NewBudgetsProgress(r, budgets, totals), nil
+}
+
+// track budget of sender after outgoing transfer in background, so it doesn't slow the transfer down.
+// failure only logged because transfer is already success
+func (b *BudgetHandler) track(c context.Context, userID int) {
+	b.bg.Go(c, func(c context.Context) {
+		if err := b.checkThresholds(c, userID); err != nil {
+			logger.FromContext(c).Error("Failed check budget threshold", "user_id", userID, "err", err)
+		}
+	})
+}
+
+// checkThresholds notify every budget of this month which reach a threshold for the first time,
+// only the highest new threshold is notified when spending jump over several of them
+func (b *BudgetHandler) checkThresholds(c context.Context, userID int) error {
+	r, err := b.month(time.Now())
+	if err != nil {
+		return err
+	}
+	progress, err := b.progress(c, userID, r)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range progress.Budgets {
+		reached := p.Threshold
+		p.Threshold = 0
+		for _, threshold := range models.BudgetThresholds {
+			if threshold > reached {
+				break
+			}
+			// marked by concurrent transfer is already notified there
+			marked, err := b.br.MarkAlert(c, p.ID, r.From, threshold)
+			if err != nil {
+				return err
+			}
+			if marked {
+				p.Threshold = threshold
+			}
+		}
+		if p.Threshold > 0 {
+			b.notify(c, userID, models.BudgetAlert{BudgetProgress: p, Month: progress.Month})
+		}
+	}
+	return nil
+}
+
+// notify alert to connected device and by email
+func (b *BudgetHandler) notify(c context.Context, userID int, alert models.BudgetAlert) {
+	if err := b.br.PublishAlert(c, userID, alert); err != nil {
+		logger.FromContext(c).Error("Failed publish budget alert", "err", err)
+	}
+
+	recipient, err := b.br.GetMailRecipient(c, userID)
+	if err != nil {
+		logger.FromContext(c).Error("Failed get budget alert recipient", "err", err)
+		return
+	}
+	lang := mailLanguage(c, recipient)
+	name := i18n.Translate(lang, i18n.MsgBudgetOverall)
+	if alert.Category != "" {
+		name = i18n.Translate(lang, i18n.CategoryName(alert.Category))
+	}
+	if err := utils.SendTemplate(c, b.mailer, recipient.Email, utils.MailBudgetAlert, lang, utils.MailData{
+		Brand: b.cfg.Mail.Branding,
+		Name:  recipientName(recipient),
+		Data: map[string]any{
+			"Budget":    name,
+			"Threshold": alert.Threshold,
+			"Month":     alert.Month,
+			"Amount":    alert.Amount,
+			"Spent":     alert.Spent,
+			"Remaining": alert.Remaining,
+			"Over":      -alert.Remaining,
+		},
+	}); err != nil {
+		logger.FromContext(c).Error("Failed to send budget alert email", "err", err)
+	}
+}
@@ -0,0 +1,191 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository/memory"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+)
+
+func TestBudget(t *testing.T) {
+	env := newTestEnv(t)
+	budi, _ := env.store.AddUser("budi@mail.com", hash(t, "Rahasia#123"), hash(t, "123456"), 1000000)
+	ani, aniWallet := env.store.AddUser("ani@mail.com", hash(t, "Rahasia#123"), "", 0)
+	// budi picked id on profile, it win over Accept-Language en of the request
+	lang := "id"
+	if err := memory.NewProfileRepository(env.store).UpdateProfile(context.Background(), &models.Profile{UserID: budi, Language: &lang}); err != nil {
+		t.Fatal(err)
+	}
+
+	sub, err := memory.NewEventRepository(env.store).Subscribe(context.Background(), budi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	// alert is checked in background, wait for it before the next transfer so alerts keep their order
+	nextAlert := func(t *testing.T) models.BudgetAlert {
+		t.Helper()
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case msg := <-sub.Channel():
+				var event struct {
+					Type models.EventType   `json:"type"`
+					Data models.BudgetAlert `json:"data"`
+				}
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					t.Fatal(err)
+				}
+				if event.Type == models.EventBudgetAlert {
+					return event.Data
+				}
+			case <-timeout:
+				t.Fatal("budget alert isn't published")
+			}
+		}
+	}
+	transfer := func(t *testing.T, amount int, notes string) {
+		t.Helper()
+		body := models.TransferBody{IdReceiver: aniWallet, ReceiverPhone: "0812", Amount: amount, Notes: notes, PinSender: "123456"}
+		if rec, res := env.do(http.MethodPost, "/transfer", body, budi); rec.Code != http.StatusOK {
+			t.Fatalf("transfer status = %d (%s)", rec.Code, res.Err)
+		}
+	}
+	save := func(t *testing.T, category string, amount int) models.Budget {
+		t.Helper()
+		rec, res := env.do(http.MethodPut, "/budgets", models.BudgetRequest{Category: category, Amount: amount}, budi)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("save status = %d (%s)", rec.Code, res.Err)
+		}
+		return decodeData[models.Budget](t, res)
+	}
+
+	t.Run("invalid request", func(t *testing.T) {
+		rec, res := env.do(http.MethodPut, "/budgets", models.BudgetRequest{Category: "income", Amount: 1000}, budi)
+		assertError(t, rec, res, http.StatusBadRequest, "VALIDATION_FAILED")
+		rec, res = env.do(http.MethodPut, "/budgets", models.BudgetRequest{Category: "food"}, budi)
+		assertError(t, rec, res, http.StatusBadRequest, "VALIDATION_FAILED")
+		rec, res = env.do(http.MethodGet, "/budgets?month=2026-13", nil, budi)
+		assertError(t, rec, res, http.StatusBadRequest, "VALIDATION_FAILED")
+	})
+
+	overall := save(t, "", 100000)
+	food := save(t, "food", 10000)
+
+	t.Run("threshold alert", func(t *testing.T) {
+		transfer(t, 5000, "kopi") // food 50%
+		transfer(t, 3500, "makan")
+		if got := nextAlert(t); got.ID != food.ID || got.Threshold != 80 || got.Spent != 8500 {
+			t.Errorf("alert = %+v, want food reach 80%%", got)
+		}
+		transfer(t, 2000, "makan siang")
+		if got := nextAlert(t); got.ID != food.ID || got.Threshold != 100 || got.Remaining != -500 {
+			t.Errorf("alert = %+v, want food reach 100%%", got)
+		}
+		// jump over 80% only notify 100%
+		transfer(t, 90000, "")
+		if got := nextAlert(t); got.ID != overall.ID || got.Threshold != 100 || got.Spent != 100500 {
+			t.Errorf("alert = %+v, want overall reach 100%%", got)
+		}
+	})
+
+	t.Run("progress", func(t *testing.T) {
+		rec, res := env.do(http.MethodGet, "/budgets", nil, budi)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d (%s)", rec.Code, res.Err)
+		}
+		jakarta, err := time.LoadLocation("Asia/Jakarta")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := decodeData[models.BudgetsProgress](t, res)
+		if got.Month != time.Now().In(jakarta).Format("2006-01") || got.Timezone != "Asia/Jakarta" || len(got.Budgets) != 2 {
+			t.Fatalf("progress = %+v", got)
+		}
+		if b := got.Budgets[0]; b.ID != overall.ID || b.Spent != 100500 || b.Percentage != 100.5 || b.Threshold != 100 {
+			t.Errorf("overall = %+v", b)
+		}
+		if b := got.Budgets[1]; b.ID != food.ID || b.Spent != 10500 || b.Remaining != -500 || b.Percentage != 105 {
+			t.Errorf("food = %+v", b)
+		}
+
+		_, res = env.do(http.MethodGet, "/budgets?month=2020-01", nil, budi)
+		if got := decodeData[models.BudgetsProgress](t, res); got.Month != "2020-01" || got.Budgets[0].Spent != 0 {
+			t.Errorf("progress of 2020-01 = %+v", got)
+		}
+		_, res = env.do(http.MethodGet, "/budgets", nil, ani)
+		if got := decodeData[models.BudgetsProgress](t, res); len(got.Budgets) != 0 {
+			t.Errorf("budgets of other user = %+v", got.Budgets)
+		}
+	})
+
+	t.Run("raise amount notify again", func(t *testing.T) {
+		if got := save(t, "food", 20000); got.ID != food.ID || got.Amount != 20000 || got.UpdatedAt == nil {
+			t.Errorf("saved = %+v, want the same budget updated", got)
+		}
+		transfer(t, 1000, "makan") // food 57.5%
+		transfer(t, 5000, "makan")
+		// overall is already notified this month
+		if got := nextAlert(t); got.ID != food.ID || got.Threshold != 80 || got.Spent != 16500 {
+			t.Errorf("alert = %+v, want food reach 80%% again", got)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		rec, res := env.do(http.MethodDelete, "/budgets/"+strconv.Itoa(food.ID), nil, ani)
+		assertError(t, rec, res, http.StatusNotFound, "BUDGET_NOT_FOUND")
+		if rec, res := env.do(http.MethodDelete, "/budgets/"+strconv.Itoa(food.ID), nil, budi); rec.Code != http.StatusOK {
+			t.Fatalf("status = %d (%s)", rec.Code, res.Err)
+		}
+		rec, res = env.do(http.MethodDelete, "/budgets/"+strconv.Itoa(food.ID), nil, budi)
+		assertError(t, rec, res, http.StatusNotFound, "BUDGET_NOT_FOUND")
+		rec, res = env.do(http.MethodDelete, "/budgets/abc", nil, budi)
+		assertError(t, rec, res, http.StatusBadRequest, "INVALID_ID")
+	})
+
+	mails := slices.DeleteFunc(env.sentMails(), func(m utils.CapturedMail) bool { return !slices.Contains(m.To, "budi@mail.com") })
+	if len(mails) != 4 {
+		t.Fatalf("budget mails = %d, want 4", len(mails))
+	}
+	subjects := []string{}
+	for _, m := range mails {
+		subjects = append(subjects, m.Subject)
+	}
+	slices.Sort(subjects)
+	want := []string{
+		"Anggaran Makanan & Minuman kamu sudah habis",
+		"Anggaran Semua pengeluaran kamu sudah habis",
+		"Kamu sudah memakai 80% anggaran Makanan & Minuman",
+		"Kamu sudah memakai 80% anggaran Makanan & Minuman",
+	}
+	if !slices.Equal(subjects, want) {
+		t.Errorf("budget mail subjects = %q, want %q", subjects, want)
+	}
+}
+
+func TestBudgetMailLanguage(t *testing.T) {
+	env := newTestEnv(t)
+	budi, _ := env.store.AddUser("budi@mail.com", hash(t, "Rahasia#123"), hash(t, "123456"), 100000)
+	_, aniWallet := env.store.AddUser("ani@mail.com", hash(t, "Rahasia#123"), "", 0)
+
+	// budi hasn't picked language on profile, mail follow Accept-Language en of the request
+	if rec, res := env.do(http.MethodPut, "/budgets", models.BudgetRequest{Amount: 10000}, budi); rec.Code != http.StatusOK {
+		t.Fatalf("save status = %d (%s)", rec.Code, res.Err)
+	}
+	body := models.TransferBody{IdReceiver: aniWallet, ReceiverPhone: "0812", Amount: 10000, PinSender: "123456"}
+	if rec, res := env.do(http.MethodPost, "/transfer", body, budi); rec.Code != http.StatusOK {
+		t.Fatalf("transfer status = %d (%s)", rec.Code, res.Err)
+	}
+
+	mails := slices.DeleteFunc(env.sentMails(), func(m utils.CapturedMail) bool { return !slices.Contains(m.To, "budi@mail.com") })
+	if len(mails) != 1 || mails[0].Subject != "You have used up your All spending budget" {
+		t.Errorf("budget mails = %+v, want 1 english mail", mails)
+	}
+}
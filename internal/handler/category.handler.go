@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/apperror"
+	"github.com/Belalai-E-Wallet-Backend/internal/i18n"
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryHandler struct {
+	cr repository.CategoryRepo
+}
+
+func NewCategoryHandler(cr repository.CategoryRepo) *CategoryHandler {
+	return &CategoryHandler{cr: cr}
+}
+
+// GetCategories - Every category with name in language of user
+// @tags 			category
+// @router 	 		/v1/category 	[GET]
+// @Summary 		Get categories
+// @Description 	Category of transaction, only spending category can be used by rule
+// @accept 			json
+// @produce 		json
+// @Security 		BearerAuth
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @success 		200 		{object}  	models.ResponseData{data=[]models.Category} "Success Response"
+func (ch *CategoryHandler) GetCategories(ctx *gin.Context) {
+	categories := make([]models.Category, len(models.Categories))
+	for i, key := range models.Categories {
+		categories[i] = models.Category{
+			Key:      key,
+			Name:     i18n.T(ctx, i18n.CategoryName(key)),
+			Spending: i < len(models.SpendingCategories),
+		}
+	}
+
+	ctx.JSON(http.StatusOK, models.ResponseData{
+		Response: models.Response{
+			IsSuccess: true,
+			Code:      http.StatusOK,
+			Msg:       i18n.T(ctx, i18n.MsgCategoriesFetched),
+		},
+		Data: categories,
+	})
+}
+
+// GetRules - Category rule made by user
+// @tags 			category
+// @router 	 		/v1/category/rules 	[GET]
+// @Summary 		Get category rules
+// @Description 	Rule of user in the order they are tried, before the default rule
+// @accept 			json
+// @produce 		json
+// @Security 		BearerAuth
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{object}  	models.ResponseData{data=[]models.CategoryRule} "Success Response"
+func (ch *CategoryHandler) GetRules(ctx *gin.Context) {
+	userID, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	rules, err := ch.cr.GetRules(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.ResponseData{
+		Response: models.Response{
+			IsSuccess: true,
+			Code:      http.StatusOK,
+			Msg:       i18n.T(ctx, i18n.MsgCategoryRulesFetched),
+		},
+		Data: rules,
+	})
+}
+
+// CreateRule - Give category to sent transfer whose notes or receiver name contain keyword
+// @tags 			category
+// @router 	 		/v1/category/rules 	[POST]
+// @Summary 		Create category rule
+// @Description 	Rule apply to every sent transfer, the old one too, unless user picked its category. Max 50 rule per user
+// @accept 			json
+// @produce 		json
+// @Security 		BearerAuth
+// @Param			body	body	models.CategoryRuleRequest	true	"Rule"
+// @failure 		400			{object} 	models.ErrorResponse "Invalid body or too many rule"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		201 		{object}  	models.ResponseData{data=models.CategoryRule} "Success Response"
+func (ch *CategoryHandler) CreateRule(ctx *gin.Context) {
+	userID, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var body models.CategoryRuleRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.Error(apperror.Validation(err))
+		return
+	}
+	keyword := strings.ToLower(strings.TrimSpace(body.Keyword))
+	if keyword == "" {
+		ctx.Error(apperror.New(apperror.ValidationFailed).WithDetail(i18n.T(ctx, i18n.MsgCategoryKeywordBlank)))
+		return
+	}
+
+	rules, err := ch.cr.GetRules(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+	if len(rules) >= models.MaxCategoryRules {
+		ctx.Error(apperror.New(apperror.ValidationFailed).WithDetail(i18n.T(ctx, i18n.MsgCategoryRuleLimit, models.MaxCategoryRules)))
+		return
+	}
+
+	rule := models.CategoryRule{Field: body.Field, Keyword: keyword, Category: body.Category}
+	if err := ch.cr.CreateRule(ctx.Request.Context(), userID, &rule); err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, models.ResponseData{
+		Response: models.Response{
+			IsSuccess: true,
+			Code:      http.StatusCreated,
+			Msg:       i18n.T(ctx, i18n.MsgCategoryRuleCreated),
+		},
+		Data: rule,
+	})
+}
+
+// DeleteRule - Remove category rule of user
+// @tags 			category
+// @router 	 		/v1/category/rules/{id} 	[DELETE]
+// @Summary 		Delete category rule
+// @Description 	Transfer categorized by the rule go back to the next matching rule
+// @accept 			json
+// @produce 		json
+// @Security 		BearerAuth
+// @Param			id	path	int	true	"Rule ID"
+// @failure 		400			{object} 	models.ErrorResponse "Invalid ID"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		404			{object} 	models.NotFoundResponse "Rule not found"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{object}  	models.Response "Success Response"
+func (ch *CategoryHandler) DeleteRule(ctx *gin.Context) {
+	userID, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.InvalidID, err))
+		return
+	}
+
+	if err := ch.cr.DeleteRule(ctx.Request.Context(), userID, id); err != nil {
+		if errors.Is(err, repository.ErrCategoryRuleNotFound) {
+			ctx.Error(apperror.New(apperror.CategoryRuleNotFound))
+			return
+		}
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.Response{
+		IsSuccess: true,
+		Code:      http.StatusOK,
+		Msg:       i18n.T(ctx, i18n.MsgCategoryRuleDeleted),
+	})
+}
@@ -0,0 +1,188 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository/memory"
+)
+
+func TestCategory(t *testing.T) {
+	env := newTestEnv(t)
+	budi, _ := env.store.AddUser("budi@mail.com", hash(t, "Rahasia#123"), "", 100000)
+	ani, aniWallet := env.store.AddUser("ani@mail.com", hash(t, "Rahasia#123"), "", 0)
+	paymentID := env.store.AddPaymentMethod("BRI")
+
+	c := context.Background()
+	name := "Ani Wijaya"
+	if err := memory.NewProfileRepository(env.store).UpdateProfile(c, &models.Profile{UserID: ani, Fullname: &name}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	env.store.SetClock(func() time.Time { return now })
+	transfer := memory.NewTransferRepository(env.store)
+	// transfer id 1..3
+	for _, notes := range []string{"Makan siang", "bayar Netflix", "titip"} {
+		now = now.Add(time.Minute)
+		if _, err := transfer.TransferMoney(c, budi, models.TransferBody{IdReceiver: aniWallet, Amount: 1000, Notes: notes}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now = now.Add(time.Minute)
+	if _, err := memory.NewTopUpRepository(env.store).CreateTopUpTransaction(c, &models.TopUp{Amount: 20000, PaymentID: paymentID}, budi); err != nil {
+		t.Fatal(err)
+	}
+
+	// categories is category of history of user keyed by kind:id
+	categories := func(t *testing.T, user int) map[string]string {
+		t.Helper()
+		rec, res := env.do(http.MethodGet, "/transaction/history/all", nil, user)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d (body %s)", rec.Code, rec.Body.String())
+		}
+		got := map[string]string{}
+		page := decodeData[struct {
+			Transactions []models.TransactionHistory `json:"transactions"`
+		}](t, res)
+		for _, h := range page.Transactions {
+			got[fmt.Sprintf("%s:%d", h.Kind, h.ID)] = h.Category
+		}
+		return got
+	}
+	assertCategories := func(t *testing.T, user int, want map[string]string) {
+		t.Helper()
+		got := categories(t, user)
+		for key, category := range want {
+			if got[key] != category {
+				t.Errorf("category of %s = %q, want %q", key, got[key], category)
+			}
+		}
+	}
+
+	t.Run("list with name", func(t *testing.T) {
+		rec, res := env.do(http.MethodGet, "/category", nil, budi)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d", rec.Code)
+		}
+		list := decodeData[[]models.Category](t, res)
+		if len(list) != len(models.Categories) || list[0] != (models.Category{Key: "food", Name: "Food & Drink", Spending: true}) {
+			t.Errorf("categories = %+v", list)
+		}
+		if last := list[len(list)-1]; last.Key != "topup" || last.Spending {
+			t.Errorf("last = %+v, want topup which isn't spending", last)
+		}
+	})
+
+	t.Run("default rule and type", func(t *testing.T) {
+		assertCategories(t, budi, map[string]string{"transfer:1": "food", "transfer:2": "entertainment", "transfer:3": "other", "topup:1": "topup"})
+		assertCategories(t, ani, map[string]string{"transfer:1": "income", "transfer:3": "income"})
+	})
+
+	var ruleID int
+	t.Run("rule of user win over default rule", func(t *testing.T) {
+		rec, res := env.do(http.MethodPost, "/category/rules", models.CategoryRuleRequest{Field: "counterparty", Keyword: "  WIJAYA ", Category: "family"}, budi)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d (body %s)", rec.Code, rec.Body.String())
+		}
+		rule := decodeData[models.CategoryRule](t, res)
+		if rule.Keyword != "wijaya" || rule.ID == 0 {
+			t.Errorf("rule = %+v, want lowercase trimmed keyword", rule)
+		}
+		ruleID = rule.ID
+
+		// applied to old transfer too, but not to received transfer
+		assertCategories(t, budi, map[string]string{"transfer:1": "family", "transfer:2": "family", "transfer:3": "family"})
+		assertCategories(t, ani, map[string]string{"transfer:1": "income"})
+
+		_, res = env.do(http.MethodGet, "/category/rules", nil, budi)
+		if rules := decodeData[[]models.CategoryRule](t, res); len(rules) != 1 || rules[0].ID != ruleID {
+			t.Errorf("rules = %+v", rules)
+		}
+		_, res = env.do(http.MethodGet, "/category/rules", nil, ani)
+		if rules := decodeData[[]models.CategoryRule](t, res); len(rules) != 0 {
+			t.Errorf("rules of ani = %+v, want none", rules)
+		}
+	})
+
+	t.Run("picked by user", func(t *testing.T) {
+		rec, res := env.do(http.MethodPut, "/transaction/2/category", models.CategoryRequest{Category: "bills"}, budi)
+		if rec.Code != http.StatusOK || decodeData[models.CategoryRequest](t, res).Category != "bills" {
+			t.Fatalf("status = %d (body %s)", rec.Code, rec.Body.String())
+		}
+		// receiver has its own category
+		rec, _ = env.do(http.MethodPut, "/transaction/2/category", models.CategoryRequest{Category: "other"}, ani)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("receiver status = %d", rec.Code)
+		}
+		rec, _ = env.do(http.MethodPut, "/transaction/topup/1/category", models.CategoryRequest{Category: "income"}, budi)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("topup status = %d", rec.Code)
+		}
+		assertCategories(t, budi, map[string]string{"transfer:1": "family", "transfer:2": "bills", "topup:1": "income"})
+		assertCategories(t, ani, map[string]string{"transfer:2": "other"})
+
+		// empty let rule decide again
+		_, res = env.do(http.MethodPut, "/transaction/2/category", models.CategoryRequest{}, budi)
+		if got := decodeData[models.CategoryRequest](t, res).Category; got != "family" {
+			t.Errorf("reset category = %q, want family", got)
+		}
+	})
+
+	t.Run("deleted rule", func(t *testing.T) {
+		// rule of other user
+		rec, res := env.do(http.MethodDelete, fmt.Sprintf("/category/rules/%d", ruleID), nil, ani)
+		assertError(t, rec, res, http.StatusNotFound, "CATEGORY_RULE_NOT_FOUND")
+		rec, _ = env.do(http.MethodDelete, fmt.Sprintf("/category/rules/%d", ruleID), nil, budi)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d", rec.Code)
+		}
+		assertCategories(t, budi, map[string]string{"transfer:1": "food", "transfer:3": "other"})
+
+		rec, res = env.do(http.MethodDelete, fmt.Sprintf("/category/rules/%d", ruleID), nil, budi)
+		assertError(t, rec, res, http.StatusNotFound, "CATEGORY_RULE_NOT_FOUND")
+		// default rule isn't owned by anyone
+		rec, res = env.do(http.MethodDelete, "/category/rules/1", nil, budi)
+		assertError(t, rec, res, http.StatusNotFound, "CATEGORY_RULE_NOT_FOUND")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		rec, res := env.do(http.MethodPut, "/transaction/topup/1/category", models.CategoryRequest{Category: "food"}, ani)
+		assertError(t, rec, res, http.StatusNotFound, "TOPUP_NOT_FOUND")
+		rec, res = env.do(http.MethodPut, "/transaction/99/category", models.CategoryRequest{Category: "food"}, budi)
+		assertError(t, rec, res, http.StatusNotFound, "TRANSACTION_NOT_FOUND")
+		rec, res = env.do(http.MethodPut, "/transaction/1/category", models.CategoryRequest{Category: "rent"}, budi)
+		assertError(t, rec, res, http.StatusBadRequest, "VALIDATION_FAILED")
+
+		for _, body := range []models.CategoryRuleRequest{
+			{Field: "notes", Keyword: "   ", Category: "food"},
+			{Field: "amount", Keyword: "kopi", Category: "food"},
+			// rule only give spending category
+			{Field: "notes", Keyword: "gaji", Category: "income"},
+		} {
+			rec, res := env.do(http.MethodPost, "/category/rules", body, budi)
+			assertError(t, rec, res, http.StatusBadRequest, "VALIDATION_FAILED")
+		}
+		_, res = env.do(http.MethodPost, "/category/rules", models.CategoryRuleRequest{Field: "notes", Keyword: "   ", Category: "food"}, budi)
+		if res.Details != "keyword must not be blank" {
+			t.Errorf("details = %q, want message of catalog", res.Details)
+		}
+	})
+
+	t.Run("rule limit", func(t *testing.T) {
+		for i := range models.MaxCategoryRules {
+			rec, _ := env.do(http.MethodPost, "/category/rules", models.CategoryRuleRequest{Field: "notes", Keyword: fmt.Sprintf("k%d", i), Category: "other"}, ani)
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("rule %d status = %d", i, rec.Code)
+			}
+		}
+		rec, res := env.do(http.MethodPost, "/category/rules", models.CategoryRuleRequest{Field: "notes", Keyword: "more", Category: "other"}, ani)
+		assertError(t, rec, res, http.StatusBadRequest, "VALIDATION_FAILED")
+		if want := fmt.Sprintf("user can't have more than %d category rule", models.MaxCategoryRules); res.Details != want {
+			t.Errorf("details = %q, want %q", res.Details, want)
+		}
+	})
+}
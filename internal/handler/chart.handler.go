@@ -1,9 +1,12 @@
 package handler
 
 import (
-	"log"
+	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/Belalai-E-Wallet-Backend/internal/apperror"
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
 	"github.com/Belalai-E-Wallet-Backend/internal/models"
 	"github.com/Belalai-E-Wallet-Backend/internal/repository"
 	"github.com/Belalai-E-Wallet-Backend/internal/utils"
@@ -11,53 +14,266 @@ import (
 )
 
 type ChartHandler struct {
-	cr *repository.ChartRepository
+	cr  repository.ChartRepo
+	cfg *configs.Config
 }
 
-func NewChartHandler(cr *repository.ChartRepository) *ChartHandler {
-	return &ChartHandler{cr: cr}
+func NewChartHandler(cr repository.ChartRepo, cfg *configs.Config) *ChartHandler {
+	return &ChartHandler{cr: cr, cfg: cfg}
 }
 
 // @Summary Mendapatkan data chart berdasarkan durasi filter
-// @Description Mendapatkan data chart keuangan pengguna yang sudah diautentikasi (berdasarkan ID pengguna dari token JWT) dengan filter durasi tertentu.
+// @Description Mendapatkan data chart keuangan pengguna yang sudah diautentikasi (berdasarkan ID pengguna dari token JWT) dengan filter durasi tertentu. Hari ini dan bucket dihitung pada timezone tz (default APP_TIMEZONE).
 // @Tags Chart
 // @Accept json
 // @Produce json
-// @Param duration path string true "Durasi filter data chart (contoh: 'seven_days', 'five_weeks', 'twelve_months')"
+// @Param duration path string true "Durasi filter data chart" Enums(seven_days, five_weeks, twelve_months)
+// @Param tz query string false "IANA timezone, contoh: Asia/Jakarta"
 // @Success 200 {object} models.ChartDataResponse "Data chart berhasil diambil"
+// @Failure 400 {object} models.ErrorResponse "Durasi atau timezone tidak valid"
 // @Failure 401 {object} models.UnauthorizedResponse "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang"
 // @Failure 500 {object} models.InternalErrorResponse "Kesalahan server internal"
-// @Router /chart/{duration} [get]
+// @Router /v1/chart/{duration} [get]
 // @Security JWTtoken
 func (c *ChartHandler) GetDataChart(ctx *gin.Context) {
-	// get filter type
-	durationFilter := ctx.Param("duration")
+	loc, ok := c.location(ctx, ctx.Query("tz"))
+	if !ok {
+		return
+	}
+	r, err := models.ChartPreset(ctx.Param("duration"), time.Now().In(loc))
+	if err != nil {
+		ctx.Error(apperror.Validation(err))
+		return
+	}
+	c.chart(ctx, r)
+}
+
+// @Summary Mendapatkan data chart dengan rentang tanggal sendiri
+// @Description Pemasukan & pengeluaran per hari, minggu (label hari senin) atau bulan dari tanggal from sampai to (keduanya termasuk), dihitung pada timezone tz (default APP_TIMEZONE). Maksimal 366 bucket.
+// @Tags Chart
+// @Accept json
+// @Produce json
+// @Param from query string true "Tanggal mulai (YYYY-MM-DD)"
+// @Param to query string true "Tanggal akhir (YYYY-MM-DD)"
+// @Param granularity query string false "Ukuran bucket (default: day)" Enums(day, week, month)
+// @Param tz query string false "IANA timezone, contoh: Asia/Jakarta"
+// @Success 200 {object} models.ChartDataResponse "Data chart berhasil diambil"
+// @Failure 400 {object} models.ErrorResponse "Rentang, granularity atau timezone tidak valid"
+// @Failure 401 {object} models.UnauthorizedResponse "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang"
+// @Failure 500 {object} models.InternalErrorResponse "Kesalahan server internal"
+// @Router /v1/chart [get]
+// @Security JWTtoken
+func (c *ChartHandler) GetChartRange(ctx *gin.Context) {
+	var query models.ChartQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.Validation(err))
+		return
+	}
+	loc, ok := c.location(ctx, query.Timezone)
+	if !ok {
+		return
+	}
+	r, err := models.NewChartRange(query.From, query.To, query.Granularity, loc)
+	if err != nil {
+		ctx.Error(apperror.Validation(err))
+		return
+	}
+	c.chart(ctx, r)
+}
+
+// @Summary Pengeluaran per kategori
+// @Description Total transfer keluar yang sukses per kategori dari tanggal from sampai to (keduanya termasuk, default awal bulan ini sampai hari ini) pada timezone tz, urut dari yang terbesar.
+// @Tags Chart
+// @Accept json
+// @Produce json
+// @Param from query string false "Tanggal mulai (YYYY-MM-DD)"
+// @Param to query string false "Tanggal akhir (YYYY-MM-DD)"
+// @Param tz query string false "IANA timezone, contoh: Asia/Jakarta"
+// @Success 200 {object} models.ResponseData{data=models.CategoryBreakdown} "Pengeluaran per kategori berhasil diambil"
+// @Failure 400 {object} models.ErrorResponse "Rentang atau timezone tidak valid"
+// @Failure 401 {object} models.UnauthorizedResponse "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang"
+// @Failure 500 {object} models.InternalErrorResponse "Kesalahan server internal"
+// @Router /v1/chart/categories [get]
+// @Security JWTtoken
+func (c *ChartHandler) GetCategoryBreakdown(ctx *gin.Context) {
+	userID, _, r, ok := c.insight(ctx)
+	if !ok {
+		return
+	}
+
+	totals, err := c.cr.GetCategoryTotals(ctx.Request.Context(), userID, r)
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.ResponseData{
+		Response: models.Response{
+			IsSuccess: true,
+			Code:      http.StatusOK,
+		},
+		Data: models.NewCategoryBreakdown(r, totals),
+	})
+}
+
+// @Summary Penerima transfer terbanyak
+// @Description Pengguna yang paling banyak menerima transfer sukses dari pengguna dari tanggal from sampai to (keduanya termasuk, default awal bulan ini sampai hari ini) pada timezone tz, urut dari nominal terbesar.
+// @Tags Chart
+// @Accept json
+// @Produce json
+// @Param from query string false "Tanggal mulai (YYYY-MM-DD)"
+// @Param to query string false "Tanggal akhir (YYYY-MM-DD)"
+// @Param tz query string false "IANA timezone, contoh: Asia/Jakarta"
+// @Param limit query int false "Jumlah pengguna (default: 5, maksimal 50)"
+// @Success 200 {object} models.ResponseData{data=[]models.CounterpartyTotal} "Penerima terbanyak berhasil diambil"
+// @Failure 400 {object} models.ErrorResponse "Rentang, limit atau timezone tidak valid"
+// @Failure 401 {object} models.UnauthorizedResponse "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang"
+// @Failure 500 {object} models.InternalErrorResponse "Kesalahan server internal"
+// @Router /v1/chart/counterparties [get]
+// @Security JWTtoken
+func (c *ChartHandler) GetTopCounterparties(ctx *gin.Context) {
+	userID, query, r, ok := c.insight(ctx)
+	if !ok {
+		return
+	}
+	limit := 5
+	if query.Limit > 0 {
+		limit = query.Limit
+	}
+
+	totals, err := c.cr.GetTopCounterparties(ctx.Request.Context(), userID, r, limit)
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.ResponseData{
+		Response: models.Response{
+			IsSuccess: true,
+			Code:      http.StatusOK,
+		},
+		Data: totals,
+	})
+}
+
+// @Summary Perbandingan bulan ini dengan bulan sebelumnya
+// @Description Pemasukan, pengeluaran dan pengeluaran per kategori pada month (default bulan ini) dibandingkan dengan bulan sebelumnya, dihitung pada timezone tz. percentage null ketika bulan sebelumnya 0.
+// @Tags Chart
+// @Accept json
+// @Produce json
+// @Param month query string false "Bulan (YYYY-MM)"
+// @Param tz query string false "IANA timezone, contoh: Asia/Jakarta"
+// @Success 200 {object} models.ResponseData{data=models.MonthComparison} "Perbandingan berhasil diambil"
+// @Failure 400 {object} models.ErrorResponse "Bulan atau timezone tidak valid"
+// @Failure 401 {object} models.UnauthorizedResponse "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang"
+// @Failure 500 {object} models.InternalErrorResponse "Kesalahan server internal"
+// @Router /v1/chart/compare [get]
+// @Security JWTtoken
+func (c *ChartHandler) GetMonthComparison(ctx *gin.Context) {
+	var query models.CompareQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.Validation(err))
+		return
+	}
+	loc, ok := c.location(ctx, query.Timezone)
+	if !ok {
+		return
+	}
+	userID, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	month := time.Now().In(loc)
+	if query.Month != nil {
+		month = *query.Month
+	}
+	month = time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, loc)
+	previous := month.AddDate(0, -1, 0)
+	// both month on one chart, then spending per category of each month
+	ranges := [3]models.ChartRange{}
+	for i, period := range [3][2]time.Time{
+		{previous, month.AddDate(0, 1, -1)},
+		{month, month.AddDate(0, 1, -1)},
+		{previous, month.AddDate(0, 0, -1)},
+	} {
+		if ranges[i], err = models.NewChartRange(period[0], period[1], "month", loc); err != nil {
+			ctx.Error(apperror.Validation(err))
+			return
+		}
+	}
+
+	chart, err := c.cr.GetChartData(ctx.Request.Context(), userID, ranges[0])
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+	var totals [2][]models.CategoryTotal
+	for i := range totals {
+		if totals[i], err = c.cr.GetCategoryTotals(ctx.Request.Context(), userID, ranges[i+1]); err != nil {
+			ctx.Error(apperror.Wrap(apperror.Internal, err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, models.ResponseData{
+		Response: models.Response{
+			IsSuccess: true,
+			Code:      http.StatusOK,
+		},
+		Data: models.NewMonthComparison(ranges[0], chart, totals[0], totals[1]),
+	})
+}
+
+// insight bind query & its range of user, error is already sent when it return false
+func (c *ChartHandler) insight(ctx *gin.Context) (userID int, query models.InsightQuery, r models.ChartRange, ok bool) {
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.Validation(err))
+		return
+	}
+	loc, ok := c.location(ctx, query.Timezone)
+	if !ok {
+		return
+	}
+	r, err := query.Range(time.Now(), loc)
+	if err != nil {
+		ctx.Error(apperror.Validation(err))
+		return userID, query, r, false
+	}
+	if userID, err = utils.GetUserFromCtx(ctx); err != nil {
+		ctx.Error(err)
+		return userID, query, r, false
+	}
+	return userID, query, r, true
+}
+
+// location of tz, default to APP_TIMEZONE, error is already sent when it return false
+func (c *ChartHandler) location(ctx *gin.Context, tz string) (*time.Location, bool) {
+	if tz == "" {
+		tz = c.cfg.App.Timezone
+	}
+	loc, err := time.LoadLocation(tz)
+	// Local is timezone of the server, not of the user
+	if err != nil || tz == "Local" {
+		ctx.Error(apperror.Validation(fmt.Errorf("tz %q is not a valid IANA timezone", tz)))
+		return nil, false
+	}
+	return loc, true
+}
 
+func (c *ChartHandler) chart(ctx *gin.Context, r models.ChartRange) {
 	// Get user ID from JWT token in context
 	userID, err := utils.GetUserFromCtx(ctx)
 	if err != nil {
-		log.Println("Error getting user from context.\nCause: ", err.Error())
-		ctx.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      401,
-			},
-			Err: "Unauthorized: " + err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
 	// get data from repository by user ID
-	chartData, err := c.cr.GetChartData(ctx.Request.Context(), userID, durationFilter)
+	chartData, err := c.cr.GetChartData(ctx.Request.Context(), userID, r)
 	if err != nil {
-		log.Println("error cause: \n", err)
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: "internal server error",
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
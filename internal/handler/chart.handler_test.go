@@ -0,0 +1,233 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository/memory"
+)
+
+func TestGetChartRange(t *testing.T) {
+	env := newTestEnv(t)
+	budi, budiWallet := env.store.AddUser("budi@mail.com", hash(t, "Rahasia#123"), "", 100000)
+	ani, aniWallet := env.store.AddUser("ani@mail.com", hash(t, "Rahasia#123"), "", 100000)
+
+	// 01:30 on 2 March in Jakarta, still 1 March in UTC
+	now := time.Date(2026, 3, 1, 18, 30, 0, 0, time.UTC)
+	env.store.SetClock(func() time.Time { return now })
+	transfer := memory.NewTransferRepository(env.store)
+	c := context.Background()
+	if _, err := transfer.TransferMoney(c, budi, models.TransferBody{IdReceiver: aniWallet, Amount: 4000}); err != nil {
+		t.Fatal(err)
+	}
+	now = time.Date(2026, 2, 10, 3, 0, 0, 0, time.UTC)
+	if _, err := transfer.TransferMoney(c, ani, models.TransferBody{IdReceiver: budiWallet, Amount: 10000}); err != nil {
+		t.Fatal(err)
+	}
+
+	chart := func(t *testing.T, path string) models.ChartData {
+		t.Helper()
+		rec, res := env.do(http.MethodGet, path, nil, budi)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+		}
+		return decodeData[models.ChartData](t, res)
+	}
+
+	t.Run("bucket in timezone of user", func(t *testing.T) {
+		data := chart(t, "/chart?from=2026-03-01&to=2026-03-02")
+		if !slices.Equal(data.Labels, []string{"2026-03-01", "2026-03-02"}) || !slices.Equal(data.ExpenseData, []int{0, 4000}) {
+			t.Errorf("default (Asia/Jakarta) = %+v, want expense on 2 March", data)
+		}
+		if data.Timezone != "Asia/Jakarta" || data.Granularity != "day" {
+			t.Errorf("timezone = %q, granularity = %q", data.Timezone, data.Granularity)
+		}
+
+		data = chart(t, "/chart?from=2026-03-01&to=2026-03-02&tz=UTC")
+		if !slices.Equal(data.ExpenseData, []int{4000, 0}) {
+			t.Errorf("UTC expense = %v, want on 1 March", data.ExpenseData)
+		}
+	})
+
+	t.Run("week and month", func(t *testing.T) {
+		data := chart(t, "/chart?from=2026-02-04&to=2026-03-04&granularity=week")
+		// first & last week is partial, labelled by its monday
+		want := []string{"2026-02-02", "2026-02-09", "2026-02-16", "2026-02-23", "2026-03-02"}
+		if !slices.Equal(data.Labels, want) {
+			t.Errorf("labels = %v, want %v", data.Labels, want)
+		}
+		if !slices.Equal(data.IncomeData, []int{0, 10000, 0, 0, 0}) || !slices.Equal(data.ExpenseData, []int{0, 0, 0, 0, 4000}) {
+			t.Errorf("income = %v, expense = %v", data.IncomeData, data.ExpenseData)
+		}
+
+		data = chart(t, "/chart?from=2026-01-15&to=2026-03-31&granularity=month")
+		if !slices.Equal(data.Labels, []string{"2026-01", "2026-02", "2026-03"}) || !slices.Equal(data.IncomeData, []int{0, 10000, 0}) {
+			t.Errorf("month = %+v", data)
+		}
+
+		// transaction outside from & to isn't counted even when its bucket is shown
+		data = chart(t, "/chart?from=2026-02-11&to=2026-02-28&granularity=month")
+		if !slices.Equal(data.IncomeData, []int{0}) {
+			t.Errorf("income = %v, want nothing after 10 February", data.IncomeData)
+		}
+	})
+
+	t.Run("max range", func(t *testing.T) {
+		// leap year is exactly the max, DST on the timezone doesn't change the count
+		if data := chart(t, "/chart?from=2024-01-01&to=2024-12-31&tz=Europe/Berlin"); len(data.Labels) != models.MaxChartBuckets {
+			t.Errorf("labels = %d, want %d", len(data.Labels), models.MaxChartBuckets)
+		}
+		if data := chart(t, "/chart?from=2025-01-01&to=2025-12-31&granularity=week"); len(data.Labels) != 53 {
+			t.Errorf("labels = %d, want 53 week", len(data.Labels))
+		}
+	})
+
+	t.Run("preset", func(t *testing.T) {
+		data := chart(t, "/chart/seven_days?tz=Asia/Jakarta")
+		jakarta, err := time.LoadLocation("Asia/Jakarta")
+		if err != nil {
+			t.Fatal(err)
+		}
+		today := time.Now().In(jakarta).Format(time.DateOnly)
+		if len(data.Labels) != 7 || data.Labels[6] != today {
+			t.Errorf("labels = %v, want 7 day ending %s", data.Labels, today)
+		}
+		if data := chart(t, "/chart/twelve_months"); len(data.Labels) != 12 || data.Granularity != "month" {
+			t.Errorf("twelve_months = %+v", data)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, path := range []string{
+			"/chart/ten_days",
+			"/chart/seven_days?tz=Mars/Olympus",
+			"/chart?to=2026-03-01",
+			"/chart?from=2026-03-02&to=2026-03-01",
+			"/chart?from=2026-03-01&to=2026-03-02&granularity=year",
+			"/chart?from=2026-03-01&to=2026-03-02&tz=Local",
+			"/chart?from=2020-01-01&to=2026-01-01",
+			"/chart?from=2025-01-01&to=2026-01-02",
+			// rejected before any bucket is built
+			"/chart?from=0001-01-01&to=9999-12-31",
+			"/chart?from=0001-01-01&to=9999-12-31&granularity=week",
+			"/chart?from=0001-01-01&to=9999-12-31&granularity=month",
+		} {
+			rec, res := env.do(http.MethodGet, path, nil, budi)
+			assertError(t, rec, res, http.StatusBadRequest, "VALIDATION_FAILED")
+		}
+	})
+}
+
+func TestChartInsight(t *testing.T) {
+	env := newTestEnv(t)
+	budi, budiWallet := env.store.AddUser("budi@mail.com", hash(t, "Rahasia#123"), "", 100000)
+	ani, aniWallet := env.store.AddUser("ani@mail.com", hash(t, "Rahasia#123"), "", 100000)
+	cici, ciciWallet := env.store.AddUser("cici@mail.com", hash(t, "Rahasia#123"), "", 0)
+
+	now := time.Now()
+	env.store.SetClock(func() time.Time { return now })
+	transfer := memory.NewTransferRepository(env.store)
+	c := context.Background()
+	for _, tt := range []struct {
+		at       time.Time
+		sender   int
+		receiver int
+		amount   int
+		notes    string
+	}{
+		{time.Date(2026, 2, 10, 3, 0, 0, 0, time.UTC), budi, aniWallet, 3000, "makan"},
+		{time.Date(2026, 3, 5, 3, 0, 0, 0, time.UTC), budi, aniWallet, 5000, "makan"},
+		{time.Date(2026, 3, 6, 3, 0, 0, 0, time.UTC), budi, aniWallet, 2000, "netflix"},
+		{time.Date(2026, 3, 7, 3, 0, 0, 0, time.UTC), budi, ciciWallet, 1000, "titip"},
+		{time.Date(2026, 3, 8, 3, 0, 0, 0, time.UTC), ani, budiWallet, 10000, "makan"},
+		// 1 April in Jakarta, not counted on March
+		{time.Date(2026, 3, 31, 18, 0, 0, 0, time.UTC), budi, ciciWallet, 9500, "makan"},
+	} {
+		now = tt.at
+		if _, err := transfer.TransferMoney(c, tt.sender, models.TransferBody{IdReceiver: tt.receiver, Amount: tt.amount, Notes: tt.notes}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	get := func(t *testing.T, path string) testResponse {
+		t.Helper()
+		rec, res := env.do(http.MethodGet, path, nil, budi)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+		}
+		return res
+	}
+
+	t.Run("spending per category", func(t *testing.T) {
+		data := decodeData[models.CategoryBreakdown](t, get(t, "/chart/categories?from=2026-03-01&to=2026-03-31"))
+		want := []models.CategoryTotal{
+			{Category: "food", Amount: 5000, Count: 1, Percentage: 62.5},
+			{Category: "entertainment", Amount: 2000, Count: 1, Percentage: 25},
+			{Category: "other", Amount: 1000, Count: 1, Percentage: 12.5},
+		}
+		if data.Total != 8000 || !slices.Equal(data.Categories, want) {
+			t.Errorf("breakdown = %+v, want total 8000 and %+v", data, want)
+		}
+		if data.From != "2026-03-01" || data.To != "2026-03-31" || data.Timezone != "Asia/Jakarta" {
+			t.Errorf("range = %s - %s %s", data.From, data.To, data.Timezone)
+		}
+
+		data = decodeData[models.CategoryBreakdown](t, get(t, "/chart/categories?from=2026-03-01&to=2026-03-31&tz=UTC"))
+		if data.Total != 17500 {
+			t.Errorf("UTC total = %d, want transfer of 31 March counted", data.Total)
+		}
+	})
+
+	t.Run("top counterparties", func(t *testing.T) {
+		data := decodeData[[]models.CounterpartyTotal](t, get(t, "/chart/counterparties?from=2026-01-01&to=2026-04-30"))
+		if len(data) != 2 || data[0].UserID != cici || data[0].Amount != 10500 || data[1].UserID != ani || data[1].Count != 3 {
+			t.Errorf("counterparties = %+v, want cici then ani", data)
+		}
+		data = decodeData[[]models.CounterpartyTotal](t, get(t, "/chart/counterparties?from=2026-03-01&to=2026-03-31&limit=1"))
+		if len(data) != 1 || data[0] != (models.CounterpartyTotal{UserID: ani, Amount: 7000, Count: 2}) {
+			t.Errorf("limit 1 = %+v, want ani only", data)
+		}
+	})
+
+	t.Run("month over month", func(t *testing.T) {
+		data := decodeData[models.MonthComparison](t, get(t, "/chart/compare?month=2026-03"))
+		if data.Month != "2026-03" || data.PreviousMonth != "2026-02" {
+			t.Fatalf("month = %s, previous = %s", data.Month, data.PreviousMonth)
+		}
+		if e := data.Expense; e.Current != 8000 || e.Previous != 3000 || e.Difference != 5000 || e.Percentage == nil || *e.Percentage != 166.67 {
+			t.Errorf("expense = %+v", e)
+		}
+		if i := data.Income; i.Current != 10000 || i.Previous != 0 || i.Percentage != nil {
+			t.Errorf("income = %+v, want no percentage from 0", i)
+		}
+		var got []string
+		for _, cc := range data.Categories {
+			got = append(got, fmt.Sprintf("%s:%d:%d", cc.Category, cc.Current, cc.Previous))
+		}
+		if want := []string{"food:5000:3000", "entertainment:2000:0", "other:1000:0"}; !slices.Equal(got, want) {
+			t.Errorf("categories = %v, want %v", got, want)
+		}
+
+		data = decodeData[models.MonthComparison](t, get(t, "/chart/compare?month=2026-04"))
+		if len(data.Categories) != 3 || data.Categories[0].Current != 9500 || data.Categories[1].Category != "entertainment" {
+			t.Errorf("april = %+v, want food first then category only spent on march", data.Categories)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, path := range []string{
+			"/chart/categories?from=2026-03-02&to=2026-03-01",
+			"/chart/categories?tz=Mars/Olympus",
+			"/chart/counterparties?limit=51",
+			"/chart/compare?month=2026-13",
+		} {
+			rec, res := env.do(http.MethodGet, path, nil, budi)
+			assertError(t, rec, res, http.StatusBadRequest, "VALIDATION_FAILED")
+		}
+	})
+}
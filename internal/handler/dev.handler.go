@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/apperror"
+	"github.com/Belalai-E-Wallet-Backend/internal/i18n"
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// DevHandler only registered when mail transport is file or memory
+type DevHandler struct {
+	mailbox utils.MailCapture
+}
+
+func NewDevHandler(mailbox utils.MailCapture) *DevHandler {
+	return &DevHandler{mailbox: mailbox}
+}
+
+// ListMails
+// @tags 			dev
+// @router 	 		/dev/mails 	[GET]
+// @Summary 		List captured emails
+// @Description 	List emails captured by file/memory mail transport, newest first. Only available on local development
+// @produce 		json
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{object}  	models.ResponseData "Captured emails"
+func (d *DevHandler) ListMails(ctx *gin.Context) {
+	mails, err := d.mailbox.List(ctx.Request.Context())
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.ResponseData{
+		Response: models.Response{
+			IsSuccess: true,
+			Code:      http.StatusOK,
+			Msg:       i18n.T(ctx, i18n.MsgCapturedMailsFetched),
+		},
+		Data: mails,
+	})
+}
+
+// GetMail
+// @tags 			dev
+// @router 	 		/dev/mails/{id} 	[GET]
+// @Summary 		View captured email
+// @Description 	Render captured email in browser, use format=text for plain-text alternative or format=json for raw data
+// @produce 		html
+// @Param			id		path	string	true	"Mail ID"
+// @Param			format	query	string	false	"html (default), text or json"
+// @failure 		404			{object} 	models.NotFoundResponse "Mail Not Found"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{string}  	string "Email body"
+func (d *DevHandler) GetMail(ctx *gin.Context) {
+	mail, err := d.mailbox.Get(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		if errors.Is(err, utils.ErrMailNotFound) {
+			ctx.Error(apperror.New(apperror.MailNotFound))
+			return
+		}
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+
+	switch ctx.Query("format") {
+	case "json":
+		ctx.JSON(http.StatusOK, models.ResponseData{
+			Response: models.Response{
+				IsSuccess: true,
+				Code:      http.StatusOK,
+			},
+			Data: mail,
+		})
+	case "text":
+		text := mail.AltBody
+		if !mail.BodyIsHTML {
+			text = mail.Body
+		}
+		ctx.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(text))
+	default:
+		contentType := "text/html; charset=utf-8"
+		if !mail.BodyIsHTML {
+			contentType = "text/plain; charset=utf-8"
+		}
+		ctx.Data(http.StatusOK, contentType, []byte(mail.Body))
+	}
+}
+
+// ClearMails
+// @tags 			dev
+// @router 	 		/dev/mails 	[DELETE]
+// @Summary 		Delete all captured emails
+// @produce 		json
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{object}  	models.Response
+func (d *DevHandler) ClearMails(ctx *gin.Context) {
+	if err := d.mailbox.Clear(ctx.Request.Context()); err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.Response{
+		IsSuccess: true,
+		Code:      http.StatusOK,
+		Msg:       i18n.T(ctx, i18n.MsgCapturedMailsDeleted),
+	})
+}
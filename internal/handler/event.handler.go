@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/apperror"
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
+	"github.com/Belalai-E-Wallet-Backend/internal/middleware"
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// interval ping to keep connection alive behind proxy
+const heartbeatInterval = 25 * time.Second
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin: func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		// non browser client doesn't send origin
+		return origin == "" || middleware.IsAllowedOrigin(origin)
+	},
+}
+
+type EventHandler struct {
+	er repository.EventRepo
+	wr repository.EWalletRepo
+	bg *utils.Background
+}
+
+func NewEventHandler(er repository.EventRepo, wr repository.EWalletRepo, bg *utils.Background) *EventHandler {
+	return &EventHandler{er: er, wr: wr, bg: bg}
+}
+
+// StreamEvents
+// @tags 			events
+// @router 	 		/v1/events/stream 	[GET]
+// @Summary 		Stream balance and transaction events (Server-Sent Events)
+// @Description 	Push balance changes and new transactions to all connected devices of authenticated user. Token can be sent as query because EventSource can't set header
+// @produce 		text/event-stream
+// @Security 		JWTtoken
+// @param 			token 		query 		string 	false "JWT token, used when Authorization header can't be set"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{object}  	models.UserEvent "Stream of events"
+func (eh *EventHandler) StreamEvents(ctx *gin.Context) {
+	userID, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	pubsub, err := eh.er.Subscribe(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+	defer pubsub.Close()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	// disable buffering on nginx
+	ctx.Header("X-Accel-Buffering", "no")
+
+	// send current balance first, so device is in sync before receiving new events
+	if event, ok := eh.currentBalanceEvent(ctx, userID); ok {
+		ctx.SSEvent(string(event.Type), event)
+		ctx.Writer.Flush()
+	}
+
+	// stream is long-lived, so it is excluded from server write timeout
+	if err := http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		logger.FromContext(ctx).Error("Failed disable write deadline", "err", err)
+	}
+
+	messages := pubsub.Channel()
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case <-eh.bg.Stopping():
+			// server is shutting down, client will reconnect to other instance
+			return false
+		case msg, ok := <-messages:
+			if !ok {
+				return false
+			}
+			var event models.UserEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				logger.FromContext(ctx).Error("Invalid user event payload", "err", err)
+				return true
+			}
+			ctx.SSEvent(string(event.Type), msg.Payload)
+			return true
+		case <-heartbeat.C:
+			ctx.SSEvent("ping", time.Now().Unix())
+			return true
+		}
+	})
+}
+
+// StreamWebSocket
+// @tags 			events
+// @router 	 		/v1/events/ws 	[GET]
+// @Summary 		Stream balance and transaction events (WebSocket)
+// @Description 	Same events as /events/stream but delivered as websocket text message (JSON)
+// @Security 		JWTtoken
+// @param 			token 		query 		string 	false "JWT token, used when Authorization header can't be set"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		101 		{object}  	models.UserEvent "Switching protocol"
+func (eh *EventHandler) StreamWebSocket(ctx *gin.Context) {
+	userID, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	pubsub, err := eh.er.Subscribe(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+	defer pubsub.Close()
+
+	// upgrader already write error response if failed
+	conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed upgrade websocket", "err", err)
+		return
+	}
+	defer conn.Close()
+
+	// read message only to detect client close the connection
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if event, ok := eh.currentBalanceEvent(ctx, userID); ok {
+		if err := conn.WriteJSON(event); err != nil {
+			return
+		}
+	}
+
+	messages := pubsub.Channel()
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-eh.bg.Stopping():
+			conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"), time.Now().Add(5*time.Second))
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(msg.Payload)); err != nil {
+				logger.FromContext(ctx).Error("Failed write websocket message", "err", err)
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (eh *EventHandler) currentBalanceEvent(ctx *gin.Context, userID int) (models.UserEvent, bool) {
+	balance, err := eh.wr.GetBalance(ctx.Request.Context(), userID)
+	if err != nil {
+		logger.FromContext(ctx).Error("Error getting balance for initial event", "err", err)
+		return models.UserEvent{}, false
+	}
+	return models.UserEvent{
+		Type:      models.EventBalanceUpdated,
+		Data:      models.BalanceEvent{Balance: balance.Balance},
+		CreatedAt: time.Now(),
+	}, true
+}
@@ -1,9 +1,11 @@
 package handler
 
 import (
-	"log"
+	"errors"
 	"net/http"
 
+	"github.com/Belalai-E-Wallet-Backend/internal/apperror"
+	"github.com/Belalai-E-Wallet-Backend/internal/i18n"
 	"github.com/Belalai-E-Wallet-Backend/internal/models"
 	"github.com/Belalai-E-Wallet-Backend/internal/repository"
 	"github.com/Belalai-E-Wallet-Backend/internal/utils" // Import utils package
@@ -11,16 +13,16 @@ import (
 )
 
 type EWalletHandler struct {
-	er *repository.EwalletRepository
+	er repository.EWalletRepo
 }
 
-func NewEWalletHandler(er *repository.EwalletRepository) *EWalletHandler {
+func NewEWalletHandler(er repository.EWalletRepo) *EWalletHandler {
 	return &EWalletHandler{er: er}
 }
 
 // GetBalance
 // @tags 			balance
-// @router 	 		/balance 	[GET]
+// @router 	 		/v1/balance 	[GET]
 // @Summary 		Get user balance
 // @Description 	Get balance for authenticated user
 // @accept 			json
@@ -34,41 +36,20 @@ func (e *EWalletHandler) GetBalance(ctx *gin.Context) {
 	// Get user ID from JWT token in context
 	userID, err := utils.GetUserFromCtx(ctx)
 	if err != nil {
-		log.Println("Error getting user from context.\nCause: ", err.Error())
-		ctx.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      401,
-			},
-			Err: "Unauthorized: " + err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
 	// Get balance from repository using user ID from token
 	balance, err := e.er.GetBalance(ctx, userID)
 	if err != nil {
-		log.Println("Error getting balance from repository.\nCause: ", err.Error())
-
-		// Check if user not found
-		if err.Error() == "user id not found" {
-			ctx.JSON(http.StatusNotFound, models.ErrorResponse{
-				Response: models.Response{
-					IsSuccess: false,
-					Code:      404,
-				},
-				Err: "User not found",
-			})
+		// user doesn't have wallet
+		if errors.Is(err, repository.ErrUserNotFound) {
+			ctx.Error(apperror.New(apperror.WalletNotFound))
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      500,
-			},
-			Err: "Failed to retrieve balance",
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
@@ -76,7 +57,7 @@ func (e *EWalletHandler) GetBalance(ctx *gin.Context) {
 		Response: models.Response{
 			IsSuccess: true,
 			Code:      http.StatusOK,
-			Msg:       "Get Balance Success",
+			Msg:       i18n.T(ctx, i18n.MsgBalanceFetched),
 		},
 		Data: *balance,
 	})
@@ -0,0 +1,222 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+	"github.com/Belalai-E-Wallet-Backend/internal/handler"
+	"github.com/Belalai-E-Wallet-Backend/internal/middleware"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository/memory"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/Belalai-E-Wallet-Backend/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+// header used by test to act as logged in user instead of sending JWT
+const testUserHeader = "X-Test-User"
+
+type testEnv struct {
+	t      *testing.T
+	store  *memory.Store
+	mailer *utils.MemoryMailer
+	bg     *utils.Background
+	cfg    *configs.Config
+	router *gin.Engine
+}
+
+// response of every endpoint, data is decoded later into the expected type
+type testResponse struct {
+	IsSuccess bool            `json:"is_success"`
+	Code      int             `json:"code"`
+	Msg       string          `json:"message"`
+	Err       string          `json:"error"`
+	ErrCode   string          `json:"error_code"`
+	Details   string          `json:"details"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// newTestEnv mount handler with in-memory repositories on the same path as routers
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	env := &testEnv{
+		t:      t,
+		store:  memory.NewStore(),
+		mailer: utils.NewMemoryMailer("test@belalai.local", 0),
+		bg:     utils.NewBackground(),
+		cfg: &configs.Config{
+			App:     configs.AppConfig{FrontendURL: "http://localhost:5173", Timezone: "Asia/Jakarta"},
+			JWT:     configs.JWTConfig{Secret: "test-secret", Issuer: "test", TTL: time.Minute},
+			Receipt: configs.ReceiptConfig{Secret: "receipt-secret", VerifyURL: "http://localhost:5173/receipt"},
+			Trash:   configs.TrashConfig{Retention: 30 * 24 * time.Hour, PurgeInterval: time.Hour},
+		},
+	}
+
+	router := gin.New()
+	router.ContextWithFallback = true
+	router.Use(middleware.Locale, middleware.ErrorHandler)
+	authed := router.Group("", testAuth)
+
+	authHandler := handler.NewAuthHandler(memory.NewAuthRepository(env.store), env.mailer, env.bg, env.cfg)
+	router.POST("/auth", authHandler.Login)
+	router.POST("/auth/register", authHandler.Register)
+
+	budgetHandler := handler.NewBudgetHandler(memory.NewBudgetRepository(env.store), memory.NewChartRepository(env.store), env.mailer, env.bg, env.cfg)
+	authed.GET("/budgets", budgetHandler.GetBudgets)
+	authed.PUT("/budgets", budgetHandler.SaveBudget)
+	authed.DELETE("/budgets/:id", budgetHandler.DeleteBudget)
+
+	transferHandler := handler.NewTransferHandler(memory.NewTransferRepository(env.store), env.mailer, env.bg, env.cfg, budgetHandler)
+	authed.GET("/transfer", transferHandler.FilterUser)
+	authed.POST("/transfer", transferHandler.TranferBalance)
+
+	topUpHandler := handler.NewTopUpHandler(memory.NewTopUpRepository(env.store))
+	authed.GET("/topup/methods", topUpHandler.GetPaymentMethods)
+	authed.POST("/topup", topUpHandler.CreateTopUpTransaction)
+
+	transactionHandler := handler.NewTransactionHandler(memory.NewTransactionRepository(env.store), env.mailer, env.bg, env.cfg)
+	authed.GET("/transaction/history", transactionHandler.GetTransactionHistory)
+	authed.GET("/transaction/history/all", transactionHandler.GetAllTransactionHistory)
+	authed.GET("/transaction/statement", transactionHandler.GetStatement)
+	authed.GET("/transaction/trash", transactionHandler.GetTrash)
+	authed.POST("/transaction/trash", transactionHandler.HideTransactions)
+	authed.POST("/transaction/trash/restore", transactionHandler.RestoreTransactions)
+	authed.GET("/transaction/:id", transactionHandler.GetTransactionDetail)
+	authed.GET("/transaction/:id/receipt", transactionHandler.GetTransactionReceipt)
+	authed.GET("/transaction/topup/:id", transactionHandler.GetTopupDetail)
+	authed.GET("/transaction/topup/:id/receipt", transactionHandler.GetTopupReceipt)
+	authed.DELETE("/transaction/:id", transactionHandler.DeleteTransaction)
+	authed.DELETE("/transaction/topup/:id", transactionHandler.DeleteTopup)
+	authed.POST("/transaction/:id/restore", transactionHandler.RestoreTransaction)
+	authed.POST("/transaction/topup/:id/restore", transactionHandler.RestoreTopup)
+	authed.PUT("/transaction/:id/category", transactionHandler.SetTransactionCategory)
+	authed.PUT("/transaction/topup/:id/category", transactionHandler.SetTopupCategory)
+	router.GET("/receipt/verify/:code", transactionHandler.VerifyReceipt)
+
+	chartHandler := handler.NewChartHandler(memory.NewChartRepository(env.store), env.cfg)
+	authed.GET("/chart", chartHandler.GetChartRange)
+	authed.GET("/chart/categories", chartHandler.GetCategoryBreakdown)
+	authed.GET("/chart/counterparties", chartHandler.GetTopCounterparties)
+	authed.GET("/chart/compare", chartHandler.GetMonthComparison)
+	authed.GET("/chart/:duration", chartHandler.GetDataChart)
+
+	categoryHandler := handler.NewCategoryHandler(memory.NewCategoryRepository(env.store))
+	authed.GET("/category", categoryHandler.GetCategories)
+	authed.GET("/category/rules", categoryHandler.GetRules)
+	authed.POST("/category/rules", categoryHandler.CreateRule)
+	authed.DELETE("/category/rules/:id", categoryHandler.DeleteRule)
+
+	env.router = router
+	t.Cleanup(func() { env.waitBackground() })
+	return env
+}
+
+// set claims like VerifyToken when test user header is sent
+func testAuth(ctx *gin.Context) {
+	if id, err := strconv.Atoi(ctx.GetHeader(testUserHeader)); err == nil {
+		ctx.Set("claims", pkg.NewJWTClaims(id, "user", "test", time.Minute))
+	}
+	ctx.Next()
+}
+
+// waitBackground wait until email sent by handler is done, task started after it is cancelled immediately
+func (env *testEnv) waitBackground() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := env.bg.Wait(ctx); err != nil {
+		env.t.Fatalf("background task doesn't finish: %v", err)
+	}
+}
+
+// hash with cheap argon2 parameter, compare read the parameter from the hash
+func hash(t *testing.T, secret string) string {
+	t.Helper()
+	hc := pkg.NewHashConfig()
+	hc.SetConfig(1024, 1, 32, 16, 1)
+	hashed, err := hc.GenHash(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hashed
+}
+
+// do send request, userID 0 mean request is unauthenticated
+func (env *testEnv) do(method, path string, body any, userID int) (*httptest.ResponseRecorder, testResponse) {
+	env.t.Helper()
+
+	var payload []byte
+	if body != nil {
+		bt, err := json.Marshal(body)
+		if err != nil {
+			env.t.Fatal(err)
+		}
+		payload = bt
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "en")
+	if userID != 0 {
+		req.Header.Set(testUserHeader, strconv.Itoa(userID))
+	}
+
+	rec := httptest.NewRecorder()
+	env.router.ServeHTTP(rec, req)
+
+	var res testResponse
+	if rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			env.t.Fatalf("invalid response body %q: %v", rec.Body.String(), err)
+		}
+	}
+	return rec, res
+}
+
+func (env *testEnv) balance(userID int) int {
+	env.t.Helper()
+	b, err := memory.NewEWalletRepository(env.store).GetBalance(context.Background(), userID)
+	if err != nil {
+		env.t.Fatal(err)
+	}
+	return b.Balance
+}
+
+func decodeData[T any](t *testing.T, res testResponse) T {
+	t.Helper()
+	var data T
+	if err := json.Unmarshal(res.Data, &data); err != nil {
+		t.Fatalf("invalid data %s: %v", res.Data, err)
+	}
+	return data
+}
+
+// assertError check status and machine readable error code of failed request
+func assertError(t *testing.T, rec *httptest.ResponseRecorder, res testResponse, status int, code string) {
+	t.Helper()
+	if rec.Code != status {
+		t.Errorf("status = %d, want %d (body %s)", rec.Code, status, rec.Body.String())
+	}
+	if res.IsSuccess {
+		t.Errorf("is_success = true, want false")
+	}
+	if res.ErrCode != code {
+		t.Errorf("error_code = %q, want %q", res.ErrCode, code)
+	}
+}
+
+// wait for mails sent in background then return them
+func (env *testEnv) sentMails() []utils.CapturedMail {
+	env.t.Helper()
+	env.waitBackground()
+	mails, err := env.mailer.List(context.Background())
+	if err != nil {
+		env.t.Fatal(err)
+	}
+	return mails
+}
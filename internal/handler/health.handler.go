@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/db"
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// max time of each dependency check, so readiness never hang when dependency is down
+const healthCheckTimeout = 2 * time.Second
+
+type HealthHandler struct {
+	hr     repository.HealthRepo
+	mailer utils.Mailer
+	bg     *utils.Background
+}
+
+func NewHealthHandler(hr repository.HealthRepo, mailer utils.Mailer, bg *utils.Background) *HealthHandler {
+	return &HealthHandler{hr: hr, mailer: mailer, bg: bg}
+}
+
+// Liveness
+// @tags 			health
+// @router 	 		/healthz 	[GET]
+// @Summary 		Liveness probe
+// @Description 	Always ok while the process is able to serve HTTP, doesn't check dependency
+// @produce 		json
+// @success 		200 		{object}  	models.HealthStatus
+func (h *HealthHandler) Liveness(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, models.HealthStatus{Status: models.HealthOK})
+}
+
+// Readiness
+// @tags 			health
+// @router 	 		/readyz 	[GET]
+// @Summary 		Readiness probe
+// @Description 	Check postgres, redis, mail transport and migration version. Return 503 when one of them is down or server is draining on shutdown
+// @produce 		json
+// @success 		200 		{object}  	models.HealthStatus
+// @failure 		503 		{object}  	models.HealthStatus
+func (h *HealthHandler) Readiness(ctx *gin.Context) {
+	checks := map[string]func(c context.Context) (any, error){
+		"postgres":  func(c context.Context) (any, error) { return nil, h.hr.PingDB(c) },
+		"redis":     func(c context.Context) (any, error) { return nil, h.hr.PingRedis(c) },
+		"migration": h.checkMigration,
+	}
+	if pinger, ok := h.mailer.(utils.MailPinger); ok {
+		checks["mail"] = func(c context.Context) (any, error) { return nil, pinger.Ping(c) }
+	}
+
+	result := models.HealthStatus{Status: models.HealthOK, Checks: make(map[string]models.DependencyStatus, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c, cancel := context.WithTimeout(ctx.Request.Context(), healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			detail, err := check(c)
+			status := models.DependencyStatus{
+				Status:    models.HealthOK,
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+				Detail:    detail,
+			}
+			if err != nil {
+				status.Status = models.HealthDown
+				status.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.Checks[name] = status
+			if err != nil {
+				result.Status = models.HealthDown
+			}
+		}()
+	}
+	wg.Wait()
+
+	// draining win over dependency status, orchestrator must stop sending traffic
+	select {
+	case <-h.bg.Stopping():
+		result.Status = models.HealthDraining
+	default:
+	}
+
+	code := http.StatusOK
+	if result.Status != models.HealthOK {
+		code = http.StatusServiceUnavailable
+	}
+	ctx.JSON(code, result)
+}
+
+// schema must be migrated to version embedded in this binary and not dirty
+func (h *HealthHandler) checkMigration(c context.Context) (any, error) {
+	expected, err := db.LatestVersion()
+	if err != nil {
+		return nil, err
+	}
+	version, dirty, err := h.hr.MigrationVersion(c)
+	if err != nil {
+		return nil, err
+	}
+	status := models.MigrationStatus{Version: version, Expected: expected, Dirty: dirty}
+	if dirty {
+		return status, fmt.Errorf("migration version %d is dirty", version)
+	}
+	if version < expected {
+		return status, fmt.Errorf("migration version %d is behind %d", version, expected)
+	}
+	return status, nil
+}
@@ -1,9 +1,11 @@
 package handler
 
 import (
-	"log"
+	"errors"
 	"net/http"
 
+	"github.com/Belalai-E-Wallet-Backend/internal/apperror"
+	"github.com/Belalai-E-Wallet-Backend/internal/i18n"
 	"github.com/Belalai-E-Wallet-Backend/internal/models"
 	"github.com/Belalai-E-Wallet-Backend/internal/repository"
 	"github.com/Belalai-E-Wallet-Backend/internal/utils"
@@ -11,10 +13,10 @@ import (
 )
 
 type ProfileHandler struct {
-	profileRepository *repository.ProfileRepository
+	profileRepository repository.ProfileRepo
 }
 
-func NewProfileHandler(pr *repository.ProfileRepository) *ProfileHandler {
+func NewProfileHandler(pr repository.ProfileRepo) *ProfileHandler {
 	return &ProfileHandler{
 		profileRepository: pr,
 	}
@@ -28,32 +30,22 @@ func NewProfileHandler(pr *repository.ProfileRepository) *ProfileHandler {
 // @Success 200 {object} models.ResponseData{Data=models.ProfileResponse} "Detail profil berhasil diambil"
 // @Failure 401 {object} models.UnauthorizedResponse "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang"
 // @Failure 404 {object} models.NotFoundResponse "Profil pengguna tidak ditemukan"
-// @Router /profile [get]
+// @Router /v1/profile [get]
 // @Security JWTtoken
 func (ph *ProfileHandler) GetProfile(c *gin.Context) {
 	userId, err := utils.GetUserFromCtx(c)
 	if err != nil {
-		log.Println("error cause: ", err.Error())
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusUnauthorized,
-			},
-			Err: err.Error(),
-		})
+		c.Error(err)
 		return
 	}
 
 	profile, err := ph.profileRepository.GetProfile(c.Request.Context(), userId)
 	if err != nil {
-		log.Println("error cause: ", err.Error())
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusNotFound,
-			},
-			Err: err.Error(),
-		})
+		if errors.Is(err, repository.ErrProfileNotFound) {
+			c.Error(apperror.New(apperror.ProfileNotFound))
+			return
+		}
+		c.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
@@ -61,7 +53,7 @@ func (ph *ProfileHandler) GetProfile(c *gin.Context) {
 		Response: models.Response{
 			IsSuccess: true,
 			Code:      http.StatusOK,
-			Msg:       "Get profile successfully",
+			Msg:       i18n.T(c, i18n.MsgProfileFetched),
 		},
 		Data: models.ProfileResponse{
 			UserID:         userId,
@@ -69,6 +61,7 @@ func (ph *ProfileHandler) GetProfile(c *gin.Context) {
 			Phone:          profile.Phone,
 			ProfilePicture: profile.ProfilePicture,
 			Email:          *profile.Email,
+			Language:       profile.Language,
 			CreatedAt:      &profile.CreatedAt,
 			UpdatedAt:      profile.UpdatedAt,
 		},
@@ -83,37 +76,24 @@ func (ph *ProfileHandler) GetProfile(c *gin.Context) {
 // @Param fullname formData string true "Nama lengkap pengguna"
 // @Param phone formData string true "Nomor telepon pengguna"
 // @Param email formData string false "Alamat email pengguna (opsional)"
+// @Param language formData string false "Bahasa untuk email & notifikasi: 'id' atau 'en' (opsional)"
 // @Param profile_picture formData file false "Gambar profil baru (format file)"
 // @Success 200 {object} models.Response "Profil berhasil diperbarui"
 // @Failure 400 {object} models.ErrorResponse "Permintaan tidak valid (contoh: data form binding gagal, kesalahan upload file)"
 // @Failure 401 {object} models.UnauthorizedResponse "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang"
 // @Failure 500 {object} models.InternalErrorResponse "Kesalahan server internal"
-// @Router /profile [patch]
+// @Router /v1/profile [patch]
 // @Security JWTtoken
 func (ph *ProfileHandler) UpdateProfile(c *gin.Context) {
 	userId, err := utils.GetUserFromCtx(c)
 	if err != nil {
-		log.Println("error cause: ", err.Error())
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusUnauthorized,
-			},
-			Err: err.Error(),
-		})
+		c.Error(err)
 		return
 	}
 
 	var body models.ProfileRequest
 	if err := c.ShouldBind(&body); err != nil {
-		log.Println("error cause: ", err.Error())
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusBadRequest,
-			},
-			Err: err.Error(),
-		})
+		c.Error(apperror.Validation(err))
 		return
 	}
 
@@ -121,13 +101,7 @@ func (ph *ProfileHandler) UpdateProfile(c *gin.Context) {
 	file, err := c.FormFile("profile_picture")
 	if err == nil {
 		if filename, err := utils.FileUpload(c, file, "avatar"); err != nil {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Response: models.Response{
-					IsSuccess: false,
-					Code:      http.StatusBadRequest,
-				},
-				Err: err.Error(),
-			})
+			c.Error(err)
 			return
 		} else {
 			profilePic = &filename
@@ -140,24 +114,18 @@ func (ph *ProfileHandler) UpdateProfile(c *gin.Context) {
 		Phone:          body.Phone,
 		ProfilePicture: profilePic,
 		Email:          body.Email,
+		Language:       body.Language,
 	}
 
 	if err := ph.profileRepository.UpdateProfile(c.Request.Context(), &profile); err != nil {
-		log.Println("error cause: ", err.Error())
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: err.Error(),
-		})
+		c.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	c.JSON(http.StatusOK, models.Response{
 		IsSuccess: true,
 		Code:      http.StatusOK,
-		Msg:       "Profile updated successfully",
+		Msg:       i18n.T(c, i18n.MsgProfileUpdated),
 	})
 }
 
@@ -169,37 +137,23 @@ func (ph *ProfileHandler) UpdateProfile(c *gin.Context) {
 // @Success 200 {object} models.Response "Gambar profil berhasil dihapus"
 // @Failure 401 {object} models.UnauthorizedResponse "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang"
 // @Failure 500 {object} models.InternalErrorResponse "Kesalahan server internal saat menghapus avatar"
-// @Router /profile/avatar [delete]
+// @Router /v1/profile/avatar [delete]
 // @Security JWTtoken
 func (ph *ProfileHandler) DeleteAvatar(c *gin.Context) {
 	userId, err := utils.GetUserFromCtx(c)
 	if err != nil {
-		log.Println("error cause: ", err.Error())
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusUnauthorized,
-			},
-			Err: err.Error(),
-		})
+		c.Error(err)
 		return
 	}
 
 	if err := ph.profileRepository.DeleteAvatar(c.Request.Context(), userId); err != nil {
-		log.Println("error cause: ", err.Error())
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-			},
-			Err: err.Error(),
-		})
+		c.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	c.JSON(http.StatusOK, models.Response{
 		IsSuccess: true,
 		Code:      http.StatusOK,
-		Msg:       "Deleted profile picture successfully",
+		Msg:       i18n.T(c, i18n.MsgAvatarDeleted),
 	})
 }
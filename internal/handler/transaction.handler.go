@@ -1,52 +1,123 @@
 package handler
 
 import (
-	"log"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"slices"
 	"strconv"
+	"time"
 
+	"github.com/Belalai-E-Wallet-Backend/internal/apperror"
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+	"github.com/Belalai-E-Wallet-Backend/internal/i18n"
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
 	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/receipt"
 	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/Belalai-E-Wallet-Backend/internal/statement"
 	"github.com/Belalai-E-Wallet-Backend/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
 type TransactionHandler struct {
-	tr *repository.TransactionRepository
+	tr     repository.TransactionRepo
+	mailer utils.Mailer
+	bg     *utils.Background
+	signer *receipt.Signer
+	cfg    *configs.Config
 }
 
-func NewTransactionHandler(tr *repository.TransactionRepository) *TransactionHandler {
-	return &TransactionHandler{tr: tr}
+func NewTransactionHandler(tr repository.TransactionRepo, mailer utils.Mailer, bg *utils.Background, cfg *configs.Config) *TransactionHandler {
+	return &TransactionHandler{tr: tr, mailer: mailer, bg: bg, signer: receipt.NewSigner(cfg.Receipt.Secret), cfg: cfg}
 }
 
 // GetTransactionHistory
 // @tags 			transaction
-// @router 			/transaction/history 	[GET]
+// @router 			/v1/transaction/history 	[GET]
 // @Summary 		Get user transaction history with pagination
-// @Description 	Get transaction history for authenticated user (excluding soft deleted) with pagination support
+// @Description 	Get transfer history for authenticated user (excluding soft deleted). Send next_cursor / prev_cursor of the response as cursor to read the next / previous page, page is ignored when cursor is set
 // @accept 			json
 // @produce 		json
 // @Security 		BearerAuth
+// @param 			cursor 		query 		string 	false "Opaque cursor from next_cursor or prev_cursor"
 // @param 			page 		query 		int 	false "Page number (default: 1)"
 // @param 			limit 		query 		int 	false "Items per page (default: 10)"
+// @param 			from 		query 		string 	false "Created from date, inclusive (YYYY-MM-DD)"
+// @param 			to 			query 		string 	false "Created until date, inclusive (YYYY-MM-DD)"
+// @param 			type 		query 		[]string false "Transaction type, repeat for many type" collectionFormat(multi) Enums(sent, received, topup, withdrawal)
+// @param 			status 		query 		[]string false "Transaction status, repeat for many status" collectionFormat(multi) Enums(success, pending, failed)
+// @param 			min_amount 	query 		int 	false "Minimum amount"
+// @param 			max_amount 	query 		int 	false "Maximum amount"
+// @param 			counterparty query 		int 	false "User ID of the other side of transfer"
+// @param 			q 			query 		string 	false "Search notes and counterparty name"
+// @failure 		400			{object} 	models.ErrorResponse "Invalid cursor or filter"
 // @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
-// @failure 		404			{object} 	models.NotFoundResponse "Transaction History Not Found"
 // @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
 // @success 		200 		{object}  	models.ResponseData "Success Response with Transaction History Data"
 func (th *TransactionHandler) GetTransactionHistory(ctx *gin.Context) {
+	th.history(ctx, "transfer", i18n.MsgHistoryFetched)
+}
+
+// GetAllTransactionHistory - Get transfer + topup history
+// @tags 			transaction
+// @router 			/v1/transaction/history/all 	[GET]
+// @Summary 		Get all user transaction history (transfer + topup)
+// @Description 	Get complete transaction history including transfers and topups for authenticated user, newest first. Send next_cursor / prev_cursor of the response as cursor to read the next / previous page, page is ignored when cursor is set
+// @accept 			json
+// @produce 		json
+// @Security 		BearerAuth
+// @param 			cursor 		query 		string 	false "Opaque cursor from next_cursor or prev_cursor"
+// @param 			page 		query 		int 	false "Page number (default: 1)"
+// @param 			limit 		query 		int 	false "Items per page (default: 10)"
+// @param 			from 		query 		string 	false "Created from date, inclusive (YYYY-MM-DD)"
+// @param 			to 			query 		string 	false "Created until date, inclusive (YYYY-MM-DD)"
+// @param 			type 		query 		[]string false "Transaction type, repeat for many type" collectionFormat(multi) Enums(sent, received, topup, withdrawal)
+// @param 			status 		query 		[]string false "Transaction status, repeat for many status" collectionFormat(multi) Enums(success, pending, failed)
+// @param 			min_amount 	query 		int 	false "Minimum amount"
+// @param 			max_amount 	query 		int 	false "Maximum amount"
+// @param 			counterparty query 		int 	false "User ID of the other side of transfer"
+// @param 			q 			query 		string 	false "Search notes and counterparty name"
+// @failure 		400			{object} 	models.ErrorResponse "Invalid cursor or filter"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{object}  	models.ResponseData "Success Response with Complete Transaction History Data"
+// @success 		204 		{object}  	models.ResponseData "User has no transaction"
+func (th *TransactionHandler) GetAllTransactionHistory(ctx *gin.Context) {
+	th.history(ctx, "", i18n.MsgHistoryAllFetched)
+}
+
+// history serve both history endpoint, kind is transaction kind shown by the endpoint (empty is every kind)
+// with cursor query it use keyset pagination, otherwise page & limit like before
+func (th *TransactionHandler) history(ctx *gin.Context, kind, msg string) {
 	userID, err := utils.GetUserFromCtx(ctx)
 	if err != nil {
-		log.Println("Error getting user from context.\nCause: ", err.Error())
-		ctx.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      401,
-			},
-			Err: "Unauthorized: " + err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
+	var filter models.HistoryFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.Error(apperror.Validation(err))
+		return
+	}
+	if err := filter.Validate(); err != nil {
+		ctx.Error(apperror.Validation(err))
+		return
+	}
+	filter.Kind = kind
+
+	var cursor *models.Cursor
+	if raw := ctx.Query("cursor"); raw != "" {
+		if cursor, err = models.DecodeCursor(raw); err != nil {
+			ctx.Error(apperror.Wrap(apperror.CursorInvalid, err))
+			return
+		}
+	}
+
 	// Parse pagination parameters
 	page, err := strconv.Atoi(ctx.Query("page"))
 	if err != nil || page < 1 {
@@ -58,354 +129,840 @@ func (th *TransactionHandler) GetTransactionHistory(ctx *gin.Context) {
 		limit = 10
 	}
 
-	// Calculate offset
-	offset := (page - 1) * limit
-
 	// Get total count first
-	totalCount, err := th.tr.GetHistoryCount(ctx, userID)
+	totalCount, err := th.tr.GetAllHistoryCount(ctx, userID, filter)
 	if err != nil {
-		log.Println("Error getting transaction history count.\nCause: ", err.Error())
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      500,
-			},
-			Err: "Failed to retrieve transaction history count",
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	// Calculate total pages
-	totalPages := 0
-	if limit > 0 && totalCount > 0 {
-		totalPages = (totalCount + limit - 1) / limit
-	}
+	totalPages := (totalCount + limit - 1) / limit
 
-	// If no transactions found at all
-	if totalCount == 0 {
-		ctx.JSON(http.StatusOK, models.ResponseData{
+	// no transaction at all, all history keep responding 204 like before
+	// (no match for a filter is an empty page)
+	if totalCount == 0 && kind == "" && filter.IsZero() {
+		ctx.JSON(http.StatusNoContent, models.ResponseData{
 			Response: models.Response{
 				IsSuccess: true,
-				Code:      200,
-				Msg:       "Get Transaction History Success",
-			},
-			Data: map[string]interface{}{
-				"transactions": []interface{}{},
-				"page":         page,
-				"limit":        limit,
-				"total":        totalCount,
-				"total_pages":  totalPages,
+				Code:      http.StatusNoContent,
+				Msg:       i18n.T(ctx, i18n.MsgHistoryEmpty),
 			},
+			Data: historyPage(nil, page, limit, totalCount, totalPages, nil, nil),
 		})
 		return
 	}
 
-	// Get transaction histories with pagination
-	histories, err := th.tr.GetHistory(ctx, userID, offset, limit)
-	if err != nil {
-		log.Println("Error getting transaction history from repository.\nCause: ", err.Error())
-
-		if err.Error() == "no transactions found" {
-			// Return empty array for this page but with total count
-			ctx.JSON(http.StatusOK, models.ResponseData{
-				Response: models.Response{
-					IsSuccess: true,
-					Code:      200,
-					Msg:       "Get Transaction History Success",
-				},
-				Data: map[string]interface{}{
-					"transactions": []interface{}{},
-					"page":         page,
-					"limit":        limit,
-					"total":        totalCount,
-					"total_pages":  totalPages,
-				},
-			})
+	var data map[string]interface{}
+	if cursor != nil {
+		result, err := th.tr.GetHistoryByCursor(ctx, userID, filter, cursor, limit)
+		if err != nil {
+			ctx.Error(apperror.Wrap(apperror.Internal, err))
 			return
 		}
-
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      500,
-			},
-			Err: "Failed to retrieve transaction history",
-		})
-		return
+		data = historyPage(result.Items, 0, limit, totalCount, totalPages, result.Next, result.Prev)
+	} else {
+		// Calculate offset
+		offset := (page - 1) * limit
+		histories, err := th.tr.GetAllHistory(ctx, userID, filter, limit, offset)
+		// page after the last one is empty, not an error
+		if err != nil && !errors.Is(err, repository.ErrNoTransactions) {
+			ctx.Error(apperror.Wrap(apperror.Internal, err))
+			return
+		}
+		// cursor of offset page let client switch to cursor from any page
+		var next, prev *models.Cursor
+		if len(histories) > 0 && page < totalPages {
+			c := utils.HistoryCursor(histories[len(histories)-1])
+			next = &c
+		}
+		if len(histories) > 0 && page > 1 {
+			c := utils.HistoryCursor(histories[0])
+			c.Backward = true
+			prev = &c
+		}
+		data = historyPage(histories, page, limit, totalCount, totalPages, next, prev)
 	}
 
-	// Success response
 	ctx.JSON(http.StatusOK, models.ResponseData{
 		Response: models.Response{
 			IsSuccess: true,
-			Code:      200,
-			Msg:       "Get Transaction History Success",
-		},
-		Data: map[string]interface{}{
-			"transactions": histories,
-			"page":         page,
-			"limit":        limit,
-			"total":        totalCount,
-			"total_pages":  totalPages,
+			Code:      http.StatusOK,
+			Msg:       i18n.T(ctx, msg),
 		},
+		Data: data,
 	})
 }
 
-// GetAllTransactionHistory - Get transfer + topup history
+// page is 0 for cursor pagination, so it is left out
+func historyPage(histories []models.TransactionHistory, page, limit, total, totalPages int, next, prev *models.Cursor) map[string]interface{} {
+	if histories == nil {
+		histories = []models.TransactionHistory{}
+	}
+	data := map[string]interface{}{
+		"transactions": histories,
+		"limit":        limit,
+		"total":        total,
+		"total_pages":  totalPages,
+		"next_cursor":  next.Encode(),
+		"prev_cursor":  prev.Encode(),
+	}
+	if page > 0 {
+		data["page"] = page
+	}
+	return data
+}
+
+// DeleteTransaction - Soft delete transaction
 // @tags 			transaction
-// @router 			/transaction/history/all 	[GET]
-// @Summary 		Get all user transaction history (transfer + topup)
-// @Description 	Get complete transaction history including transfers and topups for authenticated user
+// @router 			/v1/transaction/{id} 	[DELETE]
+// @Summary 		Soft delete transaction
+// @Description 	Soft delete transaction for authenticated user, it can be restored from trash until retention period end
 // @accept 			json
 // @produce 		json
 // @Security 		BearerAuth
+// @Param			id	path	int	true	"Transaction ID"
 // @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
-// @failure 		404			{object} 	models.NotFoundResponse "Transaction History Not Found"
+// @failure 		404			{object} 	models.NotFoundResponse "Transaction Not Found"
 // @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
-// @success 		200 		{object}  	models.ResponseData "Success Response with Complete Transaction History Data"
-func (th *TransactionHandler) GetAllTransactionHistory(ctx *gin.Context) {
+// @success 		200 		{object}  	models.Response "Success Response"
+func (th *TransactionHandler) DeleteTransaction(ctx *gin.Context) {
 	userID, err := utils.GetUserFromCtx(ctx)
 	if err != nil {
-		log.Println("Error getting user from context.\nCause: ", err.Error())
-		ctx.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      401,
-			},
-			Err: "Unauthorized: " + err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
-	page, err := strconv.Atoi(ctx.Query("page"))
-	if err != nil || page < 1 {
-		page = 1
+	// Get transaction ID from URL parameter
+	transactionIDStr := ctx.Param("id")
+	transactionID, err := strconv.Atoi(transactionIDStr)
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.InvalidID, err))
+		return
 	}
 
-	limit, err := strconv.Atoi(ctx.Query("limit"))
-	if err != nil || limit < 1 {
-		limit = 10 // default limit for transaction history
+	err = th.tr.SoftDeleteTransaction(ctx, transactionID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTransactionNotFound) {
+			ctx.Error(apperror.New(apperror.TransactionNotFound))
+			return
+		}
+
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
 	}
 
-	// Calculate offset
-	offset := (page - 1) * limit
+	ctx.JSON(http.StatusOK, models.Response{
+		IsSuccess: true,
+		Code:      http.StatusOK,
+		Msg:       i18n.T(ctx, i18n.MsgTransactionDeleted),
+	})
+}
 
-	totalCount, err := th.tr.GetHistoryCount(ctx, userID)
+// Handler Method untuk Topup
+// DeleteTopup - Soft delete topup
+// @tags 			topup
+// @router 			/v1/transaction/topup/{id} 	[DELETE]
+// @Summary 		Soft delete topup
+// @Description 	Soft delete topup for authenticated user, it can be restored from trash until retention period end
+// @accept 			json
+// @produce 		json
+// @Security 		BearerAuth
+// @Param			id	path	int	true	"Topup ID"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		403			{object} 	models.ErrorResponse "Topup is not owned by user"
+// @failure 		404			{object} 	models.NotFoundResponse "Topup Not Found"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{object}  	models.Response "Success Response"
+func (th *TransactionHandler) DeleteTopup(ctx *gin.Context) {
+	userID, err := utils.GetUserFromCtx(ctx)
 	if err != nil {
-		log.Println("Error getting transaction history count.\nCause: ", err.Error())
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      500,
-			},
-			Err: "Failed to retrieve transaction history count",
-		})
+		ctx.Error(err)
 		return
 	}
 
-	// Calculate total pages
-	totalPages := 0
-	if limit > 0 && totalCount > 0 {
-		totalPages = (totalCount + limit - 1) / limit // Ceiling division
+	topupIDStr := ctx.Param("id")
+	topupID, err := strconv.Atoi(topupIDStr)
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.InvalidID, err))
+		return
 	}
 
-	histories, err := th.tr.GetAllHistory(ctx, userID, limit, offset)
+	err = th.tr.SoftDeleteTopup(ctx, topupID, userID)
 	if err != nil {
-		log.Println("Error getting all transaction history from repository.\nCause: ", err.Error())
-
-		if err.Error() == "no transactions found" {
-			ctx.JSON(http.StatusNoContent, models.ResponseData{
-				Response: models.Response{
-					IsSuccess: true,
-					Code:      http.StatusNoContent,
-					Msg:       "No History Found",
-				},
-				Data: map[string]interface{}{
-					"transactions": histories,
-					"page":         page,
-					"limit":        limit,
-					"total":        totalCount,
-					"total_pages":  totalPages,
-				},
-			})
+		if errors.Is(err, repository.ErrTopUpNotFound) {
+			ctx.Error(apperror.New(apperror.TopUpNotFound))
+			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      500,
-			},
-			Err: "Failed to retrieve transaction history",
-		})
+		if errors.Is(err, repository.ErrTopUpNotOwned) {
+			ctx.Error(apperror.New(apperror.Forbidden))
+			return
+		}
+
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.Response{
+		IsSuccess: true,
+		Code:      http.StatusOK,
+		Msg:       i18n.T(ctx, i18n.MsgTopUpDeleted),
+	})
+}
+
+// GetTrash - Hidden transaction which can still be restored
+// @tags 			transaction
+// @router 			/v1/transaction/trash 	[GET]
+// @Summary 		Get trash
+// @Description 	Transfer & topup hidden by user, last hidden first. Item can be restored until purge_at, after that it stay hidden for good
+// @accept 			json
+// @produce 		json
+// @Security 		BearerAuth
+// @param 			page 		query 		int 	false "Page number (default: 1)"
+// @param 			limit 		query 		int 	false "Items per page (default: 10)"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{object}  	models.ResponseData "Success Response with Trash Data"
+func (th *TransactionHandler) GetTrash(ctx *gin.Context) {
+	userID, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	total, err := th.tr.GetTrashCount(ctx, userID)
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
+	items, err := th.tr.GetTrash(ctx, userID, (page-1)*limit, limit)
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+	if items == nil {
+		items = []models.TrashItem{}
+	}
+	for i := range items {
+		items[i].PurgeAt = items[i].DeletedAt.Add(th.cfg.Trash.Retention)
+	}
 
 	ctx.JSON(http.StatusOK, models.ResponseData{
 		Response: models.Response{
 			IsSuccess: true,
 			Code:      http.StatusOK,
-			Msg:       "Get All Transaction History Success",
+			Msg:       i18n.T(ctx, i18n.MsgTrashFetched),
 		},
 		Data: map[string]interface{}{
-			"transactions": histories,
+			"transactions": items,
 			"page":         page,
 			"limit":        limit,
-			"total":        totalCount,
-			"total_pages":  totalPages,
+			"total":        total,
+			"total_pages":  (total + limit - 1) / limit,
 		},
 	})
 }
 
-// DeleteTransaction - Soft delete transaction
+// RestoreTransaction - Bring back hidden transfer
 // @tags 			transaction
-// @router 			/transaction/{id} 	[DELETE]
-// @Summary 		Soft delete transaction
-// @Description 	Soft delete transaction for authenticated user
+// @router 			/v1/transaction/{id}/restore 	[POST]
+// @Summary 		Restore transfer
+// @Description 	Show transfer hidden by user on history again, only while it is still in trash
 // @accept 			json
 // @produce 		json
 // @Security 		BearerAuth
 // @Param			id	path	int	true	"Transaction ID"
+// @failure 		400			{object} 	models.ErrorResponse "Invalid ID"
 // @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
-// @failure 		404			{object} 	models.NotFoundResponse "Transaction Not Found"
+// @failure 		404			{object} 	models.NotFoundResponse "Transaction is not in trash"
 // @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
 // @success 		200 		{object}  	models.Response "Success Response"
-func (th *TransactionHandler) DeleteTransaction(ctx *gin.Context) {
+func (th *TransactionHandler) RestoreTransaction(ctx *gin.Context) {
+	th.restore(ctx, "transfer", apperror.TransactionNotFound, i18n.MsgTransactionRestored)
+}
+
+// RestoreTopup - Bring back hidden topup
+// @tags 			topup
+// @router 			/v1/transaction/topup/{id}/restore 	[POST]
+// @Summary 		Restore topup
+// @Description 	Show topup hidden by user on history again, only while it is still in trash
+// @accept 			json
+// @produce 		json
+// @Security 		BearerAuth
+// @Param			id	path	int	true	"Topup ID"
+// @failure 		400			{object} 	models.ErrorResponse "Invalid ID"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		404			{object} 	models.NotFoundResponse "Topup is not in trash"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{object}  	models.Response "Success Response"
+func (th *TransactionHandler) RestoreTopup(ctx *gin.Context) {
+	th.restore(ctx, "topup", apperror.TopUpNotFound, i18n.MsgTopUpRestored)
+}
+
+// restore one transaction of :id, anything not in trash of user is not found
+func (th *TransactionHandler) restore(ctx *gin.Context, kind string, notFound apperror.Code, msg string) {
 	userID, err := utils.GetUserFromCtx(ctx)
 	if err != nil {
-		log.Println("Error getting user from context.\nCause: ", err.Error())
-		ctx.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      401,
-			},
-			Err: "Unauthorized: " + err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
-	// Get transaction ID from URL parameter
-	transactionIDStr := ctx.Param("id")
-	transactionID, err := strconv.Atoi(transactionIDStr)
+	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      400,
-			},
-			Err: "Invalid transaction ID",
-		})
+		ctx.Error(apperror.Wrap(apperror.InvalidID, err))
 		return
 	}
 
-	err = th.tr.SoftDeleteTransaction(ctx, transactionID, userID)
+	restored, err := th.tr.RestoreTransactions(ctx, userID, []models.TransactionRef{{Kind: kind, ID: id}})
 	if err != nil {
-		log.Println("Error soft deleting transaction.\nCause: ", err.Error())
-
-		if err.Error() == "transaction not found or user not authorized" {
-			ctx.JSON(http.StatusNotFound, models.ErrorResponse{
-				Response: models.Response{
-					IsSuccess: false,
-					Code:      404,
-				},
-				Err: "Transaction not found or unauthorized",
-			})
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      500,
-			},
-			Err: "Failed to delete transaction",
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+	if len(restored) == 0 {
+		ctx.Error(apperror.New(notFound))
 		return
 	}
 
 	ctx.JSON(http.StatusOK, models.Response{
 		IsSuccess: true,
 		Code:      http.StatusOK,
-		Msg:       "Transaction deleted successfully",
+		Msg:       i18n.T(ctx, msg),
 	})
 }
 
-// Handler Method untuk Topup
-// DeleteTopup - Soft delete topup
+// SetTransactionCategory - Pick category of transfer
+// @tags 			transaction
+// @router 			/v1/transaction/{id}/category 	[PUT]
+// @Summary 		Set transfer category
+// @Description 	Category picked by user win over any rule, empty category let rule decide again. Response is category of transfer after the change
+// @accept 			json
+// @produce 		json
+// @Security 		BearerAuth
+// @Param			id		path	int						true	"Transaction ID"
+// @Param			body	body	models.CategoryRequest	true	"Category"
+// @failure 		400			{object} 	models.ErrorResponse "Invalid ID or category"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		404			{object} 	models.NotFoundResponse "Transaction not found"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{object}  	models.ResponseData{data=models.CategoryRequest} "Success Response"
+func (th *TransactionHandler) SetTransactionCategory(ctx *gin.Context) {
+	th.setCategory(ctx, "transfer")
+}
+
+// SetTopupCategory - Pick category of topup
 // @tags 			topup
-// @router 			/transaction/topup/{id} 	[DELETE]
-// @Summary 		Soft delete topup
-// @Description 	Soft delete topup for authenticated user
+// @router 			/v1/transaction/topup/{id}/category 	[PUT]
+// @Summary 		Set topup category
+// @Description 	Category picked by user win over the default topup category, empty category reset it. Response is category of topup after the change
+// @accept 			json
+// @produce 		json
+// @Security 		BearerAuth
+// @Param			id		path	int						true	"Topup ID"
+// @Param			body	body	models.CategoryRequest	true	"Category"
+// @failure 		400			{object} 	models.ErrorResponse "Invalid ID or category"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		404			{object} 	models.NotFoundResponse "Topup not found"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{object}  	models.ResponseData{data=models.CategoryRequest} "Success Response"
+func (th *TransactionHandler) SetTopupCategory(ctx *gin.Context) {
+	th.setCategory(ctx, "topup")
+}
+
+// setCategory of transaction :id owned by user, hidden one included
+func (th *TransactionHandler) setCategory(ctx *gin.Context, kind string) {
+	userID, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.InvalidID, err))
+		return
+	}
+
+	var body models.CategoryRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.Error(apperror.Validation(err))
+		return
+	}
+
+	category, err := th.tr.SetCategory(ctx.Request.Context(), userID, models.TransactionRef{Kind: kind, ID: id}, body.Category)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrTransactionNotFound):
+			ctx.Error(apperror.New(apperror.TransactionNotFound))
+		case errors.Is(err, repository.ErrTopUpNotFound):
+			ctx.Error(apperror.New(apperror.TopUpNotFound))
+		default:
+			ctx.Error(apperror.Wrap(apperror.Internal, err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.ResponseData{
+		Response: models.Response{
+			IsSuccess: true,
+			Code:      http.StatusOK,
+			Msg:       i18n.T(ctx, i18n.MsgTransactionCategorized),
+		},
+		Data: models.CategoryRequest{Category: category},
+	})
+}
+
+// HideTransactions - Move many transaction to trash
+// @tags 			transaction
+// @router 			/v1/transaction/trash 	[POST]
+// @Summary 		Hide many transaction
+// @Description 	Hide up to 100 transfer & topup at once, like DELETE of each one. Item which isn't user's or is already hidden is skipped
+// @accept 			json
+// @produce 		json
+// @Security 		BearerAuth
+// @Param			body	body	models.BulkTransactionRequest	true	"Transaction to hide"
+// @failure 		400			{object} 	models.ErrorResponse "Invalid body"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{object}  	models.ResponseData{Data=models.BulkTransactionResult} "Success Response"
+func (th *TransactionHandler) HideTransactions(ctx *gin.Context) {
+	th.bulk(ctx, th.tr.HideTransactions, i18n.MsgTransactionsHidden)
+}
+
+// RestoreTransactions - Bring back many transaction from trash
+// @tags 			transaction
+// @router 			/v1/transaction/trash/restore 	[POST]
+// @Summary 		Restore many transaction
+// @Description 	Restore up to 100 transfer & topup at once. Item which isn't in trash of user is skipped
+// @accept 			json
+// @produce 		json
+// @Security 		BearerAuth
+// @Param			body	body	models.BulkTransactionRequest	true	"Transaction to restore"
+// @failure 		400			{object} 	models.ErrorResponse "Invalid body"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{object}  	models.ResponseData{Data=models.BulkTransactionResult} "Success Response"
+func (th *TransactionHandler) RestoreTransactions(ctx *gin.Context) {
+	th.bulk(ctx, th.tr.RestoreTransactions, i18n.MsgTransactionsRestored)
+}
+
+func (th *TransactionHandler) bulk(ctx *gin.Context, apply func(context.Context, int, []models.TransactionRef) ([]models.TransactionRef, error), msg string) {
+	userID, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var body models.BulkTransactionRequest
+	if err := ctx.ShouldBind(&body); err != nil {
+		ctx.Error(apperror.Validation(err))
+		return
+	}
+
+	updated, err := apply(ctx, userID, body.Items)
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+	result := models.BulkTransactionResult{Updated: []models.TransactionRef{}, Skipped: []models.TransactionRef{}}
+	for _, ref := range body.Items {
+		if slices.Contains(updated, ref) && !slices.Contains(result.Updated, ref) {
+			result.Updated = append(result.Updated, ref)
+		} else {
+			result.Skipped = append(result.Skipped, ref)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, models.ResponseData{
+		Response: models.Response{
+			IsSuccess: true,
+			Code:      http.StatusOK,
+			Msg:       i18n.T(ctx, msg, len(result.Updated)),
+		},
+		Data: result,
+	})
+}
+
+// GetTransactionDetail - Detail of one transfer
+// @tags 			transaction
+// @router 			/v1/transaction/{id} 	[GET]
+// @Summary 		Get transfer detail
+// @Description 	Get transfer with both party, fee, status timeline and receipt code. Only sender and receiver can see it
+// @accept 			json
+// @produce 		json
+// @Security 		BearerAuth
+// @Param			id	path	int	true	"Transaction ID"
+// @failure 		400			{object} 	models.ErrorResponse "Invalid ID"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		404			{object} 	models.NotFoundResponse "Transaction Not Found"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{object}  	models.ResponseData{Data=models.TransactionDetail} "Success Response"
+func (th *TransactionHandler) GetTransactionDetail(ctx *gin.Context) {
+	th.detail(ctx, "transfer")
+}
+
+// GetTopupDetail - Detail of one topup
+// @tags 			topup
+// @router 			/v1/transaction/topup/{id} 	[GET]
+// @Summary 		Get topup detail
+// @Description 	Get topup with payment method, fee, status timeline and receipt code. Only owner of the topup can see it
 // @accept 			json
 // @produce 		json
 // @Security 		BearerAuth
 // @Param			id	path	int	true	"Topup ID"
+// @failure 		400			{object} 	models.ErrorResponse "Invalid ID"
 // @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
 // @failure 		404			{object} 	models.NotFoundResponse "Topup Not Found"
 // @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
-// @success 		200 		{object}  	models.Response "Success Response"
-func (th *TransactionHandler) DeleteTopup(ctx *gin.Context) {
+// @success 		200 		{object}  	models.ResponseData{Data=models.TransactionDetail} "Success Response"
+func (th *TransactionHandler) GetTopupDetail(ctx *gin.Context) {
+	th.detail(ctx, "topup")
+}
+
+func (th *TransactionHandler) detail(ctx *gin.Context, kind string) {
+	detail, ok := th.loadDetail(ctx, kind)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.ResponseData{
+		Response: models.Response{
+			IsSuccess: true,
+			Code:      http.StatusOK,
+			Msg:       i18n.T(ctx, i18n.MsgTransactionDetailFetched),
+		},
+		Data: detail,
+	})
+}
+
+// GetTransactionReceipt - Download receipt of transfer
+// @tags 			transaction
+// @router 			/v1/transaction/{id}/receipt 	[GET]
+// @Summary 		Download transfer receipt
+// @Description 	Download receipt of transfer as PDF or PNG, in language of the request. QR code on receipt lead to its verification
+// @produce 		application/pdf
+// @produce 		image/png
+// @Security 		BearerAuth
+// @Param			id		path	int		true	"Transaction ID"
+// @Param			format	query	string	false	"Receipt format (default: pdf)" Enums(pdf, png)
+// @failure 		400			{object} 	models.ErrorResponse "Invalid ID or format"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		404			{object} 	models.NotFoundResponse "Transaction Not Found"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{file}  	file "Receipt file"
+func (th *TransactionHandler) GetTransactionReceipt(ctx *gin.Context) {
+	th.receipt(ctx, "transfer")
+}
+
+// GetTopupReceipt - Download receipt of topup
+// @tags 			topup
+// @router 			/v1/transaction/topup/{id}/receipt 	[GET]
+// @Summary 		Download topup receipt
+// @Description 	Download receipt of topup as PDF or PNG, in language of the request. QR code on receipt lead to its verification
+// @produce 		application/pdf
+// @produce 		image/png
+// @Security 		BearerAuth
+// @Param			id		path	int		true	"Topup ID"
+// @Param			format	query	string	false	"Receipt format (default: pdf)" Enums(pdf, png)
+// @failure 		400			{object} 	models.ErrorResponse "Invalid ID or format"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		404			{object} 	models.NotFoundResponse "Topup Not Found"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{file}  	file "Receipt file"
+func (th *TransactionHandler) GetTopupReceipt(ctx *gin.Context) {
+	th.receipt(ctx, "topup")
+}
+
+func (th *TransactionHandler) receipt(ctx *gin.Context, kind string) {
+	format := ctx.DefaultQuery("format", "pdf")
+	if format != "pdf" && format != "png" {
+		ctx.Error(apperror.Validation(fmt.Errorf("format %q is invalid, use pdf or png", format)))
+		return
+	}
+
+	detail, ok := th.loadDetail(ctx, kind)
+	if !ok {
+		return
+	}
+
+	doc := receipt.New(detail, i18n.FromContext(ctx), th.cfg.Mail.Branding.AppName, th.cfg.Receipt.VerifyURL)
+	var buf bytes.Buffer
+	render, contentType := doc.PDF, "application/pdf"
+	if format == "png" {
+		render, contentType = doc.PNG, "image/png"
+	}
+	if err := render(&buf); err != nil {
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="receipt-%s.%s"`, detail.ReferenceNumber, format))
+	ctx.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// loadDetail load transaction of :id for the login user, error is already sent when it return false
+// transaction of other user is reported as not found, so id of other user can't be probed
+func (th *TransactionHandler) loadDetail(ctx *gin.Context, kind string) (*models.TransactionDetail, bool) {
 	userID, err := utils.GetUserFromCtx(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      401,
-			},
-			Err: "Unauthorized",
-		})
+		ctx.Error(err)
+		return nil, false
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.InvalidID, err))
+		return nil, false
+	}
+
+	notFound := apperror.TransactionNotFound
+	if kind == "topup" {
+		notFound = apperror.TopUpNotFound
+	}
+	detail, err := th.tr.GetTransactionDetail(ctx, kind, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrTransactionNotFound) || errors.Is(err, repository.ErrTopUpNotFound) {
+			ctx.Error(apperror.New(notFound))
+			return nil, false
+		}
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return nil, false
+	}
+	if !detail.IsParty(userID) {
+		ctx.Error(apperror.New(notFound))
+		return nil, false
+	}
+
+	// same transaction_type as history
+	switch {
+	case kind == "topup":
+		detail.Type = models.HistoryTypes["topup"]
+	case detail.Sender.UserID == userID:
+		detail.Type = models.HistoryTypes["sent"]
+	default:
+		detail.Type = models.HistoryTypes["received"]
+	}
+	detail.ReferenceNumber = receipt.Reference(kind, detail.ID, detail.CreatedAt)
+	detail.ReceiptCode = th.signer.Code(detail)
+	return detail, true
+}
+
+// VerifyReceipt - Check shared receipt
+// @tags 			transaction
+// @router 			/v1/receipt/verify/{code} 	[GET]
+// @Summary 		Verify receipt
+// @Description 	Check that receipt code is issued by this app and show what the receipt prove, name is masked. No login is needed, so anyone given the receipt can check it
+// @accept 			json
+// @produce 		json
+// @Param			code	path	string	true	"Receipt code, ex: TRF-20260101-00000123-K7Q2M4XW9D3FJ8HA"
+// @failure 		404			{object} 	models.NotFoundResponse "Receipt is invalid or not found"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{object}  	models.ResponseData{Data=models.ReceiptVerification} "Receipt is valid"
+func (th *TransactionHandler) VerifyReceipt(ctx *gin.Context) {
+	code := ctx.Param("code")
+	kind, id, err := receipt.ParseCode(code)
+	if err != nil {
+		ctx.Error(apperror.Wrap(apperror.ReceiptInvalid, err))
 		return
 	}
 
-	topupIDStr := ctx.Param("id")
-	topupID, err := strconv.Atoi(topupIDStr)
+	detail, err := th.tr.GetTransactionDetail(ctx, kind, id)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      400,
-			},
-			Err: "Invalid topup ID",
+		if errors.Is(err, repository.ErrTransactionNotFound) || errors.Is(err, repository.ErrTopUpNotFound) {
+			ctx.Error(apperror.New(apperror.ReceiptInvalid))
+			return
+		}
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+	// wrong signature look the same as unknown transaction
+	if !th.signer.Verify(code, detail) {
+		ctx.Error(apperror.New(apperror.ReceiptInvalid))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.ResponseData{
+		Response: models.Response{
+			IsSuccess: true,
+			Code:      http.StatusOK,
+			Msg:       i18n.T(ctx, i18n.MsgReceiptVerified),
+		},
+		Data: models.ReceiptVerification{
+			Valid:           true,
+			ReferenceNumber: receipt.Reference(kind, detail.ID, detail.CreatedAt),
+			Kind:            kind,
+			Amount:          detail.Amount,
+			Fee:             detail.Fee,
+			Total:           detail.Total,
+			Status:          detail.Status,
+			// payment method name of topup isn't personal
+			Sender:    maskParty(detail.Sender),
+			Receiver:  maskParty(detail.Receiver),
+			CreatedAt: detail.CreatedAt,
+		},
+	})
+}
+
+func maskParty(p models.TransactionParty) string {
+	if p.UserID == 0 {
+		return p.Name
+	}
+	return receipt.MaskName(p.Name)
+}
+
+// statement of long range may take longer than server write timeout
+const statementWriteTimeout = 5 * time.Minute
+
+// GetStatement - Account statement
+// @tags 			transaction
+// @router 			/v1/transaction/statement 	[GET]
+// @Summary 		Download or email account statement
+// @Description 	Opening balance, every success transfer & topup with running balance and closing balance of the period. Soft deleted transaction is included. CSV is always in english with plain number, PDF follow language of the request. With email=true statement is generated in background and sent as attachment to email of user (in language of user). PDF and emailed statement is built in memory so its period is at most 12 months, CSV download is streamed without limit
+// @produce 		text/csv
+// @produce 		application/pdf
+// @produce 		json
+// @Security 		BearerAuth
+// @param 			from 	query 		string 	true 	"Start date, inclusive (YYYY-MM-DD)"
+// @param 			to 		query 		string 	true 	"End date, inclusive (YYYY-MM-DD)"
+// @param 			format 	query 		string 	false 	"Statement format (default: pdf)" Enums(csv, pdf)
+// @param 			email 	query 		bool 	false 	"Send to email instead of download"
+// @failure 		400			{object} 	models.ErrorResponse "Invalid period or format, or PDF / email period longer than 12 months"
+// @failure 		401			{object} 	models.UnauthorizedResponse "Unauthorized"
+// @failure 		500 		{object} 	models.InternalErrorResponse "Internal Server Error"
+// @success 		200 		{file}  	file "Statement file"
+// @success 		202 		{object}  	models.Response "Statement is being sent to email"
+func (th *TransactionHandler) GetStatement(ctx *gin.Context) {
+	userID, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var query models.StatementQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.Validation(err))
+		return
+	}
+	if err := query.Validate(); err != nil {
+		ctx.Error(apperror.Validation(err))
+		return
+	}
+	if query.Format == "" {
+		query.Format = "pdf"
+	}
+
+	if query.Email {
+		th.sendStatement(ctx, userID, query)
+		ctx.JSON(http.StatusAccepted, models.Response{
+			IsSuccess: true,
+			Code:      http.StatusAccepted,
+			Msg:       i18n.T(ctx, i18n.MsgStatementEmailed),
 		})
 		return
 	}
 
-	err = th.tr.SoftDeleteTopup(ctx, topupID, userID)
+	recipient, err := th.tr.GetMailRecipient(ctx, userID)
 	if err != nil {
-		if err.Error() == "topup not found" {
-			ctx.JSON(http.StatusNotFound, models.ErrorResponse{
-				Response: models.Response{
-					IsSuccess: false,
-					Code:      404,
-				},
-				Err: "Topup not found",
-			})
+		if errors.Is(err, repository.ErrUserNotFound) {
+			ctx.Error(apperror.New(apperror.UserNotFound))
+			return
+		}
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+		return
+	}
+
+	if err := http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Now().Add(statementWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		logger.FromContext(ctx).Warn("Failed extend write deadline", "err", err)
+	}
+	ctx.Header("Content-Type", statement.ContentType[query.Format])
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, statement.Filename(query.Format, query.From, query.To)))
+	ctx.Status(http.StatusOK)
+	if _, _, err := th.writeStatement(ctx, userID, query, recipientName(recipient), i18n.FromContext(ctx), ctx.Writer); err != nil {
+		if ctx.Writer.Written() {
+			// part of the file is already sent, it can only be cut
+			logger.FromContext(ctx).Error("Failed streaming statement", "err", err)
+			ctx.Abort()
 			return
 		}
+		ctx.Writer.Header().Del("Content-Disposition")
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
+	}
+}
+
+// writeStatement write statement of query into out and return its opening & closing balance
+// entry is written as it is read, so nothing is written on error before the first entry
+func (th *TransactionHandler) writeStatement(c context.Context, userID int, query models.StatementQuery, name, lang string, out io.Writer) (opening, closing int, err error) {
+	opening, err = th.tr.GetOpeningBalance(c, userID, query.From)
+	if err != nil {
+		return 0, 0, err
+	}
+	w, err := statement.New(query.Format, out, statement.Header{
+		AppName: th.cfg.Mail.Branding.AppName,
+		Name:    name,
+		Lang:    lang,
+		From:    query.From,
+		To:      query.To,
+		Opening: opening,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	closing = opening
+	err = th.tr.StreamStatement(c, userID, query.From, query.To.AddDate(0, 0, 1), func(e models.StatementEntry) error {
+		closing += e.Credit - e.Debit
+		return w.Add(e)
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return opening, closing, w.Close()
+}
 
-		if err.Error() == "unauthorized" {
-			ctx.JSON(http.StatusUnauthorized, models.ErrorResponse{
-				Response: models.Response{
-					IsSuccess: false,
-					Code:      401,
-				},
-				Err: "Unauthorized",
-			})
+// generate statement & send it by email in background, failure only logged
+func (th *TransactionHandler) sendStatement(c context.Context, userID int, query models.StatementQuery) {
+	th.bg.Go(c, func(c context.Context) {
+		recipient, err := th.tr.GetMailRecipient(c, userID)
+		if err != nil {
+			logger.FromContext(c).Error("Failed get statement recipient", "err", err)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      500,
+		lang := mailLanguage(c, recipient)
+		var file bytes.Buffer
+		opening, closing, err := th.writeStatement(c, userID, query, recipientName(recipient), lang, &file)
+		if err != nil {
+			logger.FromContext(c).Error("Failed generate statement", "err", err)
+			return
+		}
+		filename := statement.Filename(query.Format, query.From, query.To)
+		opt, err := utils.RenderMail(utils.MailStatement, lang, utils.MailData{
+			Brand: th.cfg.Mail.Branding,
+			Name:  recipientName(recipient),
+			Data: map[string]any{
+				"Period":   query.From.Format("02/01/2006") + " - " + query.To.Format("02/01/2006"),
+				"Opening":  opening,
+				"Closing":  closing,
+				"Filename": filename,
 			},
-			Err: "Failed to delete topup",
 		})
-		return
-	}
-
-	ctx.JSON(http.StatusOK, models.Response{
-		IsSuccess: true,
-		Code:      http.StatusOK,
-		Msg:       "Topup deleted successfully",
+		if err != nil {
+			logger.FromContext(c).Error("Failed render statement email", "err", err)
+			return
+		}
+		opt.To = []string{recipient.Email}
+		opt.Files = []utils.MailFile{{Name: filename, Data: file.Bytes()}}
+		if err := th.mailer.Send(c, opt); err != nil {
+			logger.FromContext(c).Error("Failed to send statement email", "err", err)
+		}
 	})
 }
@@ -0,0 +1,675 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository/memory"
+)
+
+type historyPage struct {
+	Transactions []models.TransactionHistory `json:"transactions"`
+	Page         int                         `json:"page"`
+	Limit        int                         `json:"limit"`
+	Total        int                         `json:"total"`
+	TotalPages   int                         `json:"total_pages"`
+	NextCursor   string                      `json:"next_cursor"`
+	PrevCursor   string                      `json:"prev_cursor"`
+}
+
+// seedHistory make budi send 3 transfer to ani, receive 1 from ani and topup once, one minute apart
+func seedHistory(t *testing.T, env *testEnv) (int, int) {
+	t.Helper()
+	budi, budiWallet := env.store.AddUser("budi@mail.com", hash(t, "Rahasia#123"), "", 100000)
+	ani, aniWallet := env.store.AddUser("ani@mail.com", hash(t, "Rahasia#123"), "", 100000)
+	paymentID := env.store.AddPaymentMethod("BRI")
+
+	now := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	env.store.SetClock(func() time.Time { return now })
+	c := context.Background()
+	transfer := memory.NewTransferRepository(env.store)
+	for i := 1; i <= 3; i++ {
+		now = now.Add(time.Minute)
+		if _, err := transfer.TransferMoney(c, budi, models.TransferBody{IdReceiver: aniWallet, Amount: i * 1000, Notes: fmt.Sprintf("transfer %d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now = now.Add(time.Minute)
+	if _, err := transfer.TransferMoney(c, ani, models.TransferBody{IdReceiver: budiWallet, Amount: 500}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+	if _, err := memory.NewTopUpRepository(env.store).CreateTopUpTransaction(c, &models.TopUp{Amount: 20000, Tax: 1000, PaymentID: paymentID}, budi); err != nil {
+		t.Fatal(err)
+	}
+	return budi, ani
+}
+
+func TestGetTransactionHistory(t *testing.T) {
+	env := newTestEnv(t)
+	budi, _ := seedHistory(t, env)
+	empty, _ := env.store.AddUser("empty@mail.com", hash(t, "Rahasia#123"), "", 0)
+
+	tests := []struct {
+		name       string
+		user       int
+		query      string
+		total      int
+		totalPages int
+		notes      []string
+	}{
+		{name: "first page", user: budi, query: "?page=1&limit=3", total: 4, totalPages: 2, notes: []string{"", "transfer 3", "transfer 2"}},
+		{name: "last page", user: budi, query: "?page=2&limit=3", total: 4, totalPages: 2, notes: []string{"transfer 1"}},
+		{name: "page out of range", user: budi, query: "?page=5&limit=3", total: 4, totalPages: 2},
+		{name: "no transaction", user: empty, total: 0, totalPages: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, res := env.do(http.MethodGet, "/transaction/history"+tt.query, nil, tt.user)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			page := decodeData[historyPage](t, res)
+			if page.Total != tt.total || page.TotalPages != tt.totalPages {
+				t.Errorf("total = %d/%d pages, want %d/%d pages", page.Total, page.TotalPages, tt.total, tt.totalPages)
+			}
+			if len(page.Transactions) != len(tt.notes) {
+				t.Fatalf("got %d transactions, want %d", len(page.Transactions), len(tt.notes))
+			}
+			for i, trx := range page.Transactions {
+				if trx.Notes != tt.notes[i] {
+					t.Errorf("transaction %d notes = %q, want %q", i, trx.Notes, tt.notes[i])
+				}
+			}
+		})
+	}
+
+	t.Run("transaction type follow the user", func(t *testing.T) {
+		_, res := env.do(http.MethodGet, "/transaction/history?limit=2", nil, budi)
+		page := decodeData[historyPage](t, res)
+		if got := page.Transactions[0]; got.Type != "Transfer" || got.Amount != "Rp 500" {
+			t.Errorf("received transfer = %s %s, want Transfer Rp 500", got.Type, got.Amount)
+		}
+		if got := page.Transactions[1]; got.Type != "Send" || got.Amount != "Rp 3,000" {
+			t.Errorf("sent transfer = %s %s, want Send Rp 3,000", got.Type, got.Amount)
+		}
+	})
+}
+
+func TestGetAllTransactionHistory(t *testing.T) {
+	env := newTestEnv(t)
+	budi, ani := seedHistory(t, env)
+	empty, _ := env.store.AddUser("empty@mail.com", hash(t, "Rahasia#123"), "", 0)
+
+	t.Run("transfer and topup newest first", func(t *testing.T) {
+		rec, res := env.do(http.MethodGet, "/transaction/history/all", nil, budi)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+		}
+		page := decodeData[historyPage](t, res)
+		var types []string
+		for _, trx := range page.Transactions {
+			types = append(types, trx.Type)
+		}
+		want := "[Topup Transfer Send Send Send]"
+		if fmt.Sprint(types) != want {
+			t.Errorf("types = %v, want %s", types, want)
+		}
+	})
+
+	t.Run("pagination across transfer and topup", func(t *testing.T) {
+		var types []string
+		for pageNumber := 1; pageNumber <= 4; pageNumber++ {
+			rec, res := env.do(http.MethodGet, fmt.Sprintf("/transaction/history/all?page=%d&limit=2", pageNumber), nil, budi)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("page %d status = %d, want %d", pageNumber, rec.Code, http.StatusOK)
+			}
+			page := decodeData[historyPage](t, res)
+			if page.Total != 5 || page.TotalPages != 3 {
+				t.Errorf("page %d total = %d/%d pages, want 5/3 pages", pageNumber, page.Total, page.TotalPages)
+			}
+			for _, trx := range page.Transactions {
+				types = append(types, trx.Type)
+			}
+		}
+		// page 4 is after the last page, so it add nothing
+		want := "[Topup Transfer Send Send Send]"
+		if fmt.Sprint(types) != want {
+			t.Errorf("types = %v, want %s", types, want)
+		}
+	})
+
+	t.Run("topup is only shown to the owner", func(t *testing.T) {
+		_, res := env.do(http.MethodGet, "/transaction/history/all", nil, ani)
+		for _, trx := range decodeData[historyPage](t, res).Transactions {
+			if trx.Type == "Topup" {
+				t.Errorf("ani see topup of budi: %+v", trx)
+			}
+		}
+	})
+
+	t.Run("no transaction", func(t *testing.T) {
+		rec, _ := env.do(http.MethodGet, "/transaction/history/all", nil, empty)
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusNoContent)
+		}
+	})
+}
+
+func TestTransactionHistoryFilter(t *testing.T) {
+	env := newTestEnv(t)
+	budi, ani := seedHistory(t, env)
+
+	tests := []struct {
+		name  string
+		path  string
+		query string
+		want  string
+	}{
+		{name: "sent", query: "type=sent", want: "[Send 3000 Send 2000 Send 1000]"},
+		{name: "many type", query: "type=received&type=topup", want: "[Topup 20000 Transfer 500]"},
+		{name: "withdrawal", query: "type=withdrawal", want: "[]"},
+		{name: "amount range", query: "min_amount=1500&max_amount=5000", want: "[Send 3000 Send 2000]"},
+		{name: "search notes", query: "q=TRANSFER%202", want: "[Send 2000]"},
+		{name: "search payment method", query: "q=bri", want: "[Topup 20000]"},
+		{name: "counterparty", query: fmt.Sprintf("counterparty=%d&limit=2", ani), want: "[Transfer 500 Send 3000]"},
+		{name: "status", query: "status=failed", want: "[]"},
+		{name: "date range inclusive", query: "from=2026-01-01&to=2026-01-01", want: "[Topup 20000 Transfer 500 Send 3000 Send 2000 Send 1000]"},
+		{name: "date after history", query: "from=2026-01-02", want: "[]"},
+		{name: "transfer endpoint keep its kind", path: "/transaction/history", query: "type=topup", want: "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := tt.path
+			if path == "" {
+				path = "/transaction/history/all"
+			}
+			rec, res := env.do(http.MethodGet, path+"?"+tt.query, nil, budi)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			var got []string
+			for _, trx := range decodeData[historyPage](t, res).Transactions {
+				got = append(got, fmt.Sprintf("%s %d", trx.Type, trx.OriginalAmount))
+			}
+			if fmt.Sprint(got) != tt.want {
+				t.Errorf("history = %v, want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("total is filtered", func(t *testing.T) {
+		_, res := env.do(http.MethodGet, "/transaction/history/all?type=sent&limit=2", nil, budi)
+		page := decodeData[historyPage](t, res)
+		if page.Total != 3 || page.TotalPages != 2 || page.NextCursor == "" {
+			t.Fatalf("total = %d/%d pages next %q, want 3/2 pages with next cursor", page.Total, page.TotalPages, page.NextCursor)
+		}
+		_, res = env.do(http.MethodGet, "/transaction/history/all?type=sent&limit=2&cursor="+page.NextCursor, nil, budi)
+		if got := decodeData[historyPage](t, res).Transactions; len(got) != 1 || got[0].OriginalAmount != 1000 {
+			t.Errorf("cursor page = %+v, want only Send 1000", got)
+		}
+	})
+
+	for _, query := range []string{"type=bogus", "status=done", "from=01-01-2026", "from=2026-01-02&to=2026-01-01", "min_amount=5000&max_amount=1000", "min_amount=-1"} {
+		t.Run("invalid "+query, func(t *testing.T) {
+			rec, res := env.do(http.MethodGet, "/transaction/history/all?"+query, nil, budi)
+			assertError(t, rec, res, http.StatusBadRequest, "VALIDATION_FAILED")
+		})
+	}
+}
+
+func TestTransactionHistoryCursor(t *testing.T) {
+	env := newTestEnv(t)
+	budi, _ := seedHistory(t, env)
+
+	get := func(query string) historyPage {
+		t.Helper()
+		rec, res := env.do(http.MethodGet, "/transaction/history/all"+query, nil, budi)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s status = %d, want %d (body %s)", query, rec.Code, http.StatusOK, rec.Body.String())
+		}
+		return decodeData[historyPage](t, res)
+	}
+	types := func(page historyPage) string {
+		var types []string
+		for _, trx := range page.Transactions {
+			types = append(types, fmt.Sprintf("%s %d", trx.Type, trx.OriginalAmount))
+		}
+		return fmt.Sprint(types)
+	}
+
+	first := get("?limit=2")
+	if first.NextCursor == "" || first.PrevCursor != "" {
+		t.Fatalf("first page cursor = next %q prev %q, want only next", first.NextCursor, first.PrevCursor)
+	}
+
+	// topup made while paging shift the offset page, but not the cursor page
+	env.store.SetClock(func() time.Time { return time.Date(2026, 1, 2, 8, 0, 0, 0, time.UTC) })
+	paymentID := env.store.AddPaymentMethod("BCA")
+	if _, err := memory.NewTopUpRepository(env.store).CreateTopUpTransaction(context.Background(), &models.TopUp{Amount: 9000, Tax: 1000, PaymentID: paymentID}, budi); err != nil {
+		t.Fatal(err)
+	}
+
+	second := get("?limit=2&cursor=" + first.NextCursor)
+	if got, want := types(second), "[Send 3000 Send 2000]"; got != want {
+		t.Errorf("second page = %s, want %s", got, want)
+	}
+	if second.Page != 0 || second.Total != 6 {
+		t.Errorf("second page = page %d total %d, want no page and total 6", second.Page, second.Total)
+	}
+	last := get("?limit=2&cursor=" + second.NextCursor)
+	if got, want := types(last), "[Send 1000]"; got != want {
+		t.Errorf("last page = %s, want %s", got, want)
+	}
+	if last.NextCursor != "" || last.PrevCursor == "" {
+		t.Fatalf("last page cursor = next %q prev %q, want only prev", last.NextCursor, last.PrevCursor)
+	}
+
+	back := get("?limit=2&cursor=" + last.PrevCursor)
+	if got, want := types(back), "[Send 3000 Send 2000]"; got != want {
+		t.Errorf("previous page = %s, want %s", got, want)
+	}
+	back = get("?limit=2&cursor=" + back.PrevCursor)
+	if got, want := types(back), "[Topup 20000 Transfer 500]"; got != want {
+		t.Errorf("previous page = %s, want %s", got, want)
+	}
+	// the new topup is before the first page
+	if back.PrevCursor == "" {
+		t.Fatal("prev cursor is empty, want page with the new topup")
+	}
+	if got, want := types(get("?limit=2&cursor="+back.PrevCursor)), "[Topup 9000]"; got != want {
+		t.Errorf("newest page = %s, want %s", got, want)
+	}
+
+	t.Run("offset page has cursor", func(t *testing.T) {
+		page := get("?page=2&limit=2")
+		if page.Page != 2 || page.NextCursor == "" || page.PrevCursor == "" {
+			t.Errorf("page 2 = page %d next %q prev %q, want both cursor", page.Page, page.NextCursor, page.PrevCursor)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		rec, res := env.do(http.MethodGet, "/transaction/history?cursor=not-a-cursor", nil, budi)
+		assertError(t, rec, res, http.StatusBadRequest, "CURSOR_INVALID")
+	})
+}
+
+func TestDeleteTransaction(t *testing.T) {
+	env := newTestEnv(t)
+	budi, ani := seedHistory(t, env)
+
+	_, res := env.do(http.MethodGet, "/transaction/history?limit=1", nil, budi)
+	latest := decodeData[historyPage](t, res).Transactions[0]
+
+	rec, res := env.do(http.MethodDelete, fmt.Sprintf("/transaction/%d", latest.ID), nil, budi)
+	if rec.Code != http.StatusOK || !res.IsSuccess {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	// deleted only for budi, ani still see it
+	_, res = env.do(http.MethodGet, "/transaction/history", nil, budi)
+	if page := decodeData[historyPage](t, res); page.Total != 3 {
+		t.Errorf("budi total = %d, want 3", page.Total)
+	}
+	_, res = env.do(http.MethodGet, "/transaction/history", nil, ani)
+	if page := decodeData[historyPage](t, res); page.Total != 4 {
+		t.Errorf("ani total = %d, want 4", page.Total)
+	}
+
+	rec, res = env.do(http.MethodDelete, "/transaction/999", nil, budi)
+	assertError(t, rec, res, http.StatusNotFound, "TRANSACTION_NOT_FOUND")
+
+	rec, res = env.do(http.MethodDelete, "/transaction/abc", nil, budi)
+	assertError(t, rec, res, http.StatusBadRequest, "INVALID_ID")
+}
+
+type trashPage struct {
+	Transactions []models.TrashItem `json:"transactions"`
+	Total        int                `json:"total"`
+}
+
+func TestTrash(t *testing.T) {
+	env := newTestEnv(t)
+	budi, ani := seedHistory(t, env)
+	hiddenAt := time.Date(2026, 2, 1, 8, 0, 0, 0, time.UTC)
+	env.store.SetClock(func() time.Time { return hiddenAt })
+
+	// transfer 1 & 2 is budi's, transfer 4 is sent by ani to budi, 999 doesn't exist
+	rec, res := env.do(http.MethodPost, "/transaction/trash", models.BulkTransactionRequest{Items: []models.TransactionRef{
+		{Kind: "transfer", ID: 1}, {Kind: "transfer", ID: 2}, {Kind: "transfer", ID: 999}, {Kind: "transfer", ID: 1},
+	}}, budi)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	result := decodeData[models.BulkTransactionResult](t, res)
+	if len(result.Updated) != 2 || len(result.Skipped) != 2 {
+		t.Errorf("result = %+v, want 2 updated & 2 skipped", result)
+	}
+	if res.Msg != "2 transaction moved to trash" {
+		t.Errorf("message = %q", res.Msg)
+	}
+
+	hiddenAt = hiddenAt.Add(time.Hour)
+	rec, _ = env.do(http.MethodDelete, "/transaction/topup/1", nil, budi)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("delete topup status = %d (body %s)", rec.Code, rec.Body.String())
+	}
+
+	_, res = env.do(http.MethodGet, "/transaction/trash", nil, budi)
+	trash := decodeData[trashPage](t, res)
+	if trash.Total != 3 || len(trash.Transactions) != 3 {
+		t.Fatalf("trash = %+v, want 3 item", trash)
+	}
+	// last hidden first, purge at is retention after hidden
+	if first := trash.Transactions[0]; first.Kind != "topup" || !first.PurgeAt.Equal(hiddenAt.Add(env.cfg.Trash.Retention)) {
+		t.Errorf("first = %+v, want topup purged at %s", first, hiddenAt.Add(env.cfg.Trash.Retention))
+	}
+	// hidden only for budi
+	_, res = env.do(http.MethodGet, "/transaction/trash", nil, ani)
+	if page := decodeData[trashPage](t, res); page.Total != 0 {
+		t.Errorf("ani trash total = %d, want 0", page.Total)
+	}
+
+	t.Run("restore one", func(t *testing.T) {
+		rec, res := env.do(http.MethodPost, "/transaction/1/restore", nil, budi)
+		if rec.Code != http.StatusOK || !res.IsSuccess {
+			t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+		}
+		_, res = env.do(http.MethodGet, "/transaction/history", nil, budi)
+		if page := decodeData[historyPage](t, res); page.Total != 3 {
+			t.Errorf("history total = %d, want 3", page.Total)
+		}
+
+		// not in trash anymore
+		rec, res = env.do(http.MethodPost, "/transaction/1/restore", nil, budi)
+		assertError(t, rec, res, http.StatusNotFound, "TRANSACTION_NOT_FOUND")
+		// topup is restored by its own route, id of the other kind isn't mixed
+		rec, res = env.do(http.MethodPost, "/transaction/topup/2/restore", nil, budi)
+		assertError(t, rec, res, http.StatusNotFound, "TOPUP_NOT_FOUND")
+		rec, res = env.do(http.MethodPost, "/transaction/topup/1/restore", nil, ani)
+		assertError(t, rec, res, http.StatusNotFound, "TOPUP_NOT_FOUND")
+		rec, res = env.do(http.MethodPost, "/transaction/abc/restore", nil, budi)
+		assertError(t, rec, res, http.StatusBadRequest, "INVALID_ID")
+	})
+
+	t.Run("restore many", func(t *testing.T) {
+		rec, res := env.do(http.MethodPost, "/transaction/trash/restore", models.BulkTransactionRequest{Items: []models.TransactionRef{
+			{Kind: "topup", ID: 1}, {Kind: "transfer", ID: 3},
+		}}, budi)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+		}
+		result := decodeData[models.BulkTransactionResult](t, res)
+		if len(result.Updated) != 1 || result.Updated[0] != (models.TransactionRef{Kind: "topup", ID: 1}) {
+			t.Errorf("updated = %+v, want only topup 1", result.Updated)
+		}
+		_, res = env.do(http.MethodGet, "/transaction/history/all", nil, budi)
+		if page := decodeData[historyPage](t, res); page.Total != 4 {
+			t.Errorf("history total = %d, want 4", page.Total)
+		}
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		repo := memory.NewTransactionRepository(env.store)
+		hiddenAt = hiddenAt.Add(env.cfg.Trash.Retention)
+		purged, err := repo.PurgeTrash(context.Background(), env.cfg.Trash.Retention)
+		if err != nil || purged != 1 {
+			t.Fatalf("purged = %d, %v, want 1", purged, err)
+		}
+		// transfer 2 stay hidden, but can't be restored anymore
+		rec, res := env.do(http.MethodPost, "/transaction/2/restore", nil, budi)
+		assertError(t, rec, res, http.StatusNotFound, "TRANSACTION_NOT_FOUND")
+		_, res = env.do(http.MethodGet, "/transaction/history", nil, budi)
+		if page := decodeData[historyPage](t, res); page.Total != 3 {
+			t.Errorf("history total = %d, want 3", page.Total)
+		}
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		rec, res := env.do(http.MethodPost, "/transaction/trash", models.BulkTransactionRequest{}, budi)
+		assertError(t, rec, res, http.StatusBadRequest, "VALIDATION_FAILED")
+		rec, res = env.do(http.MethodPost, "/transaction/trash", models.BulkTransactionRequest{Items: []models.TransactionRef{{Kind: "withdrawal", ID: 1}}}, budi)
+		assertError(t, rec, res, http.StatusBadRequest, "VALIDATION_FAILED")
+	})
+}
+
+// download send authenticated request whose response is a file, not json
+func (env *testEnv) download(path string, userID int) *httptest.ResponseRecorder {
+	env.t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Accept-Language", "en")
+	req.Header.Set(testUserHeader, strconv.Itoa(userID))
+	rec := httptest.NewRecorder()
+	env.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGetTransactionDetail(t *testing.T) {
+	env := newTestEnv(t)
+	budi, ani := seedHistory(t, env)
+	stranger, _ := env.store.AddUser("stranger@mail.com", hash(t, "Rahasia#123"), "", 0)
+
+	_, res := env.do(http.MethodGet, "/transaction/history?type=sent&limit=1", nil, budi)
+	sent := decodeData[historyPage](t, res).Transactions[0]
+
+	rec, res := env.do(http.MethodGet, fmt.Sprintf("/transaction/%d", sent.ID), nil, budi)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	detail := decodeData[models.TransactionDetail](t, res)
+	if detail.Type != "Send" || detail.Amount != 3000 || detail.Total != 3000 || detail.Notes != "transfer 3" {
+		t.Errorf("detail = %+v, want Send of 3000 with notes transfer 3", detail)
+	}
+	if detail.Sender.UserID != budi || detail.Receiver.UserID != ani {
+		t.Errorf("sender/receiver = %d/%d, want %d/%d", detail.Sender.UserID, detail.Receiver.UserID, budi, ani)
+	}
+	if len(detail.Timeline) != 1 || detail.Timeline[0].Status != "success" {
+		t.Errorf("timeline = %+v, want one success", detail.Timeline)
+	}
+	if !strings.HasPrefix(detail.ReferenceNumber, "TRF-20260101-") || !strings.HasPrefix(detail.ReceiptCode, detail.ReferenceNumber+"-") {
+		t.Errorf("reference = %q, receipt code = %q", detail.ReferenceNumber, detail.ReceiptCode)
+	}
+
+	// receiver see the same transfer as received
+	_, res = env.do(http.MethodGet, fmt.Sprintf("/transaction/%d", sent.ID), nil, ani)
+	if got := decodeData[models.TransactionDetail](t, res); got.Type != "Transfer" || got.ReceiptCode != detail.ReceiptCode {
+		t.Errorf("receiver view = %s %q, want Transfer %q", got.Type, got.ReceiptCode, detail.ReceiptCode)
+	}
+
+	rec, res = env.do(http.MethodGet, fmt.Sprintf("/transaction/%d", sent.ID), nil, stranger)
+	assertError(t, rec, res, http.StatusNotFound, "TRANSACTION_NOT_FOUND")
+	rec, res = env.do(http.MethodGet, "/transaction/999", nil, budi)
+	assertError(t, rec, res, http.StatusNotFound, "TRANSACTION_NOT_FOUND")
+	rec, res = env.do(http.MethodGet, "/transaction/abc", nil, budi)
+	assertError(t, rec, res, http.StatusBadRequest, "INVALID_ID")
+
+	_, res = env.do(http.MethodGet, "/transaction/history/all?type=topup", nil, budi)
+	topup := decodeData[historyPage](t, res).Transactions[0]
+	rec, res = env.do(http.MethodGet, fmt.Sprintf("/transaction/topup/%d", topup.ID), nil, budi)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	detail = decodeData[models.TransactionDetail](t, res)
+	if detail.Type != "Topup" || detail.Fee != 1000 || detail.Total != 21000 || detail.Sender.Name != "BRI" {
+		t.Errorf("topup detail = %+v, want Topup of 20000 + 1000 fee from BRI", detail)
+	}
+	rec, res = env.do(http.MethodGet, fmt.Sprintf("/transaction/topup/%d", topup.ID), nil, ani)
+	assertError(t, rec, res, http.StatusNotFound, "TOPUP_NOT_FOUND")
+}
+
+func TestTransactionReceipt(t *testing.T) {
+	env := newTestEnv(t)
+	budi, _ := seedHistory(t, env)
+	stranger, _ := env.store.AddUser("stranger@mail.com", hash(t, "Rahasia#123"), "", 0)
+
+	_, res := env.do(http.MethodGet, "/transaction/history?type=sent&limit=1", nil, budi)
+	id := decodeData[historyPage](t, res).Transactions[0].ID
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		magic       string
+	}{
+		{name: "pdf by default", path: fmt.Sprintf("/transaction/%d/receipt", id), contentType: "application/pdf", magic: "%PDF"},
+		{name: "png", path: fmt.Sprintf("/transaction/%d/receipt?format=png", id), contentType: "image/png", magic: "\x89PNG"},
+		{name: "topup", path: "/transaction/topup/1/receipt", contentType: "application/pdf", magic: "%PDF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := env.download(tt.path, budi)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("content type = %q, want %q", got, tt.contentType)
+			}
+			if !strings.HasPrefix(rec.Header().Get("Content-Disposition"), `attachment; filename="receipt-T`) {
+				t.Errorf("content disposition = %q", rec.Header().Get("Content-Disposition"))
+			}
+			if !bytes.HasPrefix(rec.Body.Bytes(), []byte(tt.magic)) {
+				t.Errorf("body doesn't start with %q", tt.magic)
+			}
+		})
+	}
+
+	rec, res := env.do(http.MethodGet, fmt.Sprintf("/transaction/%d/receipt?format=docx", id), nil, budi)
+	assertError(t, rec, res, http.StatusBadRequest, "VALIDATION_FAILED")
+	rec, res = env.do(http.MethodGet, fmt.Sprintf("/transaction/%d/receipt", id), nil, stranger)
+	assertError(t, rec, res, http.StatusNotFound, "TRANSACTION_NOT_FOUND")
+}
+
+func TestVerifyReceipt(t *testing.T) {
+	env := newTestEnv(t)
+	budi, _ := seedHistory(t, env)
+
+	_, res := env.do(http.MethodGet, "/transaction/history?type=sent&limit=1", nil, budi)
+	id := decodeData[historyPage](t, res).Transactions[0].ID
+	_, res = env.do(http.MethodGet, fmt.Sprintf("/transaction/%d", id), nil, budi)
+	code := decodeData[models.TransactionDetail](t, res).ReceiptCode
+
+	// anyone can verify, no login
+	rec, res := env.do(http.MethodGet, "/receipt/verify/"+strings.ToLower(code), nil, 0)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	got := decodeData[models.ReceiptVerification](t, res)
+	if !got.Valid || got.Amount != 3000 || got.Kind != "transfer" {
+		t.Errorf("verification = %+v, want valid transfer of 3000", got)
+	}
+
+	// another transaction with signature of this one
+	parts := strings.Split(code, "-")
+	forged := fmt.Sprintf("%s-%s-%08d-%s", parts[0], parts[1], id-1, parts[3])
+	tampered := code[:len(code)-1] + map[bool]string{true: "B", false: "A"}[strings.HasSuffix(code, "A")]
+	for _, c := range []string{forged, tampered, "TRF-20260101-00000999-AAAAAAAAAAAAAAAA", "not-a-code"} {
+		rec, res = env.do(http.MethodGet, "/receipt/verify/"+c, nil, 0)
+		assertError(t, rec, res, http.StatusNotFound, "RECEIPT_INVALID")
+	}
+}
+
+func TestGetStatement(t *testing.T) {
+	env := newTestEnv(t)
+	budi, _ := seedHistory(t, env)
+	// only success topup move the balance
+	if err := memory.NewTopUpRepository(env.store).UpdateStatusTopUp(context.Background(), 1, models.TopUpSuccess); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		rec := env.download("/transaction/statement?from=2026-01-01&to=2026-01-01&format=csv", budi)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+		}
+		if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="statement-20260101-20260101.csv"` {
+			t.Errorf("content disposition = %q", got)
+		}
+		rows, err := csv.NewReader(rec.Body).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// header, opening, 3 sent, 1 received, 1 topup, closing
+		if len(rows) != 8 {
+			t.Fatalf("got %d rows, want 8: %v", len(rows), rows)
+		}
+		if rows[1][2] != "opening_balance" || rows[1][7] != "0" {
+			t.Errorf("opening row = %v", rows[1])
+		}
+		var balances []string
+		for _, row := range rows[2:7] {
+			balances = append(balances, row[7])
+		}
+		if want := []string{"-1000", "-3000", "-6000", "-5500", "14500"}; !slices.Equal(balances, want) {
+			t.Errorf("running balance = %v, want %v", balances, want)
+		}
+		if got := rows[2]; got[2] != "sent" || got[4] != "transfer 1" || got[5] != "1000" || !strings.HasPrefix(got[1], "TRF-20260101-") {
+			t.Errorf("first entry = %v", got)
+		}
+		if got := rows[7]; got[2] != "closing_balance" || got[5] != "6000" || got[6] != "20500" || got[7] != "14500" {
+			t.Errorf("closing row = %v", got)
+		}
+	})
+
+	t.Run("opening balance from earlier transaction", func(t *testing.T) {
+		rec := env.download("/transaction/statement?from=2026-01-02&to=2026-01-31&format=csv", budi)
+		rows, err := csv.NewReader(rec.Body).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 3 || rows[1][7] != "14500" || rows[2][7] != "14500" {
+			t.Errorf("rows = %v, want opening & closing of 14500", rows)
+		}
+	})
+
+	t.Run("pdf by default", func(t *testing.T) {
+		rec := env.download("/transaction/statement?from=2026-01-01&to=2026-01-31", budi)
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/pdf" {
+			t.Fatalf("status = %d, content type = %q", rec.Code, rec.Header().Get("Content-Type"))
+		}
+		if !bytes.HasPrefix(rec.Body.Bytes(), []byte("%PDF")) {
+			t.Error("body is not a PDF")
+		}
+	})
+
+	// pdf & email is built in memory, at most 12 months
+	for _, query := range []string{"from=2026-01-01", "from=2026-02-01&to=2026-01-01", "from=2026-01-01&to=2026-01-31&format=xls", "from=01-01-2026&to=2026-01-31",
+		"from=2025-01-01&to=2026-01-01", "from=2025-01-01&to=2026-01-01&format=pdf&email=true", "from=2020-01-01&to=2026-01-31&format=csv&email=true"} {
+		rec, res := env.do(http.MethodGet, "/transaction/statement?"+query, nil, budi)
+		assertError(t, rec, res, http.StatusBadRequest, "VALIDATION_FAILED")
+	}
+
+	t.Run("longest period", func(t *testing.T) {
+		for _, query := range []string{"from=2025-02-01&to=2026-01-31", "from=2020-01-01&to=2026-01-31&format=csv"} {
+			if rec := env.download("/transaction/statement?"+query, budi); rec.Code != http.StatusOK {
+				t.Errorf("%s status = %d, want 200", query, rec.Code)
+			}
+		}
+	})
+
+	t.Run("email", func(t *testing.T) {
+		rec, res := env.do(http.MethodGet, "/transaction/statement?from=2026-01-01&to=2026-01-31&format=csv&email=true", nil, budi)
+		if rec.Code != http.StatusAccepted || !res.IsSuccess {
+			t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusAccepted, rec.Body.String())
+		}
+		mails := env.sentMails()
+		if len(mails) != 1 {
+			t.Fatalf("got %d mails, want 1", len(mails))
+		}
+		if mail := mails[0]; mail.To[0] != "budi@mail.com" || !slices.Equal(mail.Attachments, []string{"statement-20260101-20260131.csv"}) {
+			t.Errorf("mail to %v with attachments %v", mail.To, mail.Attachments)
+		}
+		if !strings.Contains(mails[0].AltBody, "Rp 14.500") {
+			t.Errorf("mail body doesn't show closing balance: %s", mails[0].AltBody)
+		}
+	})
+}
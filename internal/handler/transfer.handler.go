@@ -1,11 +1,17 @@
 package handler
 
 import (
-	"log"
+	"context"
+	"errors"
 	"net/http"
-	"regexp"
 	"strconv"
+	"time"
 
+	"github.com/Belalai-E-Wallet-Backend/internal/apperror"
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+	"github.com/Belalai-E-Wallet-Backend/internal/i18n"
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
+	"github.com/Belalai-E-Wallet-Backend/internal/metrics"
 	"github.com/Belalai-E-Wallet-Backend/internal/models"
 	"github.com/Belalai-E-Wallet-Backend/internal/repository"
 	"github.com/Belalai-E-Wallet-Backend/internal/utils"
@@ -14,49 +20,96 @@ import (
 )
 
 type TransferHandler struct {
-	transRep *repository.TransferRepository
+	transRep repository.TransferRepo
+	mailer   utils.Mailer
+	bg       *utils.Background
+	cfg      *configs.Config
+	// budgets track spending of sender after transfer
+	budgets *BudgetHandler
 }
 
-func NewTransferHandler(transRep *repository.TransferRepository) *TransferHandler {
-	return &TransferHandler{transRep: transRep}
+func NewTransferHandler(transRep repository.TransferRepo, mailer utils.Mailer, bg *utils.Background, cfg *configs.Config, budgets *BudgetHandler) *TransferHandler {
+	return &TransferHandler{transRep: transRep, mailer: mailer, bg: bg, cfg: cfg, budgets: budgets}
 }
 
 // @Summary Memfilter daftar pengguna
-// @Description Mendapatkan daftar pengguna dengan opsi pencarian dan paginasi. Digunakan untuk memilih pengguna tujuan transfer.
+// @Description Mendapatkan daftar pengguna dengan opsi pencarian dan paginasi. Digunakan untuk memilih pengguna tujuan transfer. Kirim next_cursor / prev_cursor sebagai cursor untuk halaman berikutnya / sebelumnya, jika cursor diisi page diabaikan dan data berbentuk CursorProfileResponse (tanpa total).
 // @Tags Transfer
 // @Accept json
 // @Produce json
 // @Param search query string false "Kata kunci pencarian nama atau nomor telepon"
+// @Param cursor query string false "Cursor dari next_cursor atau prev_cursor"
 // @Param page query int false "Nomor halaman untuk paginasi (default: 1)"
 // @Success 200 {object} models.ResponseData{Data=models.ListprofileResponse} "Daftar pengguna berhasil diambil"
+// @Failure 400 {object} models.ErrorResponse "Cursor tidak valid"
 // @Failure 401 {object} models.UnauthorizedResponse "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang"
 // @Failure 500 {object} models.InternalErrorResponse "Kesalahan server internal"
-// @Router /transfer [get]
+// @Router /v1/transfer [get]
 // @Security JWTtoken
 func (u *TransferHandler) FilterUser(ctx *gin.Context) {
 	// default get all user if query is empty
 	query := ctx.Query("search")
+	limit := 10
+
+	// keyset pagination, new user registered while paging doesn't shift the page
+	if raw := ctx.Query("cursor"); raw != "" {
+		cursor, err := models.DecodeCursor(raw)
+		if err != nil {
+			ctx.Error(apperror.Wrap(apperror.CursorInvalid, err))
+			return
+		}
+		result, err := u.transRep.SearchUserByCursor(ctx.Request.Context(), query, cursor, limit)
+		if err != nil {
+			ctx.Error(apperror.Wrap(apperror.Internal, err))
+			return
+		}
+		users := result.Items
+		if users == nil {
+			users = []models.ProfileResponse{}
+		}
+		ctx.JSON(http.StatusOK, models.ResponseData{
+			Response: models.Response{
+				IsSuccess: true,
+				Code:      http.StatusOK,
+			},
+			Data: models.CursorProfileResponse{
+				Users:      users,
+				Limit:      limit,
+				NextCursor: result.Next.Encode(),
+				PrevCursor: result.Prev.Encode(),
+			},
+		})
+		return
+	}
+
 	// Make pagenation using query LIMIT dan OFFSET
 	page, err := strconv.Atoi(ctx.Query("page"))
 	if err != nil {
 		page = 1
 	}
-	limit := 10
 	offset := (page - 1) * limit
 
 	// use / call repository filter user
 	users, err := u.transRep.FilterUser(ctx.Request.Context(), query, offset, limit, page)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      500,
-			},
-			Err: err.Error(),
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
+	// cursor of offset page let client switch to cursor from any page
+	// (page cached before created_at was selected has no position)
+	if n := len(users.Users); n > 0 && users.Users[0].CreatedAt != nil {
+		if page < users.TotalPage {
+			next := utils.ProfileCursor(users.Users[n-1])
+			users.NextCursor = next.Encode()
+		}
+		if page > 1 {
+			prev := utils.ProfileCursor(users.Users[0])
+			prev.Backward = true
+			users.PrevCursor = prev.Encode()
+		}
+	}
+
 	// send data users as response
 	ctx.JSON(http.StatusOK, models.ResponseData{
 		Response: models.Response{
@@ -77,48 +130,27 @@ func (u *TransferHandler) FilterUser(ctx *gin.Context) {
 // @Failure 400 {object} models.ErrorResponse "Permintaan tidak valid (contoh: data binding gagal, PIN salah, saldo tidak cukup, transfer ke diri sendiri)"
 // @Failure 401 {object} models.UnauthorizedResponse "Tidak terautentikasi (Unauthorized) - Token JWT tidak valid atau hilang"
 // @Failure 500 {object} models.InternalErrorResponse "Kesalahan server internal"
-// @Router /transfer [post]
+// @Router /v1/transfer [post]
 // @Security JWTtoken
 func (u *TransferHandler) TranferBalance(ctx *gin.Context) {
 	// get user id from token
 	userID, err := utils.GetUserFromCtx(ctx)
 	if err != nil {
-		log.Println("Error getting user from context.\nCause: ", err.Error())
-		ctx.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      401,
-			},
-			Err: "Unauthorized: " + err.Error(),
-		})
+		ctx.Error(err)
 		return
 	}
 
 	// binding data JSON
 	var body models.TransferBody
 	if err := ctx.ShouldBind(&body); err != nil {
-		log.Println("Failed binding data\nCause: ", err)
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      400,
-			},
-			Err: "Failed binding data...",
-		})
+		ctx.Error(apperror.Validation(err))
 		return
 	}
 
 	// get user hashed pin
 	user, err := u.transRep.GetHashedPin(ctx.Request.Context(), userID)
 	if err != nil {
-		log.Println("failed get hashed pin \nCause: ", err)
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusBadRequest,
-			},
-			Err: "user sender is not found",
-		})
+		ctx.Error(apperror.Wrap(apperror.UserNotFound, err))
 		return
 	}
 
@@ -128,70 +160,68 @@ func (u *TransferHandler) TranferBalance(ctx *gin.Context) {
 	hc := pkg.NewHashConfig()
 	isMatched, err := hc.CompareHashAndPassword(body.PinSender, user.Pin)
 	if err != nil {
-		log.Println("Internal Server Error.\nCause: ", err.Error())
-		re := regexp.MustCompile("hash|crypto|argon2id|format")
-		if re.Match([]byte(err.Error())) {
-			log.Println("Error during Hashing")
-		}
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      500,
-			},
-			Err: "internal server error",
-		})
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	// if pin is not match
 	if !isMatched {
-		ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      400,
-			},
-			Err: "pin is incorrect",
-		})
+		metrics.ObservePINFailure(metrics.PINSourceTransfer)
+		metrics.ObserveTransfer(metrics.TransferInvalidPIN)
+		ctx.Error(apperror.New(apperror.PINInvalid))
 		return
 	}
 
 	// if match execute tranfer using func repo
-	if err := u.transRep.TransferMoney(ctx.Request.Context(), userID, body); err != nil {
-		if err == repository.ErrNotEnoughBalance {
-			ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Response: models.Response{
-					IsSuccess: false,
-					Code:      http.StatusBadRequest,
-				},
-				Err: err.Error(),
-			})
+	transferID, err := u.transRep.TransferMoney(ctx.Request.Context(), userID, body)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotEnoughBalance) {
+			metrics.ObserveTransfer(metrics.TransferInsufficientBalance)
+			ctx.Error(apperror.New(apperror.InsufficientBalance))
 			return
 		}
-		if err == repository.ErrCantSendingToYourself {
-			ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Response: models.Response{
-					IsSuccess: false,
-					Code:      http.StatusBadRequest,
-				},
-				Err: err.Error(),
-			})
+		if errors.Is(err, repository.ErrCantSendingToYourself) {
+			metrics.ObserveTransfer(metrics.TransferToSelf)
+			ctx.Error(apperror.New(apperror.SelfTransfer))
 			return
 		}
-		log.Println("Internal Server Error.\nCause: ", err.Error())
-		ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      500,
-				Msg:       "internal server error",
-			},
-			Err: err.Error(),
-		})
+		metrics.ObserveTransfer(metrics.TransferError)
+		ctx.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	} else {
+		metrics.ObserveTransfer(metrics.TransferSuccess)
+		u.sendTransferReceivedMail(ctx.Request.Context(), userID, transferID, body)
+		u.budgets.track(ctx.Request.Context(), userID)
 		ctx.JSON(http.StatusOK, models.Response{
 			IsSuccess: true,
 			Code:      http.StatusOK,
-			Msg:       "transfer is success",
+			Msg:       i18n.T(ctx, i18n.MsgTransferSuccess),
 		})
 	}
 }
+
+// notify receiver by email in background, failure only logged because transfer is already success
+func (u *TransferHandler) sendTransferReceivedMail(c context.Context, senderID, transferID int, body models.TransferBody) {
+	now := time.Now()
+	u.bg.Go(c, func(c context.Context) {
+		sender, receiver, err := u.transRep.GetMailRecipients(c, senderID, body.IdReceiver)
+		if err != nil {
+			logger.FromContext(c).Error("Failed get transfer mail recipients", "err", err)
+			return
+		}
+		// request is made by sender, receiver without language preference get default language
+		if err := utils.SendTemplate(c, u.mailer, receiver.Email, utils.MailTransferReceived, receiver.Language, utils.MailData{
+			Brand: u.cfg.Mail.Branding,
+			Name:  recipientName(receiver),
+			Data: map[string]any{
+				"Amount":     body.Amount,
+				"SenderName": recipientName(sender),
+				"Notes":      body.Notes,
+				"Time":       now,
+				"TransferID": transferID,
+			},
+		}); err != nil {
+			logger.FromContext(c).Error("Failed to send transfer received email", "err", err)
+		}
+	})
+}
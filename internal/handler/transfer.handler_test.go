@@ -0,0 +1,162 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+)
+
+func TestTransferBalance(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    func(receiverWallet, senderWallet int) models.TransferBody
+		auth    bool
+		status  int
+		errCode string
+	}{
+		{
+			name: "success",
+			body: func(receiver, _ int) models.TransferBody {
+				return models.TransferBody{IdReceiver: receiver, ReceiverPhone: "0812", Amount: 30000, Notes: "makan", PinSender: "123456"}
+			},
+			auth:   true,
+			status: http.StatusOK,
+		},
+		{
+			name: "insufficient balance",
+			body: func(receiver, _ int) models.TransferBody {
+				return models.TransferBody{IdReceiver: receiver, ReceiverPhone: "0812", Amount: 500000, PinSender: "123456"}
+			},
+			auth:    true,
+			status:  http.StatusBadRequest,
+			errCode: "INSUFFICIENT_BALANCE",
+		},
+		{
+			name: "self transfer",
+			body: func(_, sender int) models.TransferBody {
+				return models.TransferBody{IdReceiver: sender, ReceiverPhone: "0812", Amount: 1000, PinSender: "123456"}
+			},
+			auth:    true,
+			status:  http.StatusBadRequest,
+			errCode: "SELF_TRANSFER",
+		},
+		{
+			name: "wrong pin",
+			body: func(receiver, _ int) models.TransferBody {
+				return models.TransferBody{IdReceiver: receiver, ReceiverPhone: "0812", Amount: 1000, PinSender: "654321"}
+			},
+			auth:    true,
+			status:  http.StatusBadRequest,
+			errCode: "PIN_INVALID",
+		},
+		{
+			name: "invalid body",
+			body: func(receiver, _ int) models.TransferBody {
+				return models.TransferBody{IdReceiver: receiver, ReceiverPhone: "0812", Amount: 1000, PinSender: "12"}
+			},
+			auth:    true,
+			status:  http.StatusBadRequest,
+			errCode: "VALIDATION_FAILED",
+		},
+		{
+			name: "unauthenticated",
+			body: func(receiver, _ int) models.TransferBody {
+				return models.TransferBody{IdReceiver: receiver, ReceiverPhone: "0812", Amount: 1000, PinSender: "123456"}
+			},
+			status:  http.StatusUnauthorized,
+			errCode: "UNAUTHORIZED",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			sender, senderWallet := env.store.AddUser("budi@mail.com", hash(t, "Rahasia#123"), hash(t, "123456"), 100000)
+			receiver, receiverWallet := env.store.AddUser("ani@mail.com", hash(t, "Rahasia#123"), "", 0)
+
+			userID := 0
+			if tt.auth {
+				userID = sender
+			}
+			rec, res := env.do(http.MethodPost, "/transfer", tt.body(receiverWallet, senderWallet), userID)
+
+			if tt.errCode != "" {
+				assertError(t, rec, res, tt.status, tt.errCode)
+				// failed transfer must not move any money
+				if got := env.balance(sender); got != 100000 {
+					t.Errorf("sender balance = %d, want 100000", got)
+				}
+				if got := env.balance(receiver); got != 0 {
+					t.Errorf("receiver balance = %d, want 0", got)
+				}
+				return
+			}
+
+			if rec.Code != tt.status || !res.IsSuccess {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.status, rec.Body.String())
+			}
+			if got := env.balance(sender); got != 70000 {
+				t.Errorf("sender balance = %d, want 70000", got)
+			}
+			if got := env.balance(receiver); got != 30000 {
+				t.Errorf("receiver balance = %d, want 30000", got)
+			}
+			mails := env.sentMails()
+			if len(mails) != 1 || mails[0].To[0] != "ani@mail.com" {
+				t.Errorf("transfer mail = %+v, want 1 mail to receiver", mails)
+			}
+		})
+	}
+}
+
+func TestFilterUserCursor(t *testing.T) {
+	env := newTestEnv(t)
+	now := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	env.store.SetClock(func() time.Time { return now })
+	var me int
+	for i := 1; i <= 12; i++ {
+		now = now.Add(time.Minute)
+		me, _ = env.store.AddUser(fmt.Sprintf("user%d@mail.com", i), hash(t, "Rahasia#123"), "", 0)
+	}
+
+	type userPage struct {
+		Users      []models.ProfileResponse `json:"users"`
+		NextCursor string                   `json:"next_cursor"`
+		PrevCursor string                   `json:"prev_cursor"`
+	}
+	get := func(query string) userPage {
+		t.Helper()
+		rec, res := env.do(http.MethodGet, "/transfer"+query, nil, me)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s status = %d, want %d (body %s)", query, rec.Code, http.StatusOK, rec.Body.String())
+		}
+		return decodeData[userPage](t, res)
+	}
+
+	first := get("?page=1")
+	if len(first.Users) != 10 || first.NextCursor == "" || first.PrevCursor != "" {
+		t.Fatalf("first page = %d users next %q prev %q, want 10 users and only next", len(first.Users), first.NextCursor, first.PrevCursor)
+	}
+
+	// user registered while paging doesn't show up again on the next page
+	now = now.Add(time.Minute)
+	env.store.AddUser("late@mail.com", hash(t, "Rahasia#123"), "", 0)
+
+	second := get("?cursor=" + first.NextCursor)
+	if len(second.Users) != 2 || second.NextCursor != "" || second.PrevCursor == "" {
+		t.Fatalf("second page = %d users next %q prev %q, want 2 users and only prev", len(second.Users), second.NextCursor, second.PrevCursor)
+	}
+	if last := first.Users[len(first.Users)-1].UserID; second.Users[0].UserID != last-1 {
+		t.Errorf("second page start at user %d, want %d", second.Users[0].UserID, last-1)
+	}
+
+	back := get("?cursor=" + second.PrevCursor)
+	if len(back.Users) != 10 || back.Users[0].UserID != first.Users[0].UserID {
+		t.Errorf("previous page = %d users from user %d, want 10 users from user %d", len(back.Users), back.Users[0].UserID, first.Users[0].UserID)
+	}
+
+	rec, res := env.do(http.MethodGet, "/transfer?cursor=bad", nil, me)
+	assertError(t, rec, res, http.StatusBadRequest, "CURSOR_INVALID")
+}
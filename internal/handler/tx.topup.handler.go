@@ -1,10 +1,11 @@
 package handler
 
 import (
-	"log"
 	"net/http"
 	"strconv"
 
+	"github.com/Belalai-E-Wallet-Backend/internal/apperror"
+	"github.com/Belalai-E-Wallet-Backend/internal/i18n"
 	"github.com/Belalai-E-Wallet-Backend/internal/models"
 	"github.com/Belalai-E-Wallet-Backend/internal/repository"
 	"github.com/Belalai-E-Wallet-Backend/internal/utils"
@@ -12,10 +13,10 @@ import (
 )
 
 type TopUpHandler struct {
-	topUpRepo *repository.TopUpRepository
+	topUpRepo repository.TopUpRepo
 }
 
-func NewTopUpHandler(topUpRepo *repository.TopUpRepository) *TopUpHandler {
+func NewTopUpHandler(topUpRepo repository.TopUpRepo) *TopUpHandler {
 	return &TopUpHandler{topUpRepo: topUpRepo}
 }
 
@@ -29,19 +30,11 @@ func NewTopUpHandler(topUpRepo *repository.TopUpRepository) *TopUpHandler {
 // @Success      200  {object}  models.ResponseData
 // @Failure      401  {object}  models.ErrorResponse
 // @Failure      500  {object}  models.ErrorResponse
-// @Router       /topup/methods [get]
+// @Router       /v1/topup/methods [get]
 func (th *TopUpHandler) GetPaymentMethods(c *gin.Context) {
 	methods, err := th.topUpRepo.FindAllPaymentMethods(c)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-				Msg:       "Failed to fetch payment methods",
-			},
-			Err: err.Error(),
-		})
+		c.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
@@ -49,7 +42,7 @@ func (th *TopUpHandler) GetPaymentMethods(c *gin.Context) {
 		Response: models.Response{
 			IsSuccess: true,
 			Code:      http.StatusOK,
-			Msg:       "Get payment methods successfully",
+			Msg:       i18n.T(c, i18n.MsgPaymentMethodsFetched),
 		},
 		Data: methods,
 	})
@@ -58,15 +51,7 @@ func (th *TopUpHandler) GetPaymentMethods(c *gin.Context) {
 func (th *TopUpHandler) CreateTopUp(c *gin.Context) {
 	var req models.TopUp
 	if err := c.ShouldBind(&req); err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusBadRequest,
-				Msg:       "Invalid request",
-			},
-			Err: err.Error(),
-		})
+		c.Error(apperror.Validation(err))
 		return
 	}
 
@@ -74,15 +59,7 @@ func (th *TopUpHandler) CreateTopUp(c *gin.Context) {
 
 	newTopup, err := th.topUpRepo.CreateTopUp(c, &req)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-				Msg:       "Failed to create top up",
-			},
-			Err: err.Error(),
-		})
+		c.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
@@ -90,7 +67,7 @@ func (th *TopUpHandler) CreateTopUp(c *gin.Context) {
 		Response: models.Response{
 			IsSuccess: true,
 			Code:      http.StatusCreated,
-			Msg:       "Create top up successfully",
+			Msg:       i18n.T(c, i18n.MsgTopUpCreated),
 		},
 		Data: newTopup,
 	})
@@ -101,78 +78,38 @@ func (th *TopUpHandler) MarkTopUpSuccess(c *gin.Context) {
 
 	userID, err := utils.GetUserFromCtx(c)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusUnauthorized,
-				Msg:       "Unauthorized",
-			},
-			Err: err.Error(),
-		})
+		c.Error(err)
 		return
 	}
 
 	walletID, err := th.topUpRepo.GetWalletIDByUserID(c, userID)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusNotFound,
-				Msg:       "Wallet not found",
-			},
-			Err: err.Error(),
-		})
+		c.Error(apperror.Wrap(apperror.WalletNotFound, err))
 		return
 	}
 
 	err = th.topUpRepo.UpdateStatusTopUp(c, topupID, models.TopUpSuccess)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-				Msg:       "Failed to update top up status",
-			},
-			Err: err.Error(),
-		})
+		c.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	topup, err := th.topUpRepo.GetTopUpByID(c, topupID)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-				Msg:       "Failed to get topup data",
-			},
-			Err: err.Error(),
-		})
+		c.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	err = th.topUpRepo.ApplyToWallet(c, walletID, topupID, topup.Amount)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-				Msg:       "Failed to update wallet balance",
-			},
-			Err: err.Error(),
-		})
+		c.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
 	c.JSON(http.StatusOK, models.Response{
 		IsSuccess: true,
 		Code:      http.StatusOK,
-		Msg:       "Topup applied to wallet",
+		Msg:       i18n.T(c, i18n.MsgTopUpApplied),
 	})
 }
 
@@ -188,32 +125,17 @@ func (th *TopUpHandler) MarkTopUpSuccess(c *gin.Context) {
 // @Failure      400  {object}  models.ErrorResponse
 // @Failure      401  {object}  models.ErrorResponse
 // @Failure      500  {object}  models.ErrorResponse
-// @Router       /topup [post]
+// @Router       /v1/topup [post]
 func (th *TopUpHandler) CreateTopUpTransaction(c *gin.Context) {
 	var req models.TopUpRequest
 	if err := c.ShouldBind(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusBadRequest,
-				Msg:       "Invalid request",
-			},
-			Err: err.Error(),
-		})
+		c.Error(apperror.Validation(err))
 		return
 	}
 
 	userID, err := utils.GetUserFromCtx(c)
 	if err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusUnauthorized,
-				Msg:       "Unauthorized",
-			},
-			Err: err.Error(),
-		})
+		c.Error(err)
 		return
 	}
 
@@ -225,14 +147,7 @@ func (th *TopUpHandler) CreateTopUpTransaction(c *gin.Context) {
 
 	newTopup, err := th.topUpRepo.CreateTopUpTransaction(c, topup, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Response: models.Response{
-				IsSuccess: false,
-				Code:      http.StatusInternalServerError,
-				Msg:       "Failed to process topup",
-			},
-			Err: err.Error(),
-		})
+		c.Error(apperror.Wrap(apperror.Internal, err))
 		return
 	}
 
@@ -240,7 +155,7 @@ func (th *TopUpHandler) CreateTopUpTransaction(c *gin.Context) {
 		Response: models.Response{
 			IsSuccess: true,
 			Code:      http.StatusCreated,
-			Msg:       "Topup successful",
+			Msg:       i18n.T(c, i18n.MsgTopUpSuccess),
 		},
 		Data: newTopup,
 	})
@@ -0,0 +1,93 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+)
+
+func TestGetPaymentMethods(t *testing.T) {
+	env := newTestEnv(t)
+	user, _ := env.store.AddUser("budi@mail.com", hash(t, "Rahasia#123"), "", 0)
+	env.store.AddPaymentMethod("BRI")
+	env.store.AddPaymentMethod("Dana")
+
+	rec, res := env.do(http.MethodGet, "/topup/methods", nil, user)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	methods := decodeData[[]models.PaymentMethod](t, res)
+	if len(methods) != 2 || methods[0].Name != "BRI" || methods[1].Name != "Dana" {
+		t.Errorf("methods = %+v, want BRI and Dana", methods)
+	}
+}
+
+func TestCreateTopUpTransaction(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    func(paymentID int) any
+		auth    bool
+		status  int
+		errCode string
+		balance int
+	}{
+		{
+			name:    "success",
+			body:    func(id int) any { return models.TopUpRequest{Amount: 50000, Tax: 2500, PaymentID: id} },
+			auth:    true,
+			status:  http.StatusCreated,
+			balance: 60000,
+		},
+		{
+			name:    "missing amount",
+			body:    func(id int) any { return map[string]int{"payment_id": id} },
+			auth:    true,
+			status:  http.StatusBadRequest,
+			errCode: "VALIDATION_FAILED",
+			balance: 10000,
+		},
+		{
+			name:    "unknown payment method",
+			body:    func(id int) any { return models.TopUpRequest{Amount: 50000, PaymentID: id + 1} },
+			auth:    true,
+			status:  http.StatusInternalServerError,
+			errCode: "INTERNAL_ERROR",
+			balance: 10000,
+		},
+		{
+			name:    "unauthenticated",
+			body:    func(id int) any { return models.TopUpRequest{Amount: 50000, PaymentID: id} },
+			status:  http.StatusUnauthorized,
+			errCode: "UNAUTHORIZED",
+			balance: 10000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			user, _ := env.store.AddUser("budi@mail.com", hash(t, "Rahasia#123"), "", 10000)
+			paymentID := env.store.AddPaymentMethod("BRI")
+
+			userID := 0
+			if tt.auth {
+				userID = user
+			}
+			rec, res := env.do(http.MethodPost, "/topup", tt.body(paymentID), userID)
+			if tt.errCode != "" {
+				assertError(t, rec, res, tt.status, tt.errCode)
+			} else {
+				if rec.Code != tt.status {
+					t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.status, rec.Body.String())
+				}
+				topup := decodeData[models.TopUp](t, res)
+				if topup.ID == 0 || topup.Status != models.TopUpSuccess || topup.Amount != 50000 {
+					t.Errorf("topup = %+v, want success topup of 50000", topup)
+				}
+			}
+			if got := env.balance(user); got != tt.balance {
+				t.Errorf("balance = %d, want %d", got, tt.balance)
+			}
+		})
+	}
+}
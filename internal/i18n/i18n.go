@@ -0,0 +1,128 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// supported language of API message
+const (
+	EN = "en"
+	ID = "id"
+)
+
+// Default is used when client doesn't send supported Accept-Language and has no profile preference
+const Default = EN
+
+var Supported = []string{EN, ID}
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+// catalogs[lang][key] = message
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[string]map[string]string {
+	catalogs := make(map[string]map[string]string, len(Supported))
+	for _, lang := range Supported {
+		raw, err := localeFS.ReadFile("locales/" + lang + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: read catalog %s: %v", lang, err))
+		}
+		var catalog map[string]string
+		if err := json.Unmarshal(raw, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: parse catalog %s: %v", lang, err))
+		}
+		catalogs[lang] = catalog
+	}
+	return catalogs
+}
+
+// Translate return message of key in lang, fallback to default language then to the key itself
+// args is used as fmt.Sprintf argument when message has verb
+func Translate(lang, key string, args ...any) string {
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		msg, ok = catalogs[Default][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// T translate key to language of the request
+func T(c context.Context, key string, args ...any) string {
+	return Translate(FromContext(c), key, args...)
+}
+
+// Normalize return supported language of tag (ex: "en-US" -> "en"), empty when not supported
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if slices.Contains(Supported, tag) {
+		return tag
+	}
+	return ""
+}
+
+// Negotiate pick supported language with highest quality from Accept-Language header
+// ex: "fr-FR,id;q=0.8,en;q=0.5" -> "id", ok is false when no language is supported
+func Negotiate(acceptLanguage string) (string, bool) {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		lang := Normalize(tag)
+		if lang == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{lang, q})
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	// stable, so on same quality the first one in header win
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang, true
+}
+
+type ctxKey struct{}
+
+// WithLang return copy of c carrying language of the request
+func WithLang(c context.Context, lang string) context.Context {
+	return context.WithValue(c, ctxKey{}, lang)
+}
+
+// FromContext return language of the request, Default if not set
+func FromContext(c context.Context) string {
+	if lang, ok := c.Value(ctxKey{}).(string); ok && lang != "" {
+		return lang
+	}
+	return Default
+}
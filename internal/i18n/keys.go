@@ -0,0 +1,56 @@
+package i18n
+
+// key of success message, error message use key "error.<CODE>" (see apperror)
+const (
+	MsgLoginSuccess             = "auth.login.success"
+	MsgRegisterSuccess          = "auth.register.success"
+	MsgLogoutSuccess            = "auth.logout.success"
+	MsgPasswordChanged          = "auth.password.changed"
+	MsgPasswordReset            = "auth.password.reset"
+	MsgResetPasswordLinkSent    = "auth.password.reset_link_sent"
+	MsgPINChanged               = "auth.pin.changed"
+	MsgPINUpdated               = "auth.pin.updated"
+	MsgPINReset                 = "auth.pin.reset"
+	MsgResetPINLinkSent         = "auth.pin.reset_link_sent"
+	MsgPINConfirmed             = "auth.pin.confirmed"
+	MsgProfileFetched           = "profile.fetched"
+	MsgProfileUpdated           = "profile.updated"
+	MsgAvatarDeleted            = "profile.avatar_deleted"
+	MsgBalanceFetched           = "balance.fetched"
+	MsgTransferSuccess          = "transfer.success"
+	MsgHistoryFetched           = "transaction.history.fetched"
+	MsgHistoryAllFetched        = "transaction.history_all.fetched"
+	MsgHistoryEmpty             = "transaction.history.empty"
+	MsgTransactionDeleted       = "transaction.deleted"
+	MsgTransactionDetailFetched = "transaction.detail.fetched"
+	MsgTransactionRestored      = "transaction.restored"
+	MsgTransactionsHidden       = "transaction.bulk.hidden"
+	MsgTransactionsRestored     = "transaction.bulk.restored"
+	MsgTrashFetched             = "transaction.trash.fetched"
+	MsgStatementEmailed         = "transaction.statement.emailed"
+	MsgReceiptVerified          = "receipt.verified"
+	MsgTopUpDeleted             = "topup.deleted"
+	MsgTopUpRestored            = "topup.restored"
+	MsgPaymentMethodsFetched    = "topup.payment_methods.fetched"
+	MsgTopUpCreated             = "topup.created"
+	MsgTopUpApplied             = "topup.applied"
+	MsgTopUpSuccess             = "topup.success"
+	MsgCategoriesFetched        = "category.fetched"
+	MsgCategoryRulesFetched     = "category.rules.fetched"
+	MsgCategoryRuleCreated      = "category.rule.created"
+	MsgCategoryRuleDeleted      = "category.rule.deleted"
+	MsgCategoryKeywordBlank     = "category.rule.keyword_blank"
+	MsgCategoryRuleLimit        = "category.rule.limit"
+	MsgTransactionCategorized   = "transaction.category.updated"
+	MsgBudgetsFetched           = "budget.fetched"
+	MsgBudgetSaved              = "budget.saved"
+	MsgBudgetDeleted            = "budget.deleted"
+	MsgBudgetOverall            = "budget.overall"
+	MsgCapturedMailsFetched     = "dev.mails.fetched"
+	MsgCapturedMailsDeleted     = "dev.mails.deleted"
+)
+
+// CategoryName is key of display name of category (see models.Categories)
+func CategoryName(category string) string {
+	return "category.name." + category
+}
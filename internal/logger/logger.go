@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+type ctxKey struct{}
+
+// New create JSON (or text) logger, every record pass through redaction first
+func New(w io.Writer, level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: ParseLevel(level)}
+	var handler slog.Handler
+	if strings.ToLower(format) == "text" {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(NewRedactHandler(handler))
+}
+
+// ParseLevel convert debug, info, warn, error into slog level, default info
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// WithContext put logger into context, used by request-id middleware
+func WithContext(c context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(c, ctxKey{}, l)
+}
+
+// FromContext return logger of the request (with request_id & trace_id)
+// fallback to default logger, so it is always safe to call
+func FromContext(c context.Context) *slog.Logger {
+	if c != nil {
+		if l, ok := c.Value(ctxKey{}).(*slog.Logger); ok {
+			return l
+		}
+	}
+	return slog.Default()
+}
@@ -0,0 +1,197 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// key that is never logged, compared after lowercase & removing _ and -
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"pass":          true,
+	"pwd":           true,
+	"pin":           true,
+	"token":         true,
+	"secret":        true,
+	"authorization": true,
+	"cookie":        true,
+	"otp":           true,
+	"apikey":        true,
+	"hash":          true,
+}
+
+// key suffix that is never logged, ex: old_password, new_pin, pin_sender, reset_token
+var sensitiveSuffixes = []string{"password", "pin", "pinsender", "token", "secret"}
+
+var (
+	reJWT    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	reBearer = regexp.MustCompile(`(?i)bearer\s+\S+`)
+	reArgon  = regexp.MustCompile(`\$argon2id\$\S+`)
+	reQuery  = regexp.MustCompile(`(?i)((?:token|pin|password)=)[^&\s"]+`)
+	reEmail  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	rePhone  = regexp.MustCompile(`(?:\+62|62|0)8[0-9]{7,12}`)
+)
+
+// RedactHandler wrap other handler, remove password, PIN, token, and mask email & phone
+// on message and every attribute, including attribute added with With
+type RedactHandler struct {
+	next slog.Handler
+}
+
+func NewRedactHandler(next slog.Handler) *RedactHandler {
+	return &RedactHandler{next: next}
+}
+
+func (h *RedactHandler) Enabled(c context.Context, level slog.Level) bool {
+	return h.next.Enabled(c, level)
+}
+
+func (h *RedactHandler) Handle(c context.Context, r slog.Record) error {
+	record := slog.NewRecord(r.Time, r.Level, RedactString(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		record.AddAttrs(redactAttr(a))
+		return true
+	})
+	return h.next.Handle(c, record)
+}
+
+func (h *RedactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clean := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		clean = append(clean, redactAttr(a))
+	}
+	return &RedactHandler{next: h.next.WithAttrs(clean)}
+}
+
+func (h *RedactHandler) WithGroup(name string) slog.Handler {
+	return &RedactHandler{next: h.next.WithGroup(name)}
+}
+
+// RedactString remove secret and mask email & phone inside free text (message, error)
+func RedactString(s string) string {
+	s = reJWT.ReplaceAllString(s, redacted)
+	s = reBearer.ReplaceAllString(s, "Bearer "+redacted)
+	s = reArgon.ReplaceAllString(s, redacted)
+	s = reQuery.ReplaceAllString(s, "${1}"+redacted)
+	s = reEmail.ReplaceAllStringFunc(s, MaskEmail)
+	s = rePhone.ReplaceAllStringFunc(s, MaskPhone)
+	return s
+}
+
+// MaskEmail keep first 2 character and domain, ex: johndoe@mail.com -> jo*****@mail.com
+func MaskEmail(email string) string {
+	name, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return redacted
+	}
+	keep := min(2, len(name))
+	return name[:keep] + strings.Repeat("*", len(name)-keep) + "@" + domain
+}
+
+// MaskPhone keep last 3 digit, ex: 081234567890 -> *********890
+func MaskPhone(phone string) string {
+	if len(phone) <= 3 {
+		return strings.Repeat("*", len(phone))
+	}
+	return strings.Repeat("*", len(phone)-3) + phone[len(phone)-3:]
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	if sensitiveKeys[key] {
+		return true
+	}
+	for _, suffix := range sensitiveSuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactAttr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+		clean := make([]slog.Attr, 0, len(group))
+		for _, ga := range group {
+			clean = append(clean, redactAttr(ga))
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(clean...)}
+	}
+	if isSensitiveKey(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, redactKeyString(a.Key, a.Value.String()))
+	case slog.KindAny:
+		return slog.Any(a.Key, redactAny(a.Key, a.Value.Any()))
+	default:
+		// number, bool, time & duration never contain secret
+		return a
+	}
+}
+
+func redactKeyString(key, value string) string {
+	lower := strings.ToLower(key)
+	switch {
+	case strings.Contains(lower, "email"):
+		return reEmail.ReplaceAllStringFunc(value, MaskEmail)
+	case strings.Contains(lower, "phone"):
+		return MaskPhone(value)
+	default:
+		return RedactString(value)
+	}
+}
+
+// struct & map is converted through JSON, so field is redacted by its json name
+func redactAny(key string, value any) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case error:
+		return RedactString(v.Error())
+	case fmt.Stringer:
+		return redactKeyString(key, v.String())
+	}
+
+	bt, err := json.Marshal(value)
+	if err != nil {
+		return redactKeyString(key, fmt.Sprint(value))
+	}
+	var generic any
+	if err := json.Unmarshal(bt, &generic); err != nil {
+		return redacted
+	}
+	return redactJSON(key, generic)
+}
+
+func redactJSON(key string, value any) any {
+	if isSensitiveKey(key) {
+		return redacted
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		for k, inner := range v {
+			v[k] = redactJSON(k, inner)
+		}
+		return v
+	case []any:
+		for i, inner := range v {
+			v[i] = redactJSON(key, inner)
+		}
+		return v
+	case string:
+		return redactKeyString(key, v)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// prefix of every metric in this project
+const namespace = "belalai"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Total HTTP request by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP request by method and route.",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"method", "route"})
+
+	redisErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "redis_command_errors_total",
+		Help:      "Total failed redis command by command name, redis.Nil is not counted.",
+	}, []string{"command"})
+
+	cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Total redis cache lookup by cache name and result (hit, miss, error).",
+	}, []string{"cache", "result"})
+
+	transfers = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfers_total",
+		Help:      "Total transfer attempt by status.",
+	}, []string{"status"})
+
+	topups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "topups_total",
+		Help:      "Total success topup by payment method.",
+	}, []string{"payment_method"})
+
+	topupAmount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "topup_amount_rupiah_total",
+		Help:      "Total topup volume in rupiah by payment method.",
+	}, []string{"payment_method"})
+
+	pinFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pin_attempts_failed_total",
+		Help:      "Total wrong PIN attempt by source (confirm_pin, change_pin, transfer).",
+	}, []string{"source"})
+)
+
+// status of transfer attempt
+const (
+	TransferSuccess             = "success"
+	TransferInvalidPIN          = "invalid_pin"
+	TransferInsufficientBalance = "insufficient_balance"
+	TransferToSelf              = "to_self"
+	TransferError               = "error"
+)
+
+// source of wrong PIN attempt
+const (
+	PINSourceConfirm  = "confirm_pin"
+	PINSourceChange   = "change_pin"
+	PINSourceTransfer = "transfer"
+)
+
+// result of cache lookup
+const (
+	CacheHit   = "hit"
+	CacheMiss  = "miss"
+	CacheError = "error"
+)
+
+// Handler expose all metric in prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+func ObserveHTTP(method, route string, status int, seconds float64) {
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(seconds)
+}
+
+func ObserveCache(cache, result string) {
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+func ObserveTransfer(status string) {
+	transfers.WithLabelValues(status).Inc()
+}
+
+func ObserveTopUp(paymentMethod string, amount int) {
+	topups.WithLabelValues(paymentMethod).Inc()
+	topupAmount.WithLabelValues(paymentMethod).Add(float64(amount))
+}
+
+func ObservePINFailure(source string) {
+	pinFailures.WithLabelValues(source).Inc()
+}
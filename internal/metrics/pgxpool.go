@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// pgxPoolCollector read pool stat on every scrape, so value is always current
+type pgxPoolCollector struct {
+	db *pgxpool.Pool
+
+	acquiredConns        *prometheus.Desc
+	idleConns            *prometheus.Desc
+	constructingConns    *prometheus.Desc
+	totalConns           *prometheus.Desc
+	maxConns             *prometheus.Desc
+	acquireCount         *prometheus.Desc
+	acquireDuration      *prometheus.Desc
+	emptyAcquireCount    *prometheus.Desc
+	canceledAcquireCount *prometheus.Desc
+}
+
+// RegisterPgxPool expose stat of pgx pool
+func RegisterPgxPool(db *pgxpool.Pool) error {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "pgxpool", name), help, nil, nil)
+	}
+	return prometheus.Register(&pgxPoolCollector{
+		db:                   db,
+		acquiredConns:        desc("acquired_conns", "Number of connection currently in use."),
+		idleConns:            desc("idle_conns", "Number of idle connection in pool."),
+		constructingConns:    desc("constructing_conns", "Number of connection being opened."),
+		totalConns:           desc("total_conns", "Total connection in pool."),
+		maxConns:             desc("max_conns", "Max connection of pool."),
+		acquireCount:         desc("acquire_total", "Total success acquire from pool."),
+		acquireDuration:      desc("acquire_duration_seconds_total", "Total time spent to acquire connection."),
+		emptyAcquireCount:    desc("empty_acquire_total", "Total acquire that had to wait because pool is empty."),
+		canceledAcquireCount: desc("canceled_acquire_total", "Total acquire cancelled by context."),
+	})
+}
+
+func (p *pgxPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- p.acquiredConns
+	ch <- p.idleConns
+	ch <- p.constructingConns
+	ch <- p.totalConns
+	ch <- p.maxConns
+	ch <- p.acquireCount
+	ch <- p.acquireDuration
+	ch <- p.emptyAcquireCount
+	ch <- p.canceledAcquireCount
+}
+
+func (p *pgxPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := p.db.Stat()
+	ch <- prometheus.MustNewConstMetric(p.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(p.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(p.constructingConns, prometheus.GaugeValue, float64(stat.ConstructingConns()))
+	ch <- prometheus.MustNewConstMetric(p.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(p.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(p.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(p.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(p.emptyAcquireCount, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(p.canceledAcquireCount, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/apperror"
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLog write one structured log per request, replace default gin logger
+// query string is not logged because it may carry token (ex: /events/stream?token=)
+func AccessLog(ctx *gin.Context) {
+	start := time.Now()
+	ctx.Next()
+
+	status := ctx.Writer.Status()
+	level := slog.LevelInfo
+	switch {
+	case status >= http.StatusInternalServerError:
+		level = slog.LevelError
+	case status >= http.StatusBadRequest:
+		level = slog.LevelWarn
+	}
+
+	attrs := []any{
+		"method", ctx.Request.Method,
+		"route", ctx.FullPath(),
+		"path", ctx.Request.URL.Path,
+		"status", status,
+		"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+		"client_ip", ctx.ClientIP(),
+		"bytes", ctx.Writer.Size(),
+	}
+	if len(ctx.Errors) > 0 {
+		attrs = append(attrs, "errors", ctx.Errors.String())
+	}
+	logger.FromContext(ctx.Request.Context()).Log(ctx.Request.Context(), level, "http request", attrs...)
+}
+
+// Recovery log panic with stack trace as structured log, then response 500 through ErrorHandler
+var Recovery = gin.CustomRecoveryWithWriter(nil, func(ctx *gin.Context, err any) {
+	logger.FromContext(ctx.Request.Context()).Error("panic recovered", "panic", err, "stack", string(debug.Stack()))
+	AbortWithError(ctx, apperror.New(apperror.Internal))
+})
@@ -7,16 +7,24 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// setup whitelist origin
+var whitelist = []string{"http://localhost:5173", "http://127.0.0.1:5173", "http://localhost:2409", "http://127.0.0.1:2409"}
+
+// IsAllowedOrigin check origin against whitelist, also used when upgrading websocket
+func IsAllowedOrigin(origin string) bool {
+	return slices.Contains(whitelist, origin)
+}
+
 func CORSMiddleware(ctx *gin.Context) {
-	// setup whitelist origin
-	whitelist := []string{"http://localhost:5173", "http://127.0.0.1:5173", "http://localhost:2409", "http://127.0.0.1:2409"}
 	origin := ctx.GetHeader("Origin")
-	if slices.Contains(whitelist, origin) {
+	if IsAllowedOrigin(origin) {
 		ctx.Header("Access-Control-Allow-Origin", origin)
 	}
 	// header untuk preflight cors
 	ctx.Header("Access-Control-Allow-Methods", "GET, POST, PATCH, PUT, DELETE, OPTIONS")
 	ctx.Header("Access-Control-Allow-Headers", "Authorization, Content-Type")
+	// let browser client read version & language header
+	ctx.Header("Access-Control-Expose-Headers", "Deprecation, Sunset, Link, Content-Language, X-Request-ID")
 	// tangani apabila bertemu preflight
 	if ctx.Request.Method == http.MethodOptions {
 		// ctx.Header("X-DEBUG", "preflight-handled")
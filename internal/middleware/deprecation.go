@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated mark route as deprecated (RFC 9745) with its sunset date (RFC 8594)
+// and link to the same route on successor version, ex: /auth -> /v1/auth
+// after sunset the route response 410 Gone, so old client is told to update instead of getting 404
+func Deprecated(deprecatedAt, sunsetAt time.Time, successor string) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	sunset := sunsetAt.UTC().Format(http.TimeFormat)
+	successor = strings.TrimSuffix(successor, "/")
+
+	return func(ctx *gin.Context) {
+		ctx.Header("Deprecation", deprecation)
+		ctx.Header("Sunset", sunset)
+		ctx.Header("Link", fmt.Sprintf("<%s%s>; rel=\"successor-version\"", successor, ctx.Request.URL.Path))
+
+		if !time.Now().Before(sunsetAt) {
+			AbortWithError(ctx, apperror.New(apperror.APIVersionGone))
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"github.com/Belalai-E-Wallet-Backend/internal/apperror"
+	"github.com/Belalai-E-Wallet-Backend/internal/i18n"
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler render error pushed by handler with ctx.Error as uniform models.ErrorResponse
+// handler only need `ctx.Error(apperror.New(...)); return`, the cause is logged by AccessLog
+// must be placed after AccessLog & Metrics so they see the final status
+func ErrorHandler(ctx *gin.Context) {
+	ctx.Next()
+
+	if len(ctx.Errors) == 0 || ctx.Writer.Written() {
+		return
+	}
+
+	appErr := apperror.From(ctx.Errors.Last().Err)
+	lang := i18n.FromContext(ctx.Request.Context())
+	ctx.AbortWithStatusJSON(appErr.Status(), models.ErrorResponse{
+		Response: models.Response{
+			IsSuccess: false,
+			Code:      appErr.Status(),
+		},
+		Err:     appErr.Code.Message(lang),
+		ErrCode: string(appErr.Code),
+		Details: appErr.Detail,
+	})
+}
+
+// AbortWithError push error to be rendered by ErrorHandler and stop the chain, used by middleware
+func AbortWithError(ctx *gin.Context, err error) {
+	_ = ctx.Error(err)
+	ctx.Abort()
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/Belalai-E-Wallet-Backend/internal/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// Locale negotiate language of message from Accept-Language, get it with i18n.FromContext
+// on authenticated route VerifyToken replace it with language preference on user profile
+func Locale(ctx *gin.Context) {
+	lang, ok := i18n.Negotiate(ctx.GetHeader("Accept-Language"))
+	if !ok {
+		lang = i18n.Default
+	}
+	ctx.Header("Vary", "Accept-Language")
+	setLang(ctx, lang)
+	ctx.Next()
+}
+
+func setLang(ctx *gin.Context, lang string) {
+	ctx.Request = ctx.Request.WithContext(i18n.WithLang(ctx.Request.Context(), lang))
+	ctx.Header("Content-Language", lang)
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics record latency & status of every request
+// route template (/transaction/:id) is used as label instead of real path, so cardinality stay low
+func Metrics(ctx *gin.Context) {
+	start := time.Now()
+	ctx.Next()
+
+	route := ctx.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+	metrics.ObserveHTTP(ctx.Request.Method, route, ctx.Writer.Status(), time.Since(start).Seconds())
+}
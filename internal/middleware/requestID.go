@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// request id from client is only accepted when it is short & safe to be logged
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID give every request an id (from X-Request-ID or generated), returned on response header
+// logger with request_id & trace_id is put into request context, get it with logger.FromContext
+func RequestID(ctx *gin.Context) {
+	requestID := ctx.GetHeader(RequestIDHeader)
+	if !validRequestID.MatchString(requestID) {
+		requestID = newRequestID()
+	}
+	ctx.Header(RequestIDHeader, requestID)
+
+	l := logger.FromContext(ctx.Request.Context()).With("request_id", requestID)
+	if span := trace.SpanContextFromContext(ctx.Request.Context()); span.HasTraceID() {
+		l = l.With("trace_id", span.TraceID().String())
+	}
+	ctx.Request = ctx.Request.WithContext(logger.WithContext(ctx.Request.Context(), l))
+	ctx.Next()
+}
+
+func newRequestID() string {
+	bt := make([]byte, 16)
+	rand.Read(bt)
+	return hex.EncodeToString(bt)
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// TokenFromQuery allow token sent as query ?token=<jwt>
+// browser EventSource and WebSocket can't set Authorization header
+// must be placed before VerifyToken
+func TokenFromQuery(ctx *gin.Context) {
+	if ctx.GetHeader("Authorization") == "" {
+		if token := ctx.Query("token"); token != "" {
+			ctx.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	ctx.Next()
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/tracing"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing start server span of every request
+// trace id from incoming traceparent header is continued, and the span context is put on request context
+// so query & redis command inside handler become child span
+func Tracing(ctx *gin.Context) {
+	propagator := otel.GetTextMapPropagator()
+	parent := propagator.Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+
+	route := ctx.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+	spanCtx, span := tracing.Tracer().Start(parent, fmt.Sprintf("%s %s", ctx.Request.Method, route),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(ctx.Request.Method),
+			semconv.HTTPRoute(route),
+			semconv.URLPath(ctx.Request.URL.Path),
+			semconv.ClientAddress(ctx.ClientIP()),
+			semconv.UserAgentOriginal(ctx.Request.UserAgent()),
+		),
+	)
+	defer span.End()
+
+	ctx.Request = ctx.Request.WithContext(spanCtx)
+	// return trace id to client, useful when reporting a problem
+	propagator.Inject(spanCtx, propagation.HeaderCarrier(ctx.Writer.Header()))
+
+	ctx.Next()
+
+	status := ctx.Writer.Status()
+	span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+	if status >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+	if len(ctx.Errors) > 0 {
+		span.RecordError(ctx.Errors.Last())
+	}
+}
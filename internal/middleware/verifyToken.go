@@ -1,29 +1,28 @@
 package middleware
 
 import (
-	"fmt"
-	"log"
-	"net/http"
+	"errors"
 	"strings"
 
+	"github.com/Belalai-E-Wallet-Backend/internal/apperror"
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+	"github.com/Belalai-E-Wallet-Backend/internal/i18n"
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
 	"github.com/Belalai-E-Wallet-Backend/pkg"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/redis/go-redis/v9"
 )
 
-func VerifyToken(rdb *redis.Client) gin.HandlerFunc {
+func VerifyToken(rdb *redis.Client, jwtCfg configs.JWTConfig) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// ambil token dari header
 		bearerToken := ctx.GetHeader("Authorization")
 
 		// Check if Authorization header is empty
 		if bearerToken == "" {
-			log.Println("Authorization header is missing")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   "Silahkan login terlebih dahulu",
-			})
+			AbortWithError(ctx, apperror.New(apperror.TokenMissing))
 			return
 		}
 
@@ -31,22 +30,8 @@ func VerifyToken(rdb *redis.Client) gin.HandlerFunc {
 		parts := strings.Split(bearerToken, " ")
 
 		// Check if the format is correct (should be "Bearer <token>")
-		if len(parts) != 2 {
-			log.Println("Invalid Authorization header format. Expected: Bearer <token>")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   "Format authorization header tidak valid",
-			})
-			return
-		}
-
-		// Validate Bearer prefix
-		if parts[0] != "Bearer" {
-			log.Println("Authorization header must start with 'Bearer'")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   "Format authorization header tidak valid",
-			})
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			AbortWithError(ctx, apperror.New(apperror.TokenMalformed))
 			return
 		}
 
@@ -54,60 +39,40 @@ func VerifyToken(rdb *redis.Client) gin.HandlerFunc {
 
 		// Check if token is empty
 		if token == "" {
-			log.Println("Token is empty after Bearer")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   "Silahkan login terlebih dahulu",
-			})
+			AbortWithError(ctx, apperror.New(apperror.TokenMissing))
 			return
 		}
 
 		// !DO cek token from redis if it not blacklisted
 		isBlacklisted, err := rdb.Get(ctx, "Belalai-E-wallet:blacklist:"+bearerToken).Result()
 		if err == nil && isBlacklisted == "true" {
-			log.Println("Token sudah logout, silahkan login kembali")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   "Token sudah logout, silahkan login kembali",
-			})
+			AbortWithError(ctx, apperror.New(apperror.TokenRevoked))
 			return
 		} else if err != redis.Nil && err != nil {
-			log.Println("Error when checking blacklist redis cache:", err)
-			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"error":   "Internal Server Error",
-			})
+			AbortWithError(ctx, apperror.Wrap(apperror.Internal, err))
 			return
 		}
 
 		// verify token jwt
 		var claims pkg.Claims
-		if err := claims.VerifyToken(token); err != nil {
-			if strings.Contains(err.Error(), jwt.ErrTokenInvalidIssuer.Error()) {
-				log.Println("JWT Error.\nCause: ", err.Error())
-				ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-					"success": false,
-					"error":   "Silahkan login kembali",
-				})
-				return
-			}
-			if strings.Contains(err.Error(), jwt.ErrTokenExpired.Error()) {
-				log.Println("JWT Error.\nCause: ", err.Error())
-				ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-					"success": false,
-					"error":   "Silahkan login kembali",
-				})
+		if err := claims.VerifyToken(token, jwtCfg.Secret, jwtCfg.Issuer); err != nil {
+			if errors.Is(err, jwt.ErrTokenExpired) {
+				AbortWithError(ctx, apperror.Wrap(apperror.TokenExpired, err))
 				return
 			}
-			fmt.Println(jwt.ErrTokenExpired)
-			log.Println("Internal Server Error.\nCause: ", err.Error())
-			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"error":   "Internal Server Error",
-			})
+			AbortWithError(ctx, apperror.Wrap(apperror.TokenInvalid, err))
 			return
 		}
 		ctx.Set("claims", &claims)
+
+		// language preference of user win over Accept-Language
+		lang, err := rdb.Get(ctx, utils.UserLanguageKey(claims.UserId)).Result()
+		if err != nil && err != redis.Nil {
+			logger.FromContext(ctx).Warn("Error when getting user language", "err", err)
+		}
+		if lang = i18n.Normalize(lang); lang != "" {
+			setLang(ctx, lang)
+		}
 		ctx.Next()
 	}
 }
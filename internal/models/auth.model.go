@@ -7,6 +7,7 @@ type User struct {
 	Email     string     `db:"email"`
 	Password  string     `db:"password"`
 	Pin       *string    `db:"pin"`
+	Language  string     `db:"language"`
 	CreatedAt time.Time  `db:"created_at"`
 	UpdatedAt *time.Time `db:"updated_at"`
 }
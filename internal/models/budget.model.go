@@ -0,0 +1,82 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// BudgetThresholds is percent of budget notified once a month when spending reach it, lowest first
+var BudgetThresholds = []int{80, 100}
+
+// Budget is monthly spending limit, empty category is overall budget of every spending category
+type Budget struct {
+	ID        int        `json:"id"`
+	Category  string     `json:"category"`
+	Amount    int        `json:"amount"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// BudgetRequest create budget of category or replace its amount
+type BudgetRequest struct {
+	Category string `json:"category" binding:"omitempty,oneof=food transport shopping bills entertainment health education family other" example:"food"`
+	Amount   int    `json:"amount" binding:"required,min=1" example:"1500000"`
+}
+
+// BudgetQuery is month of budget progress, default is this month
+type BudgetQuery struct {
+	Month *time.Time `form:"month" time_format:"2006-01" time_utc:"1"`
+}
+
+// BudgetProgress is spending of budget on a month
+type BudgetProgress struct {
+	Budget
+	Spent int `json:"spent"`
+	// negative when spending is over the budget
+	Remaining int `json:"remaining"`
+	// spent of amount, 0-100+ with 2 decimal
+	Percentage float64 `json:"percentage"`
+	// highest of BudgetThresholds reached, 0 when none
+	Threshold int `json:"threshold"`
+}
+
+func NewBudgetProgress(b Budget, spent int) BudgetProgress {
+	p := BudgetProgress{Budget: b, Spent: spent, Remaining: b.Amount - spent}
+	if b.Amount > 0 {
+		p.Percentage = math.Round(float64(spent)*10000/float64(b.Amount)) / 100
+	}
+	for _, threshold := range BudgetThresholds {
+		// compare integer so 79.999% isn't rounded up to 80%
+		if spent*100 >= b.Amount*threshold {
+			p.Threshold = threshold
+		}
+	}
+	return p
+}
+
+// BudgetsProgress is every budget of user on a month, overall budget first
+type BudgetsProgress struct {
+	Month    string           `json:"month"`
+	Timezone string           `json:"timezone"`
+	Budgets  []BudgetProgress `json:"budgets"`
+}
+
+// NewBudgetsProgress take spending of the month per category, overall budget spent every category
+func NewBudgetsProgress(r ChartRange, budgets []Budget, totals []CategoryTotal) BudgetsProgress {
+	spent := map[string]int{}
+	for _, t := range totals {
+		spent[t.Category] += t.Amount
+		spent[""] += t.Amount
+	}
+	p := BudgetsProgress{Month: r.Label(r.From), Timezone: r.Location.String(), Budgets: make([]BudgetProgress, len(budgets))}
+	for i, b := range budgets {
+		p.Budgets[i] = NewBudgetProgress(b, spent[b.Category])
+	}
+	return p
+}
+
+// BudgetAlert is sent to user when spending of a budget reach a threshold
+type BudgetAlert struct {
+	BudgetProgress
+	Month string `json:"month"`
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// spending category is given to sent transfer by rule, incoming money is always income or topup unless user pick another one
+var (
+	SpendingCategories = []string{"food", "transport", "shopping", "bills", "entertainment", "health", "education", "family", "other"}
+	Categories         = append(append([]string{}, SpendingCategories...), "income", "topup")
+)
+
+// MaxCategoryRules limit rule of one user, every rule is checked for every sent transfer
+const MaxCategoryRules = 50
+
+type Category struct {
+	Key  string `json:"key" example:"food"`
+	Name string `json:"name" example:"Makanan & Minuman"`
+	// Spending category can be used by rule
+	Spending bool `json:"spending"`
+}
+
+// CategoryRule give category to sent transfer whose notes or receiver name contain keyword
+type CategoryRule struct {
+	ID int `json:"id"`
+	// notes or counterparty (name of receiver)
+	Field string `json:"field"`
+	// lowercase, matched case insensitive as part of the field
+	Keyword   string    `json:"keyword"`
+	Category  string    `json:"category"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CategoryRuleRequest struct {
+	Field    string `json:"field" binding:"required,oneof=notes counterparty" example:"notes"`
+	Keyword  string `json:"keyword" binding:"required,max=50" example:"kopi"`
+	Category string `json:"category" binding:"required,oneof=food transport shopping bills entertainment health education family other" example:"food"`
+}
+
+// CategoryRequest is body of picking category of one transaction, empty category let rule decide again
+type CategoryRequest struct {
+	Category string `json:"category" binding:"omitempty,oneof=food transport shopping bills entertainment health education family other income topup" example:"food"`
+}
+
+// CategoryTotal is success sent transfer of one category
+type CategoryTotal struct {
+	Category string `json:"category"`
+	Amount   int    `json:"amount"`
+	Count    int    `json:"count"`
+	// share of amount from total spending, 0-100 with 2 decimal
+	Percentage float64 `json:"percentage"`
+}
+
+// CategoryBreakdown is spending of range grouped by category, largest first
+type CategoryBreakdown struct {
+	From       string          `json:"from"`
+	To         string          `json:"to"`
+	Timezone   string          `json:"timezone"`
+	Total      int             `json:"total"`
+	Categories []CategoryTotal `json:"categories"`
+}
+
+// NewCategoryBreakdown fill total & percentage of every category
+func NewCategoryBreakdown(r ChartRange, totals []CategoryTotal) CategoryBreakdown {
+	b := CategoryBreakdown{
+		From:       r.From.Format(time.DateOnly),
+		To:         r.To.Format(time.DateOnly),
+		Timezone:   r.Location.String(),
+		Categories: make([]CategoryTotal, len(totals)),
+	}
+	for _, t := range totals {
+		b.Total += t.Amount
+	}
+	for i, t := range totals {
+		if b.Total > 0 {
+			t.Percentage = math.Round(float64(t.Amount)*10000/float64(b.Total)) / 100
+		}
+		b.Categories[i] = t
+	}
+	return b
+}
+
+// CounterpartyTotal is success transfer sent to one user
+type CounterpartyTotal struct {
+	UserID         int    `json:"user_id"`
+	Name           string `json:"name"`
+	ProfilePicture string `json:"profile_picture"`
+	Amount         int    `json:"amount"`
+	Count          int    `json:"count"`
+}
+
+// InsightQuery is range of category & counterparty insight, default is this month until today
+type InsightQuery struct {
+	From *time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To   *time.Time `form:"to" time_format:"2006-01-02" time_utc:"1"`
+	// IANA timezone, ex: Asia/Jakarta
+	Timezone string `form:"tz"`
+	// only used by top counterparties, default 5
+	Limit int `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// Range of query in loc, from & to which isn't sent is start of month of today & today
+func (q InsightQuery) Range(now time.Time, loc *time.Location) (ChartRange, error) {
+	now = now.In(loc)
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+	to := now
+	if q.From != nil {
+		from = *q.From
+	}
+	if q.To != nil {
+		to = *q.To
+	}
+	// month bucket so range of a few years is still allowed
+	return NewChartRange(from, to, "month", loc)
+}
+
+// CompareQuery is month of month over month comparison, default is this month
+type CompareQuery struct {
+	Month    *time.Time `form:"month" time_format:"2006-01" time_utc:"1"`
+	Timezone string     `form:"tz"`
+}
+
+// Change is value of a month next to the previous month
+type Change struct {
+	Current    int `json:"current"`
+	Previous   int `json:"previous"`
+	Difference int `json:"difference"`
+	// nil when previous month is 0
+	Percentage *float64 `json:"percentage"`
+}
+
+func NewChange(current, previous int) Change {
+	c := Change{Current: current, Previous: previous, Difference: current - previous}
+	if previous != 0 {
+		p := math.Round(float64(c.Difference)*10000/float64(previous)) / 100
+		c.Percentage = &p
+	}
+	return c
+}
+
+type CategoryChange struct {
+	Category string `json:"category"`
+	Change
+}
+
+// MonthComparison is income, spending and spending per category of month next to previous month
+type MonthComparison struct {
+	Month         string           `json:"month"`
+	PreviousMonth string           `json:"previous_month"`
+	Timezone      string           `json:"timezone"`
+	Income        Change           `json:"income"`
+	Expense       Change           `json:"expense"`
+	Categories    []CategoryChange `json:"categories"`
+}
+
+// NewMonthComparison take chart of both month (month granularity, previous first) and spending per category of each month,
+// category spent on current month come first by its amount, then category only spent on previous month
+func NewMonthComparison(r ChartRange, chart ChartData, current, previous []CategoryTotal) MonthComparison {
+	m := MonthComparison{Timezone: r.Location.String(), Categories: []CategoryChange{}}
+	if len(chart.Labels) == 2 {
+		m.PreviousMonth, m.Month = chart.Labels[0], chart.Labels[1]
+		m.Income = NewChange(chart.IncomeData[1], chart.IncomeData[0])
+		m.Expense = NewChange(chart.ExpenseData[1], chart.ExpenseData[0])
+	}
+
+	amounts := map[string][2]int{}
+	var order []string
+	add := func(totals []CategoryTotal, i int) {
+		for _, t := range totals {
+			a, ok := amounts[t.Category]
+			if !ok {
+				order = append(order, t.Category)
+			}
+			a[i] = t.Amount
+			amounts[t.Category] = a
+		}
+	}
+	// both is sorted by amount, so current first keep order of current month
+	add(current, 0)
+	add(previous, 1)
+	for _, category := range order {
+		a := amounts[category]
+		m.Categories = append(m.Categories, CategoryChange{Category: category, Change: NewChange(a[0], a[1])})
+	}
+	return m
+}
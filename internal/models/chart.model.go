@@ -1,12 +1,164 @@
 package models
 
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
 type ChartData struct {
 	Labels      []string `json:"labels"`
 	IncomeData  []int    `json:"income_data"`
 	ExpenseData []int    `json:"expense_data"`
+	// day, week or month, label of week is its monday
+	Granularity string `json:"granularity"`
+	Timezone    string `json:"timezone"`
 }
 
 type ChartDataResponse struct {
 	Response
 	Data ChartData `json:"data"`
 }
+
+// ChartQuery is query of chart with custom range
+type ChartQuery struct {
+	// From & To is date in Timezone, both inclusive
+	From        time.Time `form:"from" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+	To          time.Time `form:"to" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+	Granularity string    `form:"granularity" binding:"omitempty,oneof=day week month"`
+	// IANA timezone, ex: Asia/Jakarta
+	Timezone string `form:"tz"`
+}
+
+// MaxChartBuckets limit point of one chart, a year of day
+const MaxChartBuckets = 366
+
+// ChartRange is period of chart, every bucket start & label is in Location
+type ChartRange struct {
+	// From & To is midnight of the first & last date, both inclusive
+	From        time.Time
+	To          time.Time
+	Granularity string
+	Location    *time.Location
+}
+
+// NewChartRange take date of from & to (time of day is ignored) in loc, granularity default to day
+func NewChartRange(from, to time.Time, granularity string, loc *time.Location) (ChartRange, error) {
+	if granularity == "" {
+		granularity = "day"
+	}
+	r := ChartRange{
+		From:        time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc),
+		To:          time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc),
+		Granularity: granularity,
+		Location:    loc,
+	}
+	switch granularity {
+	case "day", "week", "month":
+	default:
+		return ChartRange{}, fmt.Errorf("granularity %q is invalid, use day, week or month", granularity)
+	}
+	if r.From.After(r.To) {
+		return ChartRange{}, errors.New("from must not be after to")
+	}
+	// counted without building the buckets, huge range (ex: year 1 to 9999) must not allocate anything
+	if n := r.bucketCount(); n > MaxChartBuckets {
+		return ChartRange{}, fmt.Errorf("range has %d %s, max is %d", n, granularity, MaxChartBuckets)
+	}
+	return r, nil
+}
+
+// ChartPreset is range of preset duration ending today (in loc of now): seven_days, five_weeks or twelve_months
+func ChartPreset(name string, now time.Time) (ChartRange, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch name {
+	case "seven_days":
+		return NewChartRange(today.AddDate(0, 0, -6), today, "day", now.Location())
+	case "five_weeks":
+		// last 30 days grouped by week
+		return NewChartRange(today.AddDate(0, 0, -29), today, "week", now.Location())
+	case "twelve_months":
+		return NewChartRange(time.Date(today.Year(), today.Month()-11, 1, 0, 0, 0, 0, now.Location()), today, "month", now.Location())
+	}
+	return ChartRange{}, fmt.Errorf("duration %q is invalid, use seven_days, five_weeks or twelve_months", name)
+}
+
+// End is the instant right after the last date
+func (r ChartRange) End() time.Time {
+	return r.To.AddDate(0, 0, 1)
+}
+
+// Truncate return start of bucket of t like DATE_TRUNC on postgres (week start on monday)
+func (r ChartRange) Truncate(t time.Time) time.Time {
+	t = t.In(r.Location)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, r.Location)
+	switch r.Granularity {
+	case "week":
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, r.Location)
+	}
+	return day
+}
+
+// Buckets is start of every bucket touching the range, oldest first
+func (r ChartRange) Buckets() []time.Time {
+	var buckets []time.Time
+	for start := r.Truncate(r.From); !start.After(r.To); start = r.next(start) {
+		buckets = append(buckets, start)
+	}
+	return buckets
+}
+
+// bucketCount is len(Buckets()), counted from the calendar date so DST doesn't change it
+func (r ChartRange) bucketCount() int {
+	if r.Granularity == "month" {
+		return (r.To.Year()*12 + int(r.To.Month())) - (r.From.Year()*12 + int(r.From.Month())) + 1
+	}
+	first := r.Truncate(r.From)
+	// Sub saturate instead of overflow on range longer than ~290 years, still far above the max
+	days := int(dateOf(r.To).Sub(dateOf(first)).Hours() / 24)
+	if r.Granularity == "week" {
+		return days/7 + 1
+	}
+	return days + 1
+}
+
+// dateOf is midnight UTC of the date of t, whose days differ by exactly 24 hour
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (r ChartRange) next(start time.Time) time.Time {
+	switch r.Granularity {
+	case "week":
+		return start.AddDate(0, 0, 7)
+	case "month":
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// Label of bucket, YYYY-MM for month, otherwise YYYY-MM-DD
+func (r ChartRange) Label(start time.Time) string {
+	if r.Granularity == "month" {
+		return start.Format("2006-01")
+	}
+	return start.Format(time.DateOnly)
+}
+
+// Empty is chart of the range without any transaction
+func (r ChartRange) Empty() ChartData {
+	buckets := r.Buckets()
+	data := ChartData{
+		Labels:      make([]string, len(buckets)),
+		IncomeData:  make([]int, len(buckets)),
+		ExpenseData: make([]int, len(buckets)),
+		Granularity: r.Granularity,
+		Timezone:    r.Location.String(),
+	}
+	for i, start := range buckets {
+		data.Labels[i] = r.Label(start)
+	}
+	return data
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Cursor is position of a row on keyset pagination, row is ordered by (created_at, id) newest first
+// client only see it as opaque string (next_cursor / prev_cursor)
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        int       `json:"i"`
+	// kind of transaction (transfer, topup), tie breaker for row of different kind with the same id
+	Kind string `json:"k,omitempty"`
+	// Backward read rows newer than the cursor, that is the page before
+	Backward bool `json:"b,omitempty"`
+}
+
+// CursorPage is one page of keyset pagination, cursor is nil when there is no page on that direction
+type CursorPage[T any] struct {
+	Items []T
+	Next  *Cursor
+	Prev  *Cursor
+}
+
+var ErrCursorInvalid = errors.New("invalid cursor")
+
+func (c *Cursor) Encode() string {
+	if c == nil {
+		return ""
+	}
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrCursorInvalid
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.CreatedAt.IsZero() || c.ID <= 0 {
+		return nil, ErrCursorInvalid
+	}
+	return &c, nil
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+type EventType string
+
+const (
+	EventBalanceUpdated     EventType = "balance.updated"
+	EventTransactionCreated EventType = "transaction.created"
+	EventBudgetAlert        EventType = "budget.alert"
+)
+
+// UserEvent is pushed to every connected device of a user
+type UserEvent struct {
+	Type      EventType `json:"type"`
+	Data      any       `json:"data"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type BalanceEvent struct {
+	Balance int `json:"balance"`
+}
+
+type TransactionEvent struct {
+	ID             int       `json:"id"`
+	Type           string    `json:"transaction_type"`
+	Amount         int       `json:"amount"`
+	Status         string    `json:"status"`
+	Notes          string    `json:"notes"`
+	CounterpartyID int       `json:"counterparty_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
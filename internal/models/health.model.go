@@ -0,0 +1,26 @@
+package models
+
+const (
+	HealthOK       = "ok"
+	HealthDown     = "down"
+	HealthDraining = "draining"
+)
+
+type HealthStatus struct {
+	Status string                      `json:"status" example:"ok"`
+	Checks map[string]DependencyStatus `json:"checks,omitempty"`
+}
+
+// DependencyStatus is result of checking one dependency (postgres, redis, mail, migration)
+type DependencyStatus struct {
+	Status    string  `json:"status" example:"ok"`
+	LatencyMs float64 `json:"latency_ms" example:"1.25"`
+	Error     string  `json:"error,omitempty"`
+	Detail    any     `json:"detail,omitempty"`
+}
+
+type MigrationStatus struct {
+	Version  uint `json:"version" example:"9"`
+	Expected uint `json:"expected" example:"9"`
+	Dirty    bool `json:"dirty"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// MailRecipient is data needed to send email in user preferred language
+type MailRecipient struct {
+	UserID   int    `db:"user_id"`
+	Email    string `db:"email"`
+	Fullname string `db:"fullname"`
+	Language string `db:"language"`
+}
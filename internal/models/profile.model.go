@@ -11,6 +11,7 @@ type Profile struct {
 	Phone          *string    `db:"phone"`
 	ProfilePicture *string    `db:"profile_picture"`
 	Email          *string    `db:"email"`
+	Language       *string    `db:"language"`
 	CreatedAt      time.Time  `db:"created_at"`
 	UpdatedAt      *time.Time `db:"updated_at"`
 }
@@ -20,6 +21,7 @@ type ProfileRequest struct {
 	Phone          *string               `json:"phone" form:"phone"`
 	ProfilePicture *multipart.FileHeader `form:"profile_picture"`
 	Email          *string               `json:"email" form:"email"`
+	Language       *string               `json:"language" form:"language" binding:"omitempty,oneof=en id"`
 }
 
 type ProfileResponse struct {
@@ -28,14 +30,25 @@ type ProfileResponse struct {
 	Phone          *string    `json:"phone"`
 	ProfilePicture *string    `json:"profile_picture"`
 	Email          string     `json:"email"`
+	Language       *string    `json:"language,omitempty"`
 	CreatedAt      *time.Time `json:"created_at"`
 	UpdatedAt      *time.Time `json:"updated_at"`
 }
 
 type ListprofileResponse struct {
-	Users     []ProfileResponse `json:"users"`
-	Page      int               `json:"page"`
-	Limit     int               `json:"limit"`
-	TotalUser int               `json:"total"`
-	TotalPage int               `json:"total_pages"`
+	Users      []ProfileResponse `json:"users"`
+	Page       int               `json:"page"`
+	Limit      int               `json:"limit"`
+	TotalUser  int               `json:"total"`
+	TotalPage  int               `json:"total_pages"`
+	NextCursor string            `json:"next_cursor"`
+	PrevCursor string            `json:"prev_cursor"`
+}
+
+// CursorProfileResponse is page of user search with cursor, total isn't counted
+type CursorProfileResponse struct {
+	Users      []ProfileResponse `json:"users"`
+	Limit      int               `json:"limit"`
+	NextCursor string            `json:"next_cursor"`
+	PrevCursor string            `json:"prev_cursor"`
 }
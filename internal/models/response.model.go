@@ -9,7 +9,9 @@ type Response struct {
 
 type ErrorResponse struct {
 	Response
-	Err string `json:"error" example:"Error message..."`
+	Err     string `json:"error" example:"Error message..."`
+	ErrCode string `json:"error_code" example:"INTERNAL_ERROR"`
+	Details string `json:"details,omitempty" example:"Detail of the error..."`
 }
 
 type ResponseData struct {
@@ -23,22 +25,26 @@ type BadRequestResponse struct {
 	IsSuccess bool   `json:"is_success" example:"false"`
 	Code      int    `json:"code,omitempty" example:"400"`
 	Err       string `json:"error" example:"Example bad request error..."`
+	ErrCode   string `json:"error_code" example:"VALIDATION_FAILED"`
 }
 
 type UnauthorizedResponse struct {
 	IsSuccess bool   `json:"is_success" example:"false"`
 	Code      int    `json:"code,omitempty" example:"401"`
 	Err       string `json:"error" example:"Unauthorized : please login again..."`
+	ErrCode   string `json:"error_code" example:"TOKEN_EXPIRED"`
 }
 
 type NotFoundResponse struct {
 	IsSuccess bool   `json:"is_success" example:"false"`
 	Code      int    `json:"code,omitempty" example:"404"`
 	Err       string `json:"error" example:"Not Found"`
+	ErrCode   string `json:"error_code" example:"NOT_FOUND"`
 }
 
 type InternalErrorResponse struct {
 	IsSuccess bool   `json:"is_success" example:"false"`
 	Code      int    `json:"code,omitempty" example:"500"`
 	Err       string `json:"error" example:"Example Internal server error..."`
+	ErrCode   string `json:"error_code" example:"INTERNAL_ERROR"`
 }
@@ -1,19 +1,30 @@
 // models/transaction.go
 package models
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
 
 type TransactionHistory struct {
-	ID             int       `json:"id" db:"id"`
-	Type           string    `json:"transaction_type" db:"transaction_type"`
-	ProfilePicture string    `json:"profile_picture" db:"profile_picture"`
-	ContactName    string    `json:"contact_name" db:"contact_name"`
-	PhoneNumber    string    `json:"phone_number" db:"phone_number"`
-	Amount         string    `json:"amount" db:"display_amount"`
-	OriginalAmount int       `json:"original_amount" db:"original_amount"`
-	Status         string    `json:"status" db:"status"`
-	Notes          string    `json:"notes" db:"notes"`
-	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	ID int `json:"id" db:"id"`
+	// transfer or topup, id is unique per kind
+	Kind           string `json:"kind" db:"kind"`
+	Type           string `json:"transaction_type" db:"transaction_type"`
+	ProfilePicture string `json:"profile_picture" db:"profile_picture"`
+	// user on the other side of transfer, 0 for topup
+	CounterpartyID int    `json:"counterparty_id,omitempty" db:"counterparty_user_id"`
+	ContactName    string `json:"contact_name" db:"contact_name"`
+	PhoneNumber    string `json:"phone_number" db:"phone_number"`
+	Amount         string `json:"amount" db:"display_amount"`
+	OriginalAmount int    `json:"original_amount" db:"original_amount"`
+	Status         string `json:"status" db:"status"`
+	Notes          string `json:"notes" db:"notes"`
+	// picked by user, otherwise by rule (see models.Categories)
+	Category  string    `json:"category" db:"category"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
 type TransactionHistoryRequest struct {
@@ -26,6 +37,162 @@ type TransactionHistoryResponse struct {
 	Data    []TransactionHistory `json:"data,omitempty"`
 }
 
+// TransactionParty is one side of transaction, sender of topup is its payment method (no user)
+type TransactionParty struct {
+	UserID         int    `json:"user_id,omitempty"`
+	WalletID       int    `json:"wallet_id,omitempty"`
+	Name           string `json:"name"`
+	Phone          string `json:"phone,omitempty"`
+	ProfilePicture string `json:"profile_picture,omitempty"`
+}
+
+type TransactionStatusEvent struct {
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TransactionDetail is one transfer or topup, type is from the view of user who request it
+type TransactionDetail struct {
+	ID              int              `json:"id"`
+	Kind            string           `json:"kind"`
+	Type            string           `json:"transaction_type"`
+	ReferenceNumber string           `json:"reference_number"`
+	Amount          int              `json:"amount"`
+	Fee             int              `json:"fee"`
+	Total           int              `json:"total"`
+	Status          string           `json:"status"`
+	Notes           string           `json:"notes"`
+	Sender          TransactionParty `json:"sender"`
+	Receiver        TransactionParty `json:"receiver"`
+	// oldest first, the last one is the current status
+	Timeline  []TransactionStatusEvent `json:"timeline"`
+	CreatedAt time.Time                `json:"created_at"`
+	UpdatedAt *time.Time               `json:"updated_at"`
+	// ReceiptCode is signed reference number, anyone can check it on receipt verify endpoint
+	ReceiptCode string `json:"receipt_code"`
+}
+
+// IsParty report whether user is sender or receiver of transaction
+func (d *TransactionDetail) IsParty(userID int) bool {
+	return userID != 0 && (d.Sender.UserID == userID || d.Receiver.UserID == userID)
+}
+
+// ReceiptVerification is what public can see of a shared receipt, name is masked and phone isn't shown
+type ReceiptVerification struct {
+	Valid           bool      `json:"valid"`
+	ReferenceNumber string    `json:"reference_number"`
+	Kind            string    `json:"kind"`
+	Amount          int       `json:"amount"`
+	Fee             int       `json:"fee"`
+	Total           int       `json:"total"`
+	Status          string    `json:"status"`
+	Sender          string    `json:"sender"`
+	Receiver        string    `json:"receiver"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
 type DeleteTransactionRequest struct {
 	TransactionID int `json:"transaction_id" uri:"id" binding:"required"`
 }
+
+// HistoryTypes map type filter of history to transaction_type of transactions view
+var HistoryTypes = map[string]string{
+	"sent":       "Send",
+	"received":   "Transfer",
+	"topup":      "Topup",
+	"withdrawal": "Withdrawal",
+}
+
+// HistoryFilter is query of history endpoint, zero value return every transaction
+type HistoryFilter struct {
+	// Kind is set by endpoint (transfer, topup), empty is every kind
+	Kind string `form:"-"`
+	// From & To is date of created_at, both inclusive
+	From *time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To   *time.Time `form:"to" time_format:"2006-01-02" time_utc:"1"`
+	// Types is key of HistoryTypes, repeat the query for many type (type=sent&type=topup)
+	Types        []string `form:"type" binding:"dive,oneof=sent received topup withdrawal"`
+	Statuses     []string `form:"status" binding:"dive,oneof=success pending failed"`
+	MinAmount    int      `form:"min_amount" binding:"omitempty,min=1"`
+	MaxAmount    int      `form:"max_amount" binding:"omitempty,min=1"`
+	Counterparty int      `form:"counterparty" binding:"omitempty,min=1"`
+	// Search match notes and counterparty name (user or payment method), case insensitive
+	Search string `form:"q" binding:"max=100"`
+}
+
+func (f HistoryFilter) Validate() error {
+	if f.From != nil && f.To != nil && f.From.After(*f.To) {
+		return errors.New("from must not be after to")
+	}
+	if f.MaxAmount > 0 && f.MinAmount > f.MaxAmount {
+		return errors.New("min_amount must not be greater than max_amount")
+	}
+	return nil
+}
+
+// IsZero report whether no filter is set, kind isn't a filter
+func (f HistoryFilter) IsZero() bool {
+	return f.From == nil && f.To == nil && len(f.Types) == 0 && len(f.Statuses) == 0 &&
+		f.MinAmount == 0 && f.MaxAmount == 0 && f.Counterparty == 0 && strings.TrimSpace(f.Search) == ""
+}
+
+// StatementQuery is query of statement endpoint
+type StatementQuery struct {
+	// From & To is date of created_at, both inclusive
+	From   time.Time `form:"from" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+	To     time.Time `form:"to" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+	Format string    `form:"format" binding:"omitempty,oneof=csv pdf"`
+	// Email send statement as attachment to email of user instead of downloading it
+	Email bool `form:"email"`
+}
+
+// MaxStatementMonths is longest period of statement built in memory: PDF and email attachment.
+// CSV download is streamed so its period isn't limited
+const MaxStatementMonths = 12
+
+func (q StatementQuery) Validate() error {
+	if q.From.After(q.To) {
+		return errors.New("from must not be after to")
+	}
+	if (q.Format != "csv" || q.Email) && !q.To.Before(q.From.AddDate(0, MaxStatementMonths, 0)) {
+		return fmt.Errorf("period of pdf or emailed statement must not be longer than %d months", MaxStatementMonths)
+	}
+	return nil
+}
+
+// StatementEntry is one success transaction on statement, Debit is money out of wallet and Credit is money in
+type StatementEntry struct {
+	ID   int    `db:"id"`
+	Kind string `db:"kind"`
+	Type string `db:"transaction_type"`
+	// other user of transfer, payment method of topup
+	Counterparty string    `db:"counterparty"`
+	Notes        string    `db:"notes"`
+	Debit        int       `db:"debit"`
+	Credit       int       `db:"credit"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+// TrashItem is transaction hidden by user which can still be restored until PurgeAt
+type TrashItem struct {
+	TransactionHistory
+	DeletedAt time.Time `json:"deleted_at" db:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// TransactionRef point to one transfer or topup
+type TransactionRef struct {
+	Kind string `json:"kind" binding:"required,oneof=transfer topup" example:"transfer"`
+	ID   int    `json:"id" binding:"required,min=1" example:"12"`
+}
+
+// BulkTransactionRequest is body of bulk hide & restore
+type BulkTransactionRequest struct {
+	Items []TransactionRef `json:"items" binding:"required,min=1,max=100,dive"`
+}
+
+// BulkTransactionResult tell which item is changed, item which isn't user's or is already in that state is skipped
+type BulkTransactionResult struct {
+	Updated []TransactionRef `json:"updated"`
+	Skipped []TransactionRef `json:"skipped"`
+}
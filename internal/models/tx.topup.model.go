@@ -16,13 +16,15 @@ type PaymentMethod struct {
 }
 
 type TopUp struct {
-	ID        int         `db:"id" json:"id"`
-	Amount    int         `db:"amount" json:"amount"`
-	Tax       int         `db:"tax" json:"tax"`
-	PaymentID int         `db:"payment_id" json:"payment_id"`
-	Status    TopUpStatus `db:"topup_status" json:"topup_status"`
-	CreatedAt time.Time   `db:"created_at" json:"created_at"`
-	UpdatedAt *time.Time  `db:"updated_at" json:"updated_at,omitempty"`
+	ID        int `db:"id" json:"id"`
+	Amount    int `db:"amount" json:"amount"`
+	Tax       int `db:"tax" json:"tax"`
+	PaymentID int `db:"payment_id" json:"payment_id"`
+	// name of payment method, filled after topup is created
+	PaymentMethod string      `db:"payment_method" json:"payment_method,omitempty"`
+	Status        TopUpStatus `db:"topup_status" json:"topup_status"`
+	CreatedAt     time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt     *time.Time  `db:"updated_at" json:"updated_at,omitempty"`
 }
 
 type TopUpRequest struct {
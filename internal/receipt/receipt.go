@@ -0,0 +1,98 @@
+// Package receipt number, sign and render receipt of transaction,
+// signed code let anyone check a shared receipt without login
+package receipt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+)
+
+// prefix of reference number for every transaction kind
+var prefixes = map[string]string{
+	"transfer": "TRF",
+	"topup":    "TOP",
+}
+
+var ErrInvalidCode = errors.New("invalid receipt code")
+
+// signature is short enough to be typed by hand, base32 has no ambiguous case
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Reference is reference number printed on receipt, ex: TRF-20260101-00000123
+func Reference(kind string, id int, createdAt time.Time) string {
+	return fmt.Sprintf("%s-%s-%08d", prefixes[kind], createdAt.Format("20060102"), id)
+}
+
+// ParseCode return kind & id of transaction of code, signature is checked by Verify after the transaction is loaded
+func ParseCode(code string) (kind string, id int, err error) {
+	parts := strings.Split(strings.ToUpper(strings.TrimSpace(code)), "-")
+	if len(parts) != 4 {
+		return "", 0, ErrInvalidCode
+	}
+	for k, prefix := range prefixes {
+		if prefix == parts[0] {
+			kind = k
+		}
+	}
+	id, err = strconv.Atoi(parts[2])
+	if kind == "" || err != nil || id <= 0 {
+		return "", 0, ErrInvalidCode
+	}
+	return kind, id, nil
+}
+
+type Signer struct {
+	key []byte
+}
+
+// NewSigner derive signing key from secret, so secret shared with other purpose (ex: JWT) never sign the same way
+func NewSigner(secret string) *Signer {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("belalai receipt"))
+	return &Signer{key: mac.Sum(nil)}
+}
+
+// Code is reference number with its signature, ex: TRF-20260101-00000123-K7Q2M4XW9D3FJ8HA
+func (s *Signer) Code(d *models.TransactionDetail) string {
+	ref := Reference(d.Kind, d.ID, d.CreatedAt)
+	return ref + "-" + s.sign(ref, d)
+}
+
+// Verify report whether code is the code of d, code is case insensitive
+func (s *Signer) Verify(code string, d *models.TransactionDetail) bool {
+	return hmac.Equal([]byte(strings.ToUpper(strings.TrimSpace(code))), []byte(s.Code(d)))
+}
+
+// signature cover what receipt prove: which transaction, how much and when,
+// status isn't signed because pending transaction may become success later
+func (s *Signer) sign(ref string, d *models.TransactionDetail) string {
+	mac := hmac.New(sha256.New, s.key)
+	fmt.Fprintf(mac, "%s|%d|%d|%d", ref, d.Amount, d.Fee, d.CreatedAt.UnixMicro())
+	return encoding.EncodeToString(mac.Sum(nil)[:10])
+}
+
+// MaskName hide most of every word, ex: Budi Santoso -> B*** S******
+func MaskName(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		r := []rune(word)
+		words[i] = string(r[0]) + strings.Repeat("*", len(r)-1)
+	}
+	return strings.Join(words, " ")
+}
+
+// MaskPhone keep prefix & the last 3 digit, ex: 081234567890 -> 0812*****890
+func MaskPhone(phone string) string {
+	if len(phone) <= 7 {
+		return phone
+	}
+	return phone[:4] + strings.Repeat("*", len(phone)-7) + phone[len(phone)-3:]
+}
@@ -0,0 +1,227 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/i18n"
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Document is content of receipt, every text is already translated
+type Document struct {
+	AppName string
+	Title   string
+	Rows    []Row
+	Code    string
+	// Footer tell how to verify the receipt
+	Footer string
+	// QR is content of QR code, verify link or the code itself
+	QR string
+}
+
+type Row struct {
+	Label string
+	Value string
+}
+
+// New build receipt of transaction in lang, QR code link to verifyURL/CODE (only the code when verifyURL is empty)
+func New(d *models.TransactionDetail, lang, appName, verifyURL string) Document {
+	t := func(key string, args ...any) string { return i18n.Translate(lang, key, args...) }
+	party := func(p models.TransactionParty) string {
+		if p.Phone == "" {
+			return p.Name
+		}
+		return fmt.Sprintf("%s (%s)", p.Name, MaskPhone(p.Phone))
+	}
+
+	doc := Document{
+		AppName: appName,
+		Title:   t("receipt.title"),
+		Code:    d.ReceiptCode,
+		QR:      d.ReceiptCode,
+		Rows: []Row{
+			{t("receipt.reference"), d.ReferenceNumber},
+			{t("receipt.date"), d.CreatedAt.Format("02/01/2006 15:04")},
+			{t("receipt.type"), t("receipt.type." + d.Kind)},
+			{t("receipt.status"), t("receipt.status." + d.Status)},
+			{t("receipt.sender"), party(d.Sender)},
+			{t("receipt.receiver"), party(d.Receiver)},
+			{t("receipt.amount"), utils.FormatRupiah(d.Amount)},
+			{t("receipt.fee"), utils.FormatRupiah(d.Fee)},
+			{t("receipt.total"), utils.FormatRupiah(d.Total)},
+		},
+	}
+	if d.Notes != "" {
+		doc.Rows = append(doc.Rows, Row{t("receipt.notes"), d.Notes})
+	}
+	if verifyURL != "" {
+		doc.QR = strings.TrimRight(verifyURL, "/") + "/" + d.ReceiptCode
+		doc.Footer = t("receipt.verify", doc.QR)
+	} else {
+		doc.Footer = t("receipt.verify", d.ReceiptCode)
+	}
+	return doc
+}
+
+// PDF render receipt on one small page with built-in font, so no font file is needed
+func (d Document) PDF(w io.Writer) error {
+	const width, margin, rowHeight, qrSize = 105.0, 10.0, 7.0, 32.0
+	inner := width - 2*margin
+	height := 2*margin + 22 + float64(len(d.Rows))*rowHeight + qrSize + 28
+
+	qr, err := qrcode.Encode(d.QR, qrcode.Medium, 256)
+	if err != nil {
+		return err
+	}
+
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{UnitStr: "mm", Size: gofpdf.SizeType{Wd: width, Ht: height}})
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetTitle(d.Title, true)
+	pdf.SetCreator(d.AppName, true)
+	pdf.AddPage()
+	// built-in font is cp1252, text is utf-8
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	separator := func() {
+		pdf.SetDrawColor(200, 200, 200)
+		pdf.Line(margin, pdf.GetY(), width-margin, pdf.GetY())
+		pdf.Ln(2)
+	}
+
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(inner, 8, tr(d.AppName), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(inner, 6, tr(d.Title), "", 1, "C", false, 0, "")
+	pdf.Ln(4)
+	separator()
+
+	labelWidth := inner * 0.35
+	for _, row := range d.Rows {
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.SetTextColor(110, 110, 110)
+		pdf.CellFormat(labelWidth, rowHeight, tr(row.Label), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetTextColor(0, 0, 0)
+		value := tr(row.Value)
+		// long value (ex: notes) is cut, receipt is a single page
+		for pdf.GetStringWidth(value) > inner-labelWidth && len(value) > 3 {
+			value = strings.TrimSuffix(value, "...")
+			value = value[:len(value)-1] + "..."
+		}
+		pdf.CellFormat(inner-labelWidth, rowHeight, value, "", 1, "R", false, 0, "")
+	}
+	pdf.Ln(2)
+	separator()
+
+	pdf.RegisterImageOptionsReader("qr", gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qr))
+	pdf.ImageOptions("qr", (width-qrSize)/2, pdf.GetY(), qrSize, qrSize, false, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	pdf.SetY(pdf.GetY() + qrSize + 2)
+	pdf.SetFont("Courier", "B", 9)
+	pdf.CellFormat(inner, 5, d.Code, "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 7)
+	pdf.SetTextColor(110, 110, 110)
+	pdf.MultiCell(inner, 3.5, tr(d.Footer), "", "C", false)
+
+	return pdf.Output(w)
+}
+
+// PNG render receipt with basic bitmap font (ASCII only), drawn small then scaled up so it stay sharp
+func (d Document) PNG(w io.Writer) error {
+	const width, margin, lineHeight, qrSize, scale = 300, 12, 18, 120, 2
+	face := basicfont.Face7x13
+	charWidth := face.Advance
+	maxChars := (width - 2*margin) / charWidth
+	footer := wrap(d.Footer, maxChars)
+	height := 2*margin + 3*lineHeight + len(d.Rows)*lineHeight + lineHeight + qrSize + lineHeight + len(footer)*lineHeight
+
+	qr, err := qrcode.New(d.QR, qrcode.Medium)
+	if err != nil {
+		return err
+	}
+	qr.DisableBorder = true
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	black, gray := color.Black, color.RGBA{110, 110, 110, 255}
+	text := func(s string, x, y int, c color.Color) {
+		drawer := font.Drawer{Dst: img, Src: image.NewUniform(c), Face: face, Dot: fixed.P(x, y)}
+		drawer.DrawString(s)
+	}
+	center := func(s string, y int, c color.Color) {
+		text(s, (width-len(s)*charWidth)/2, y, c)
+	}
+	separator := func(y int) {
+		draw.Draw(img, image.Rect(margin, y, width-margin, y+1), image.NewUniform(color.RGBA{200, 200, 200, 255}), image.Point{}, draw.Src)
+	}
+
+	y := margin + lineHeight
+	center(d.AppName, y, black)
+	y += lineHeight
+	center(d.Title, y, gray)
+	y += lineHeight / 2
+	separator(y)
+	y += lineHeight
+	for _, row := range d.Rows {
+		text(row.Label, margin, y, gray)
+		value := []rune(row.Value)
+		if room := maxChars - len([]rune(row.Label)) - 1; len(value) > room {
+			value = append(value[:max(room-3, 0)], []rune("...")...)
+		}
+		text(string(value), width-margin-len(value)*charWidth, y, black)
+		y += lineHeight
+	}
+	separator(y - lineHeight/2)
+	y += lineHeight / 2
+
+	draw.Draw(img, image.Rect((width-qrSize)/2, y, (width+qrSize)/2, y+qrSize), qr.Image(qrSize), image.Point{}, draw.Src)
+	y += qrSize + lineHeight
+	center(d.Code, y, black)
+	for _, line := range footer {
+		y += lineHeight
+		center(line, y, gray)
+	}
+
+	out := image.NewRGBA(image.Rect(0, 0, width*scale, height*scale))
+	xdraw.NearestNeighbor.Scale(out, out.Bounds(), img, img.Bounds(), draw.Src, nil)
+	return png.Encode(w, out)
+}
+
+// wrap split text into line of at most n character, word longer than n is cut
+func wrap(text string, n int) []string {
+	var lines []string
+	var line string
+	for _, word := range strings.Fields(text) {
+		for len(word) > n {
+			if line != "" {
+				lines, line = append(lines, line), ""
+			}
+			lines, word = append(lines, word[:n]), word[n:]
+		}
+		switch {
+		case line == "":
+			line = word
+		case len(line)+1+len(word) <= n:
+			line += " " + word
+		default:
+			lines, line = append(lines, line), word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -3,12 +3,13 @@ package repository
 import (
 	"context"
 	"errors"
-	"log"
 	"time"
 
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
 	"github.com/Belalai-E-Wallet-Backend/internal/models"
 	"github.com/Belalai-E-Wallet-Backend/internal/utils"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
@@ -26,19 +27,23 @@ func NewAuthRepository(db *pgxpool.Pool, rdb *redis.Client) *AuthRepository {
 }
 
 func (ar *AuthRepository) GetEmail(c context.Context, email string) (*models.User, error) {
-	sql := "select id, email, password, pin, created_at, updated_at from users where email = $1"
+	sql := `select u.id, u.email, u.password, u.pin, COALESCE(p.language, ''), u.created_at, u.updated_at
+	from users u left join profile p on p.user_id = u.id where u.email = $1`
 
 	var user models.User
-	if err := ar.db.QueryRow(c, sql, email).Scan(&user.ID, &user.Email, &user.Password, &user.Pin, &user.CreatedAt, &user.UpdatedAt); err != nil {
+	if err := ar.db.QueryRow(c, sql, email).Scan(&user.ID, &user.Email, &user.Password, &user.Pin, &user.Language, &user.CreatedAt, &user.UpdatedAt); err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
-		log.Println("Internal Server Error.\nCause: ", err.Error())
+		logger.FromContext(c).Error("Internal Server Error", "err", err)
 		return nil, err
 	}
 	return &user, nil
 }
 
+// postgres error code of unique constraint violation
+const uniqueViolation = "23505"
+
 func (ar *AuthRepository) CreateAccount(c context.Context, user *models.User) error {
 	tx, err := ar.db.Begin(c)
 	if err != nil {
@@ -48,32 +53,37 @@ func (ar *AuthRepository) CreateAccount(c context.Context, user *models.User) er
 
 	qInsertIntoUser := "insert into users (email, password, created_at) values ($1, $2, now()) returning id"
 	if err = tx.QueryRow(c, qInsertIntoUser, user.Email, user.Password).Scan(&user.ID); err != nil {
-		log.Println(err.Error())
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return ErrEmailRegistered
+		}
+		logger.FromContext(c).Error("CreateAccount failed", "err", err)
 		return err
 	}
 
+	// language isn't set, message follow Accept-Language until user pick one on profile
 	qInsertIntoProfile := "insert into profile (user_id, created_at) values ($1, now())"
 	_, err = tx.Exec(c, qInsertIntoProfile, user.ID)
 	if err != nil {
-		log.Println(err.Error())
+		logger.FromContext(c).Error("CreateAccount failed", "err", err)
 		return err
 	}
 
 	qInsertIntoWallet := "insert into wallets (user_id, created_at) values ($1, now())"
 	_, err = tx.Exec(c, qInsertIntoWallet, user.ID)
 	if err != nil {
-		log.Println(err.Error())
+		logger.FromContext(c).Error("CreateAccount failed", "err", err)
 		return err
 	}
 
 	if err := tx.Commit(c); err != nil {
-		log.Println(err.Error())
+		logger.FromContext(c).Error("CreateAccount failed", "err", err)
 		return err
 	}
 
 	// Handle caching after successful registration
 	if err := utils.HandleRegistrationCache(c, *ar.rdb, int64(user.ID), user); err != nil {
-		log.Println("Cache operation warning:", err)
+		logger.FromContext(c).Warn("Cache operation warning", "err", err)
 		// Don't return error - registration was successful
 	}
 
@@ -87,7 +97,7 @@ func (ar *AuthRepository) VerifyPassword(c context.Context, userId int) (string,
 
 	err := ar.db.QueryRow(c, sql, userId).Scan(&hashedPassword)
 	if err != nil {
-		log.Println(err.Error())
+		logger.FromContext(c).Error("VerifyPassword failed", "err", err)
 		return "", err
 	}
 
@@ -103,15 +113,21 @@ func (ar *AuthRepository) UpdatePassword(c context.Context, userId int, hashedPa
 
 // VerifyPIN: get hashed pin by userId
 func (ar *AuthRepository) VerifyPIN(c context.Context, userId int) (string, error) {
-	var hashedPIN string
+	var hashedPIN *string
 	sql := `SELECT pin FROM users WHERE id = $1`
 
 	err := ar.db.QueryRow(c, sql, userId).Scan(&hashedPIN)
 	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", ErrUserNotFound
+		}
 		return "", err
 	}
+	if hashedPIN == nil {
+		return "", ErrPINNotSet
+	}
 
-	return hashedPIN, nil
+	return *hashedPIN, nil
 }
 
 // UpdatePIN: update user pin
@@ -125,13 +141,18 @@ func (ar *AuthRepository) UpdatePIN(c context.Context, userId int, hashedPin str
 func (ar *AuthRepository) BlacklistToken(c context.Context, token string) error {
 	// use utils.BlackListTokenRedish for logout token
 	if err := utils.BlackListTokenRedish(c, *ar.rdb, token); err != nil {
-		log.Println("failed blacklist token, ", err)
+		logger.FromContext(c).Error("failed blacklist token", "err", err)
 		return err
 	}
 	// is success return nil
 	return nil
 }
 
+// SaveLanguage: keep language preference of user on redis so VerifyToken doesn't query profile every request
+func (ar *AuthRepository) SaveLanguage(c context.Context, userId int, lang string) error {
+	return utils.SaveUserLanguage(c, *ar.rdb, userId, lang)
+}
+
 func (ar *AuthRepository) SaveResetToken(c context.Context, key, value string, ttl time.Duration) error {
 	return ar.rdb.Set(c, key, value, ttl).Err()
 }
@@ -144,15 +165,11 @@ func (ar *AuthRepository) DeleteResetToken(c context.Context, key string) error
 	return ar.rdb.Del(c, key).Err()
 }
 
-func (ar *AuthRepository) GetEmailForSMPT(c context.Context, email string) (*models.User, error) {
-	query := "select id, email from users where email = $1"
+func (ar *AuthRepository) GetEmailForSMPT(c context.Context, email string) (*models.MailRecipient, error) {
+	return getMailRecipient(c, ar.db, "u.email = $1", email)
+}
 
-	var user models.User
-	if err := ar.db.QueryRow(c, query, email).Scan(&user.ID, &user.Email); err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, errors.New("user not found")
-		}
-		return nil, err
-	}
-	return &user, nil
+// GetMailRecipient: get email, name and language of user for notification email
+func (ar *AuthRepository) GetMailRecipient(c context.Context, userId int) (*models.MailRecipient, error) {
+	return getMailRecipient(c, ar.db, "u.id = $1", userId)
 }
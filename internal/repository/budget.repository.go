@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+type BudgetRepository struct {
+	db  *pgxpool.Pool
+	rdb *redis.Client
+}
+
+func NewBudgetRepository(db *pgxpool.Pool, rdb *redis.Client) *BudgetRepository {
+	return &BudgetRepository{db: db, rdb: rdb}
+}
+
+// GetBudgets - every budget of user, overall budget first then by category
+func (br *BudgetRepository) GetBudgets(c context.Context, userID int) ([]models.Budget, error) {
+	sql := `SELECT id, category, amount, created_at, updated_at FROM budget WHERE user_id = $1 ORDER BY category`
+	rows, err := br.db.Query(c, sql, userID)
+	if err != nil {
+		logger.FromContext(c).Error("Error querying budgets", "err", err)
+		return nil, err
+	}
+	budgets := []models.Budget{}
+	var b models.Budget
+	_, err = pgx.ForEachRow(rows, []any{&b.ID, &b.Category, &b.Amount, &b.CreatedAt, &b.UpdatedAt}, func() error {
+		budgets = append(budgets, b)
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(c).Error("Error scanning budgets", "err", err)
+		return nil, err
+	}
+	return budgets, nil
+}
+
+// SaveBudget - create budget of category or replace its amount, the rest of budget is filled back.
+// alert of changed budget is forgotten, so threshold of the new amount is notified again
+func (br *BudgetRepository) SaveBudget(c context.Context, userID int, budget *models.Budget) error {
+	tx, err := br.db.Begin(c)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(c)
+
+	sql := `INSERT INTO budget (user_id, category, amount) VALUES ($1, $2, $3)
+	ON CONFLICT (user_id, category) DO UPDATE SET amount = EXCLUDED.amount, updated_at = CURRENT_TIMESTAMP
+	RETURNING id, created_at, updated_at`
+	if err := tx.QueryRow(c, sql, userID, budget.Category, budget.Amount).Scan(&budget.ID, &budget.CreatedAt, &budget.UpdatedAt); err != nil {
+		logger.FromContext(c).Error("Error saving budget", "err", err)
+		return err
+	}
+	if _, err := tx.Exec(c, `DELETE FROM budget_alert WHERE budget_id = $1`, budget.ID); err != nil {
+		logger.FromContext(c).Error("Error resetting budget alert", "err", err)
+		return err
+	}
+	return tx.Commit(c)
+}
+
+// DeleteBudget - remove budget of user, budget of other user is ErrBudgetNotFound
+func (br *BudgetRepository) DeleteBudget(c context.Context, userID, budgetID int) error {
+	tag, err := br.db.Exec(c, `DELETE FROM budget WHERE id = $1 AND user_id = $2`, budgetID, userID)
+	if err != nil {
+		logger.FromContext(c).Error("Error deleting budget", "err", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrBudgetNotFound
+	}
+	return nil
+}
+
+// MarkAlert - record threshold of budget on month (first day), false when it is already recorded.
+// concurrent transfer crossing the same threshold only get one true
+func (br *BudgetRepository) MarkAlert(c context.Context, budgetID int, month time.Time, threshold int) (bool, error) {
+	sql := `INSERT INTO budget_alert (budget_id, month, threshold) VALUES ($1, $2::DATE, $3) ON CONFLICT DO NOTHING`
+	tag, err := br.db.Exec(c, sql, budgetID, month.Format(time.DateOnly), threshold)
+	if err != nil {
+		logger.FromContext(c).Error("Error marking budget alert", "err", err)
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// PublishAlert - push alert to connected device of user
+func (br *BudgetRepository) PublishAlert(c context.Context, userID int, alert models.BudgetAlert) error {
+	return utils.PublishUserEvent(c, *br.rdb, userID, models.EventBudgetAlert, alert)
+}
+
+func (br *BudgetRepository) GetMailRecipient(c context.Context, userID int) (*models.MailRecipient, error) {
+	return getMailRecipient(c, br.db, "u.id = $1", userID)
+}
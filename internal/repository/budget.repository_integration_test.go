@@ -0,0 +1,93 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+)
+
+func TestBudget(t *testing.T) {
+	pool, rdb := setup(t)
+	f := fixture{t: t, db: pool}
+	budi, _ := f.user("budi@mail.com", "Budi", "0811", 0)
+	ani, _ := f.user("ani@mail.com", "Ani", "0812", 0)
+
+	br := repository.NewBudgetRepository(pool, rdb)
+	c := context.Background()
+	month := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	overall := models.Budget{Amount: 100000}
+	food := models.Budget{Category: "food", Amount: 10000}
+	for _, b := range []*models.Budget{&food, &overall} {
+		if err := br.SaveBudget(c, budi, b); err != nil {
+			t.Fatal(err)
+		}
+		if b.ID == 0 || b.CreatedAt.IsZero() || b.UpdatedAt != nil {
+			t.Errorf("created budget = %+v", b)
+		}
+	}
+
+	t.Run("alert once a month", func(t *testing.T) {
+		for _, tt := range []struct {
+			month     time.Time
+			threshold int
+			want      bool
+		}{
+			{month, 80, true},
+			{month, 80, false},
+			{month, 100, true},
+			{month.AddDate(0, 1, 0), 80, true},
+		} {
+			marked, err := br.MarkAlert(c, food.ID, tt.month, tt.threshold)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if marked != tt.want {
+				t.Errorf("mark %s %d%% = %v, want %v", tt.month.Format("2006-01"), tt.threshold, marked, tt.want)
+			}
+		}
+	})
+
+	t.Run("update reset alert", func(t *testing.T) {
+		raised := models.Budget{Category: "food", Amount: 20000}
+		if err := br.SaveBudget(c, budi, &raised); err != nil {
+			t.Fatal(err)
+		}
+		if raised.ID != food.ID || raised.Amount != 20000 || raised.UpdatedAt == nil {
+			t.Errorf("updated budget = %+v, want budget %d with new amount", raised, food.ID)
+		}
+		if marked, err := br.MarkAlert(c, food.ID, month, 80); err != nil || !marked {
+			t.Errorf("mark after update = %v, %v, want true", marked, err)
+		}
+
+		budgets, err := br.GetBudgets(c, budi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(budgets) != 2 || budgets[0].ID != overall.ID || budgets[1].Amount != 20000 {
+			t.Errorf("budgets = %+v, want overall then food", budgets)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := br.DeleteBudget(c, ani, food.ID); !errors.Is(err, repository.ErrBudgetNotFound) {
+			t.Errorf("delete by other user = %v, want ErrBudgetNotFound", err)
+		}
+		if err := br.DeleteBudget(c, budi, food.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := br.DeleteBudget(c, budi, food.ID); !errors.Is(err, repository.ErrBudgetNotFound) {
+			t.Errorf("delete twice = %v, want ErrBudgetNotFound", err)
+		}
+		budgets, err := br.GetBudgets(c, budi)
+		if err != nil || len(budgets) != 1 || budgets[0].ID != overall.ID {
+			t.Errorf("budgets = %+v, %v, want overall only", budgets, err)
+		}
+	})
+}
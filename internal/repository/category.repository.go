@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CategoryRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCategoryRepository(db *pgxpool.Pool) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// GetRules - rule made by user, in the order they are tried (oldest first), default rule isn't included
+func (cr *CategoryRepository) GetRules(c context.Context, userID int) ([]models.CategoryRule, error) {
+	sql := `SELECT id, field, keyword, category, created_at FROM category_rule WHERE user_id = $1 ORDER BY id`
+	rows, err := cr.db.Query(c, sql, userID)
+	if err != nil {
+		logger.FromContext(c).Error("Error querying category rules", "err", err)
+		return nil, err
+	}
+	rules := []models.CategoryRule{}
+	var rule models.CategoryRule
+	_, err = pgx.ForEachRow(rows, []any{&rule.ID, &rule.Field, &rule.Keyword, &rule.Category, &rule.CreatedAt}, func() error {
+		rules = append(rules, rule)
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(c).Error("Error scanning category rules", "err", err)
+		return nil, err
+	}
+	return rules, nil
+}
+
+// CreateRule - save rule of user, id & created_at is set on rule
+func (cr *CategoryRepository) CreateRule(c context.Context, userID int, rule *models.CategoryRule) error {
+	sql := `INSERT INTO category_rule (user_id, field, keyword, category) VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+	if err := cr.db.QueryRow(c, sql, userID, rule.Field, rule.Keyword, rule.Category).Scan(&rule.ID, &rule.CreatedAt); err != nil {
+		logger.FromContext(c).Error("Error creating category rule", "err", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteRule - remove rule of user, rule of other user and default rule is ErrCategoryRuleNotFound
+func (cr *CategoryRepository) DeleteRule(c context.Context, userID, ruleID int) error {
+	tag, err := cr.db.Exec(c, `DELETE FROM category_rule WHERE id = $1 AND user_id = $2`, ruleID, userID)
+	if err != nil {
+		logger.FromContext(c).Error("Error deleting category rule", "err", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrCategoryRuleNotFound
+	}
+	return nil
+}
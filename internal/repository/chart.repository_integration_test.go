@@ -0,0 +1,259 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+)
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+func TestGetChartData(t *testing.T) {
+	pool, _ := setup(t)
+	f := fixture{t: t, db: pool}
+	today := f.today()
+
+	budiUser, budi := f.user("budi@mail.com", "Budi", "0811", 0)
+	_, ani := f.user("ani@mail.com", "Ani", "0812", 0)
+	_, cici := f.user("cici@mail.com", "Cici", "0813", 0)
+	dodi, _ := f.user("dodi@mail.com", "Dodi", "0814", 0)
+	bri := f.paymentMethod("BRI")
+
+	f.topup(budi, bri, 50000, "success", today)
+	f.topup(budi, bri, 7000, "failed", today)                        // failed topup isn't income
+	f.transfer(ani, budi, 10000, "success", today.AddDate(0, 0, -2)) // income 2 days ago
+	f.transfer(budi, ani, 4000, "success", today.AddDate(0, 0, -2))  // expense 2 days ago
+	f.transfer(budi, ani, 3000, "pending", today.AddDate(0, 0, -1))  // pending transfer isn't counted
+	f.transfer(budi, ani, 2000, "success", today.AddDate(0, 0, -20)) // outside 7 days, inside 30 days
+	f.transfer(cici, ani, 9000, "success", today)                    // other user
+	f.transfer(ani, budi, 8000, "success", today.AddDate(0, -2, 0))  // 2 month ago
+	f.transfer(ani, budi, 6000, "success", today.AddDate(-1, -1, 0)) // outside 12 months
+
+	cr := repository.NewChartRepository(pool)
+	c := context.Background()
+	preset := func(t *testing.T, name string) models.ChartRange {
+		t.Helper()
+		r, err := models.ChartPreset(name, today)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	t.Run("seven_days", func(t *testing.T) {
+		data, err := cr.GetChartData(c, budiUser, preset(t, "seven_days"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data.Labels) != 7 || len(data.IncomeData) != 7 || len(data.ExpenseData) != 7 {
+			t.Fatalf("got %d labels, %d income, %d expense, want 7 each", len(data.Labels), len(data.IncomeData), len(data.ExpenseData))
+		}
+		if want := today.Format(time.DateOnly); data.Labels[6] != want {
+			t.Errorf("last label = %s, want %s", data.Labels[6], want)
+		}
+		if want := today.AddDate(0, 0, -6).Format(time.DateOnly); data.Labels[0] != want {
+			t.Errorf("first label = %s, want %s", data.Labels[0], want)
+		}
+		if data.IncomeData[6] != 50000 || data.IncomeData[4] != 10000 || sum(data.IncomeData) != 60000 {
+			t.Errorf("income = %v, want 50000 today and 10000 two days ago", data.IncomeData)
+		}
+		if data.ExpenseData[4] != 4000 || sum(data.ExpenseData) != 4000 {
+			t.Errorf("expense = %v, want 4000 two days ago", data.ExpenseData)
+		}
+	})
+
+	t.Run("five_weeks", func(t *testing.T) {
+		data, err := cr.GetChartData(c, budiUser, preset(t, "five_weeks"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 30 days touch 5 or 6 weeks depending on weekday of today
+		if len(data.Labels) < 5 || len(data.Labels) > 6 {
+			t.Fatalf("got %d labels, want 5 or 6", len(data.Labels))
+		}
+		for _, label := range data.Labels {
+			day, err := time.Parse(time.DateOnly, label)
+			if err != nil || day.Weekday() != time.Monday {
+				t.Errorf("label %s isn't a monday", label)
+			}
+		}
+		if sum(data.IncomeData) != 60000 || sum(data.ExpenseData) != 6000 {
+			t.Errorf("income %v expense %v, want total 60000 and 6000", data.IncomeData, data.ExpenseData)
+		}
+	})
+
+	t.Run("twelve_months", func(t *testing.T) {
+		data, err := cr.GetChartData(c, budiUser, preset(t, "twelve_months"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data.Labels) != 12 {
+			t.Fatalf("got %d labels, want 12", len(data.Labels))
+		}
+		if want := today.Format("2006-01"); data.Labels[11] != want {
+			t.Errorf("last label = %s, want %s", data.Labels[11], want)
+		}
+		if sum(data.IncomeData) != 68000 || sum(data.ExpenseData) != 6000 {
+			t.Errorf("income %v expense %v, want total 68000 and 6000", data.IncomeData, data.ExpenseData)
+		}
+	})
+
+	t.Run("user without transaction", func(t *testing.T) {
+		data, err := cr.GetChartData(c, dodi, preset(t, "seven_days"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data.Labels) != 7 || sum(data.IncomeData) != 0 || sum(data.ExpenseData) != 0 {
+			t.Errorf("got %+v, want 7 empty days", data)
+		}
+	})
+
+	t.Run("timezone of user", func(t *testing.T) {
+		jakarta, err := time.LoadLocation("Asia/Jakarta")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 20:00 UTC is 03:00 the next day in Jakarta
+		late := time.Date(2026, 1, 10, 20, 0, 0, 0, time.UTC)
+		f.transfer(ani, budi, 1500, "success", late)
+
+		r, err := models.NewChartRange(late, late.AddDate(0, 0, 1), "day", jakarta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := cr.GetChartData(c, budiUser, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data.Labels[1] != "2026-01-11" || data.IncomeData[1] != 1500 || sum(data.IncomeData) != 1500 {
+			t.Errorf("got %+v, want 1500 on 2026-01-11", data)
+		}
+	})
+}
+
+func TestCategoryInsight(t *testing.T) {
+	pool, _ := setup(t)
+	f := fixture{t: t, db: pool}
+	today := f.today()
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := models.NewChartRange(today, today, "month", jakarta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	budiUser, budi := f.user("budi@mail.com", "Budi", "0811", 0)
+	aniUser, ani := f.user("ani@mail.com", "Ani Wijaya", "0812", 0)
+	ciciUser, cici := f.user("cici@mail.com", "Cici", "0813", 0)
+	food := f.transfer(budi, ani, 5000, "success", today)
+	f.notes(food, "Makan siang")
+	f.notes(f.transfer(budi, ani, 2000, "success", today), "bayar NETFLIX")
+	f.transfer(budi, cici, 4000, "success", today)                   // notes of fixture match no rule
+	f.notes(f.transfer(budi, cici, 9000, "pending", today), "makan") // pending isn't counted
+	f.transfer(ani, budi, 7000, "success", today)                    // income of budi
+
+	cr := repository.NewChartRepository(pool)
+	tr := repository.NewTransactionRepository(pool)
+	rules := repository.NewCategoryRepository(pool)
+	c := context.Background()
+	totals := func(t *testing.T) map[string]int {
+		t.Helper()
+		got, err := cr.GetCategoryTotals(c, budiUser, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		amounts := map[string]int{}
+		for _, total := range got {
+			amounts[total.Category] = total.Amount
+		}
+		return amounts
+	}
+
+	t.Run("default rule", func(t *testing.T) {
+		got, err := cr.GetCategoryTotals(c, budiUser, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []models.CategoryTotal{{Category: "food", Amount: 5000, Count: 1}, {Category: "other", Amount: 4000, Count: 1}, {Category: "entertainment", Amount: 2000, Count: 1}}
+		if len(got) != len(want) {
+			t.Fatalf("totals = %+v, want %+v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("totals[%d] = %+v, want %+v", i, got[i], want[i])
+			}
+		}
+	})
+
+	t.Run("top counterparties", func(t *testing.T) {
+		got, err := cr.GetTopCounterparties(c, budiUser, r, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 || got[0].UserID != aniUser || got[0].Name != "Ani Wijaya" || got[0].Amount != 7000 || got[0].Count != 2 || got[1].UserID != ciciUser {
+			t.Errorf("counterparties = %+v", got)
+		}
+	})
+
+	t.Run("rule of user and picked category", func(t *testing.T) {
+		rule := models.CategoryRule{Field: "counterparty", Keyword: "wijaya", Category: "family"}
+		if err := rules.CreateRule(c, budiUser, &rule); err != nil {
+			t.Fatal(err)
+		}
+		if got := totals(t); got["family"] != 7000 || got["food"] != 0 {
+			t.Errorf("totals with rule = %v, want transfer to ani as family", got)
+		}
+
+		category, err := tr.SetCategory(c, budiUser, models.TransactionRef{Kind: "transfer", ID: food}, "food")
+		if err != nil || category != "food" {
+			t.Fatalf("set category = %q, %v", category, err)
+		}
+		// receiver doesn't see category picked by sender
+		history, err := tr.GetAllHistory(c, aniUser, models.HistoryFilter{}, 10, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, h := range history {
+			if h.Type == "Transfer" && h.Category != "income" {
+				t.Errorf("received transfer %d category = %q, want income", h.ID, h.Category)
+			}
+		}
+
+		if err := rules.DeleteRule(c, budiUser, rule.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := rules.DeleteRule(c, budiUser, rule.ID); !errors.Is(err, repository.ErrCategoryRuleNotFound) {
+			t.Errorf("delete twice = %v, want ErrCategoryRuleNotFound", err)
+		}
+		category, err = tr.SetCategory(c, budiUser, models.TransactionRef{Kind: "transfer", ID: food}, "")
+		if err != nil || category != "food" {
+			t.Errorf("reset category = %q, %v, want food from default rule", category, err)
+		}
+		if got := totals(t); got["entertainment"] != 2000 {
+			t.Errorf("totals after delete = %v", got)
+		}
+	})
+
+	t.Run("not owned", func(t *testing.T) {
+		if _, err := tr.SetCategory(c, ciciUser, models.TransactionRef{Kind: "transfer", ID: food}, "food"); !errors.Is(err, repository.ErrTransactionNotFound) {
+			t.Errorf("err = %v, want ErrTransactionNotFound", err)
+		}
+		if _, err := tr.SetCategory(c, budiUser, models.TransactionRef{Kind: "topup", ID: 1}, "food"); !errors.Is(err, repository.ErrTopUpNotFound) {
+			t.Errorf("err = %v, want ErrTopUpNotFound", err)
+		}
+	})
+}
@@ -2,10 +2,11 @@ package repository
 
 import (
 	"context"
-	"database/sql"
-	"fmt"
+	"time"
 
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
 	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -17,169 +18,98 @@ func NewChartRepository(db *pgxpool.Pool) *ChartRepository {
 	return &ChartRepository{db: db}
 }
 
-func (cr *ChartRepository) GetChartData(c context.Context, user_id int, filter string) (models.ChartData, error) {
+// chartSQL sum success transaction of user per bucket, only bucket with transaction is returned.
+// created_at is wall time of database session, so it is turned into an instant before moved to timezone of user
+const chartSQL = `SELECT
+		DATE_TRUNC($2, t.created_at::TIMESTAMPTZ AT TIME ZONE $3)::DATE AS bucket,
+		COALESCE(SUM(t.amount) FILTER (WHERE t.transaction_type <> 'Send'), 0) AS income,
+		COALESCE(SUM(t.amount) FILTER (WHERE t.transaction_type = 'Send'), 0) AS expense
+	FROM transactions t
+	WHERE t.user_id = $1 AND t.status = 'success'
+		AND t.created_at >= $4::TIMESTAMPTZ::TIMESTAMP AND t.created_at < $5::TIMESTAMPTZ::TIMESTAMP
+	GROUP BY 1`
 
-	sqlWeekData := `WITH date_series AS (
-			SELECT generate_series(
-					CURRENT_DATE - INTERVAL '6 days',
-					CURRENT_DATE,
-					'1 day'
-			)::DATE AS date
-	),
-	transactions AS (
-			SELECT id AS wallet_id FROM wallets WHERE user_id = $1
-	),
-	daily_data AS (
-			SELECT
-					t.created_at::DATE AS date, t.amount AS income, 0 AS expense
-			FROM topup t JOIN wallets_topup wt ON t.id = wt.topup_id
-			WHERE wt.wallets_id = (SELECT wallet_id FROM transactions) AND t.topup_status = 'success' AND t.created_at >= CURRENT_DATE - INTERVAL '6 days'
-			UNION ALL
-			SELECT
-					tr.created_at::DATE AS date, tr.amount AS income, 0 AS expense
-			FROM transfer tr
-			WHERE tr.receiver_wallet_id = (SELECT wallet_id FROM transactions) AND tr.transfer_status = 'success' AND tr.created_at >= CURRENT_DATE - INTERVAL '6 days'
-			UNION ALL
-			SELECT
-					tr.created_at::DATE AS date, 0 AS income, tr.amount AS expense
-			FROM transfer tr
-			WHERE tr.sender_wallet_id = (SELECT wallet_id FROM transactions) AND tr.transfer_status = 'success' AND tr.created_at >= CURRENT_DATE - INTERVAL '6 days'
-	),
-	aggregated_daily AS (
-			SELECT
-					ds.date AS label,
-					COALESCE(SUM(dd.income), 0) AS income,
-					COALESCE(SUM(dd.expense), 0) AS expense
-			FROM date_series ds
-			LEFT JOIN daily_data dd ON ds.date = dd.date
-			GROUP BY ds.date
-			ORDER BY ds.date
-	)
-	SELECT
-			array_agg(TO_CHAR(label, 'YYYY-MM-DD')) AS labels,
-			array_agg(income) AS income_data,
-			array_agg(expense) AS expense_data
-	FROM
-			aggregated_daily`
-
-	sqlMonthData := `WITH week_series AS (
-			SELECT DISTINCT DATE_TRUNC('week', generate_series(
-					CURRENT_DATE - INTERVAL '29 days',
-					CURRENT_DATE,
-					'1 day'
-			))::DATE AS week_start
-	),
-	transactions AS (
-			SELECT id AS wallet_id FROM wallets WHERE user_id = $1
-	),
-	weekly_data AS (
-			SELECT
-					DATE_TRUNC('week', t.created_at)::DATE AS week_start,
-					t.amount AS income,
-					0 AS expense
-			FROM topup t
-			JOIN wallets_topup wt ON t.id = wt.topup_id
-			WHERE wt.wallets_id = (SELECT wallet_id FROM transactions) AND t.topup_status = 'success' 
-			AND t.created_at >= CURRENT_DATE - INTERVAL '29 days' -- Batas 30 hari
-
-			UNION ALL
-
-			SELECT
-					DATE_TRUNC('week', tr.created_at)::DATE AS week_start,
-					tr.amount AS income,
-					0 AS expense
-			FROM transfer tr
-			WHERE tr.receiver_wallet_id = (SELECT wallet_id FROM transactions) AND tr.transfer_status = 'success' 
-			AND tr.created_at >= CURRENT_DATE - INTERVAL '29 days' -- Batas 30 hari
-
-			UNION ALL
+// GetChartData - income & expense of user per bucket of range, bucket without transaction is 0
+func (cr *ChartRepository) GetChartData(c context.Context, userId int, r models.ChartRange) (models.ChartData, error) {
+	data := r.Empty()
+	index := make(map[string]int, len(data.Labels))
+	for i, start := range r.Buckets() {
+		index[start.Format(time.DateOnly)] = i
+	}
 
-			SELECT
-					DATE_TRUNC('week', tr.created_at)::DATE AS week_start,
-					0 AS income,
-					tr.amount AS expense
-			FROM transfer tr
-			WHERE tr.sender_wallet_id = (SELECT wallet_id FROM transactions) AND tr.transfer_status = 'success' 
-			AND tr.created_at >= CURRENT_DATE - INTERVAL '29 days' -- Batas 30 hari
-	),
-	aggregated_weekly AS (
-			SELECT
-					ws.week_start AS label_date,
-					COALESCE(SUM(wd.income), 0) AS income,
-					COALESCE(SUM(wd.expense), 0) AS expense
-			FROM week_series ws
-			LEFT JOIN weekly_data wd ON ws.week_start = wd.week_start
-			GROUP BY ws.week_start
-			ORDER BY ws.week_start
-	)
-	SELECT
-			array_agg(TO_CHAR(label_date, 'YYYY-MM-DD')) AS labels,
-			array_agg(income) AS income_data,
-			array_agg(expense) AS expense_data
-	FROM
-			aggregated_weekly;`
+	rows, err := cr.db.Query(c, chartSQL, userId, r.Granularity, r.Location.String(), r.From, r.End())
+	if err != nil {
+		logger.FromContext(c).Error("Error querying chart", "err", err)
+		return models.ChartData{}, err
+	}
+	var bucket time.Time
+	var income, expense int
+	_, err = pgx.ForEachRow(rows, []any{&bucket, &income, &expense}, func() error {
+		if i, ok := index[bucket.Format(time.DateOnly)]; ok {
+			data.IncomeData[i] += income
+			data.ExpenseData[i] += expense
+		}
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(c).Error("Error scanning chart", "err", err)
+		return models.ChartData{}, err
+	}
+	return data, nil
+}
 
-	sqlYearData := `WITH month_series AS (
-			SELECT generate_series(
-					DATE_TRUNC('month', CURRENT_DATE) - INTERVAL '11 months',
-					DATE_TRUNC('month', CURRENT_DATE),
-					'1 month'
-			)::DATE AS month_start
-	),
-	transactions AS (
-			SELECT id AS wallet_id FROM wallets WHERE user_id = $1
-	),
-	monthly_data AS (
-			SELECT
-					DATE_TRUNC('month', t.created_at)::DATE AS month_start, t.amount AS income, 0 AS expense
-			FROM topup t JOIN wallets_topup wt ON t.id = wt.topup_id
-			WHERE wt.wallets_id = (SELECT wallet_id FROM transactions) AND t.topup_status = 'success' AND t.created_at >= DATE_TRUNC('month', CURRENT_DATE) - INTERVAL '11 months'
-			UNION ALL
-			SELECT
-					DATE_TRUNC('month', tr.created_at)::DATE AS month_start, tr.amount AS income, 0 AS expense
-			FROM transfer tr
-			WHERE tr.receiver_wallet_id = (SELECT wallet_id FROM transactions) AND tr.transfer_status = 'success' AND tr.created_at >= DATE_TRUNC('month', CURRENT_DATE) - INTERVAL '11 months'
-			UNION ALL
-			SELECT
-					DATE_TRUNC('month', tr.created_at)::DATE AS month_start, 0 AS income, tr.amount AS expense
-			FROM transfer tr
-			WHERE tr.sender_wallet_id = (SELECT wallet_id FROM transactions) AND tr.transfer_status = 'success' AND tr.created_at >= DATE_TRUNC('month', CURRENT_DATE) - INTERVAL '11 months'
-	),
-	aggregated_monthly AS (
-			SELECT
-					ms.month_start AS label,
-					COALESCE(SUM(md.income), 0) AS income,
-					COALESCE(SUM(md.expense), 0) AS expense
-			FROM month_series ms
-			LEFT JOIN monthly_data md ON ms.month_start = md.month_start
-			GROUP BY ms.month_start
-			ORDER BY ms.month_start
-	)
-	SELECT
-			array_agg(TO_CHAR(label, 'YYYY-MM')) AS labels,
-			array_agg(income) AS income_data,
-			array_agg(expense) AS expense_data
-	FROM
-			aggregated_monthly;`
+// spendingWhere is success sent transfer of user $1 between instant $2 & $3, like range of chartSQL
+const spendingWhere = `
+	WHERE t.user_id = $1 AND t.status = 'success' AND t.transaction_type = 'Send'
+		AND t.created_at >= $2::TIMESTAMPTZ::TIMESTAMP AND t.created_at < $3::TIMESTAMPTZ::TIMESTAMP`
 
-	var queryExec string
-	switch filter {
-	case "seven_days":
-		queryExec = sqlWeekData
-	case "five_weeks":
-		queryExec = sqlMonthData
-	case "twelve_months":
-		queryExec = sqlYearData
-	default:
+// GetCategoryTotals - spending of user in range per category, largest first
+func (cr *ChartRepository) GetCategoryTotals(c context.Context, userId int, r models.ChartRange) ([]models.CategoryTotal, error) {
+	sql := `SELECT ` + categoryColumn + ` AS category, SUM(t.amount), COUNT(*)
+	FROM transactions t
+	LEFT JOIN profile p ON p.user_id = t.counterparty_user_id` + spendingWhere + `
+	GROUP BY 1
+	ORDER BY 2 DESC, 1`
+	rows, err := cr.db.Query(c, sql, userId, r.From, r.End())
+	if err != nil {
+		logger.FromContext(c).Error("Error querying category totals", "err", err)
+		return nil, err
+	}
+	totals := []models.CategoryTotal{}
+	var total models.CategoryTotal
+	_, err = pgx.ForEachRow(rows, []any{&total.Category, &total.Amount, &total.Count}, func() error {
+		totals = append(totals, total)
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(c).Error("Error scanning category totals", "err", err)
+		return nil, err
 	}
+	return totals, nil
+}
 
-	var chartData models.ChartData
-	if err := cr.db.QueryRow(c, queryExec, user_id).Scan(&chartData.Labels, &chartData.IncomeData, &chartData.ExpenseData); err != nil {
-		if err == sql.ErrNoRows {
-			return models.ChartData{}, fmt.Errorf("no data found for user %d", user_id)
-		}
-		return models.ChartData{}, err
+// GetTopCounterparties - user who receive the most money from user in range, largest first
+func (cr *ChartRepository) GetTopCounterparties(c context.Context, userId int, r models.ChartRange, limit int) ([]models.CounterpartyTotal, error) {
+	sql := `SELECT t.counterparty_user_id, COALESCE(p.fullname, ''), COALESCE(p.profile_picture, ''), SUM(t.amount), COUNT(*)
+	FROM transactions t
+	LEFT JOIN profile p ON p.user_id = t.counterparty_user_id` + spendingWhere + `
+	GROUP BY 1, 2, 3
+	ORDER BY 4 DESC, 1
+	LIMIT $4`
+	rows, err := cr.db.Query(c, sql, userId, r.From, r.End(), limit)
+	if err != nil {
+		logger.FromContext(c).Error("Error querying top counterparties", "err", err)
+		return nil, err
+	}
+	totals := []models.CounterpartyTotal{}
+	var total models.CounterpartyTotal
+	_, err = pgx.ForEachRow(rows, []any{&total.UserID, &total.Name, &total.ProfilePicture, &total.Amount, &total.Count}, func() error {
+		totals = append(totals, total)
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(c).Error("Error scanning top counterparties", "err", err)
+		return nil, err
 	}
-	// if no error return data, and error is nil
-	return chartData, nil
+	return totals, nil
 }
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+type EventRepository struct {
+	rdb *redis.Client
+}
+
+func NewEventRepository(rdb *redis.Client) *EventRepository {
+	return &EventRepository{rdb: rdb}
+}
+
+// Subscribe listen to user event channel, caller must close the subscription
+func (er *EventRepository) Subscribe(c context.Context, userID int) (Subscription, error) {
+	pubsub := er.rdb.Subscribe(c, utils.UserEventChannel(userID))
+	// wait for confirmation that subscription is created
+	if _, err := pubsub.Receive(c); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+	return pubsub, nil
+}
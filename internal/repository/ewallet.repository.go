@@ -2,9 +2,8 @@ package repository
 
 import (
 	"context"
-	"errors"
-	"log"
 
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
 	"github.com/Belalai-E-Wallet-Backend/internal/models"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -26,9 +25,9 @@ func (er *EwalletRepository) GetBalance(c context.Context, user_id int) (*models
 	var balance models.Balance
 	if err := er.db.QueryRow(c, sql, user_id).Scan(&balance.User_id, &balance.Balance); err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, errors.New("user_id not found")
+			return nil, ErrUserNotFound
 		}
-		log.Println("Internal Server Error. \nCause: ", err.Error())
+		logger.FromContext(c).Error("Internal Server Error", "err", err)
 		return nil, err
 	}
 	return &balance, nil
@@ -0,0 +1,101 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// fixture insert rows directly with SQL, so repository under test isn't used to prepare its own data
+type fixture struct {
+	t  *testing.T
+	db *pgxpool.Pool
+}
+
+// user insert user with profile and wallet, return id of user and wallet
+func (f fixture) user(email, fullname, phone string, balance int) (userID, walletID int) {
+	f.t.Helper()
+	c := context.Background()
+	if err := f.db.QueryRow(c, `INSERT INTO users (email, password) VALUES ($1, 'hash') RETURNING id`, email).Scan(&userID); err != nil {
+		f.t.Fatal(err)
+	}
+	if _, err := f.db.Exec(c, `INSERT INTO profile (user_id, fullname, phone) VALUES ($1, $2, $3)`, userID, fullname, phone); err != nil {
+		f.t.Fatal(err)
+	}
+	if err := f.db.QueryRow(c, `INSERT INTO wallets (user_id, balance) VALUES ($1, $2) RETURNING id`, userID, balance).Scan(&walletID); err != nil {
+		f.t.Fatal(err)
+	}
+	return userID, walletID
+}
+
+func (f fixture) paymentMethod(name string) int {
+	f.t.Helper()
+	var id int
+	if err := f.db.QueryRow(context.Background(), `INSERT INTO payment_method (name) VALUES ($1) RETURNING id`, name).Scan(&id); err != nil {
+		f.t.Fatal(err)
+	}
+	return id
+}
+
+// transfer insert transfer row without changing balance
+func (f fixture) transfer(senderWallet, receiverWallet, amount int, status string, createdAt time.Time) int {
+	f.t.Helper()
+	c := context.Background()
+	var id int
+	err := f.db.QueryRow(c, `INSERT INTO transfer (sender_wallet_id, receiver_wallet_id, amount, transfer_status, notes, created_at)
+		VALUES ($1, $2, $3, $4, 'fixture', $5) RETURNING id`, senderWallet, receiverWallet, amount, status, createdAt).Scan(&id)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	if _, err := f.db.Exec(c, `INSERT INTO wallets_transfer (wallets_id, transfer_id) VALUES ($1, $3), ($2, $3)`, senderWallet, receiverWallet, id); err != nil {
+		f.t.Fatal(err)
+	}
+	return id
+}
+
+// notes replace notes of transfer
+func (f fixture) notes(transferID int, notes string) {
+	f.t.Helper()
+	if _, err := f.db.Exec(context.Background(), `UPDATE transfer SET notes = $2 WHERE id = $1`, transferID, notes); err != nil {
+		f.t.Fatal(err)
+	}
+}
+
+// topup insert topup row of wallet without changing balance
+func (f fixture) topup(walletID, paymentID, amount int, status string, createdAt time.Time) int {
+	f.t.Helper()
+	c := context.Background()
+	var id int
+	err := f.db.QueryRow(c, `INSERT INTO topup (amount, tax, payment_id, topup_status, created_at)
+		VALUES ($1, 1000, $2, $3, $4) RETURNING id`, amount, paymentID, status, createdAt).Scan(&id)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	if _, err := f.db.Exec(c, `INSERT INTO wallets_topup (wallets_id, topup_id) VALUES ($1, $2)`, walletID, id); err != nil {
+		f.t.Fatal(err)
+	}
+	return id
+}
+
+// today return CURRENT_DATE of database at noon, so fixture and the query agree on the date
+func (f fixture) today() time.Time {
+	f.t.Helper()
+	var today time.Time
+	if err := f.db.QueryRow(context.Background(), `SELECT CURRENT_DATE::timestamp + interval '12 hours'`).Scan(&today); err != nil {
+		f.t.Fatal(err)
+	}
+	return today
+}
+
+func (f fixture) balance(walletID int) int {
+	f.t.Helper()
+	var balance int
+	if err := f.db.QueryRow(context.Background(), `SELECT balance FROM wallets WHERE id = $1`, walletID).Scan(&balance); err != nil {
+		f.t.Fatal(err)
+	}
+	return balance
+}
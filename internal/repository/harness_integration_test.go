@@ -0,0 +1,308 @@
+//go:build integration
+
+// Integration test run repository against real postgres and redis:
+//
+//	go test -tags integration ./internal/repository/...
+//
+// TEST_DATABASE_URL and TEST_REDIS_URL are used when set (test run on its own schema, dropped after).
+// Redis database is flushed before every test, so TEST_REDIS_URL also need TEST_REDIS_FLUSH=1 to confirm it can be emptied.
+// Otherwise disposable server is started from initdb/pg_ctl and redis-server on PATH,
+// or from docker image already pulled (TEST_POSTGRES_IMAGE, TEST_REDIS_IMAGE), nothing is downloaded.
+// When none of them is available every test is skipped.
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// max time to wait disposable server accept connection
+const startTimeout = 30 * time.Second
+
+var harness struct {
+	db   *pgxpool.Pool
+	rdb  *redis.Client
+	skip string
+	// default category rule inserted by migration, truncating users cascade to category_rule
+	categoryRules [3][]string
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	cleanup, err := startHarness()
+	defer cleanup()
+	if err != nil {
+		harness.skip = err.Error()
+		fmt.Fprintln(os.Stderr, "integration test skipped:", harness.skip)
+	}
+	return m.Run()
+}
+
+// setup return connection of harness with empty tables, test is skipped when harness isn't available
+func setup(t *testing.T) (*pgxpool.Pool, *redis.Client) {
+	t.Helper()
+	if harness.skip != "" {
+		t.Skip(harness.skip)
+	}
+	c := context.Background()
+	truncate := `TRUNCATE users, profile, wallets, payment_method, topup, transfer, wallets_transfer, wallets_topup, transaction_status_history, transaction_trash, transaction_category, category_rule, budget, budget_alert RESTART IDENTITY CASCADE`
+	if _, err := harness.db.Exec(c, truncate); err != nil {
+		t.Fatal(err)
+	}
+	rules := harness.categoryRules
+	seed := `INSERT INTO category_rule (field, keyword, category)
+		SELECT f, k, cat FROM UNNEST($1::TEXT[], $2::TEXT[], $3::TEXT[]) WITH ORDINALITY AS r(f, k, cat, n) ORDER BY n`
+	if _, err := harness.db.Exec(c, seed, rules[0], rules[1], rules[2]); err != nil {
+		t.Fatal(err)
+	}
+	// redis is started by harness or TEST_REDIS_FLUSH allow flushing the one from env
+	if err := harness.rdb.FlushDB(c).Err(); err != nil {
+		t.Fatal(err)
+	}
+	return harness.db, harness.rdb
+}
+
+// startHarness start postgres & redis, apply migration, cleanup must be called even when error is returned
+func startHarness() (func(), error) {
+	var cleanups []func()
+	cleanup := func() {
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			cleanups[i]()
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "belalai-integration-")
+	if err != nil {
+		return cleanup, err
+	}
+	cleanups = append(cleanups, func() { os.RemoveAll(dir) })
+
+	dbURL, stopDB, err := postgresURL(dir)
+	if err != nil {
+		return cleanup, err
+	}
+	cleanups = append(cleanups, stopDB)
+
+	redisURL, stopRedis, err := redisURL(dir)
+	if err != nil {
+		return cleanup, err
+	}
+	cleanups = append(cleanups, stopRedis)
+
+	c, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+
+	// shared database from env isn't touched outside of our own schema
+	schema := fmt.Sprintf("belalai_test_%d", os.Getpid())
+	cfg, err := pgxpool.ParseConfig(dbURL)
+	if err != nil {
+		return cleanup, err
+	}
+	admin, err := waitPostgres(c, cfg.ConnConfig)
+	if err != nil {
+		return cleanup, err
+	}
+	if _, err := admin.Exec(c, "CREATE SCHEMA "+schema); err != nil {
+		admin.Close(c)
+		return cleanup, err
+	}
+	cleanups = append(cleanups, func() {
+		admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+		admin.Close(context.Background())
+	})
+
+	// public is kept on the path, extension (ex: pg_trgm) may already be installed there
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema + ", public"
+	cfg.MaxConns = 20
+	pool, err := pgxpool.NewWithConfig(c, cfg)
+	if err != nil {
+		return cleanup, err
+	}
+	cleanups = append(cleanups, pool.Close)
+	migrator, err := db.NewMigrator(pool)
+	if err != nil {
+		return cleanup, err
+	}
+	if _, err := migrator.Up(c); err != nil {
+		return cleanup, fmt.Errorf("apply migration: %w", err)
+	}
+	rules := `SELECT ARRAY_AGG(field ORDER BY id), ARRAY_AGG(keyword ORDER BY id), ARRAY_AGG(category ORDER BY id) FROM category_rule`
+	if err := pool.QueryRow(c, rules).Scan(&harness.categoryRules[0], &harness.categoryRules[1], &harness.categoryRules[2]); err != nil {
+		return cleanup, err
+	}
+
+	opt, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return cleanup, err
+	}
+	rdb := redis.NewClient(opt)
+	cleanups = append(cleanups, func() { rdb.Close() })
+	if err := waitRedis(c, rdb); err != nil {
+		return cleanup, err
+	}
+
+	harness.db, harness.rdb = pool, rdb
+	return cleanup, nil
+}
+
+func postgresURL(dir string) (string, func(), error) {
+	if url := os.Getenv("TEST_DATABASE_URL"); url != "" {
+		return url, func() {}, nil
+	}
+	if url, stop, err := startLocalPostgres(filepath.Join(dir, "pg")); err == nil {
+		return url, stop, nil
+	}
+	image := envOr("TEST_POSTGRES_IMAGE", "postgres:16-alpine")
+	addr, stop, err := startContainer(image, "5432/tcp", "-e", "POSTGRES_PASSWORD=postgres")
+	if err != nil {
+		return "", func() {}, fmt.Errorf("no postgres available (set TEST_DATABASE_URL, install initdb/pg_ctl or pull %s): %w", image, err)
+	}
+	return "postgres://postgres:postgres@" + addr + "/postgres?sslmode=disable", stop, nil
+}
+
+func redisURL(dir string) (string, func(), error) {
+	if url := os.Getenv("TEST_REDIS_URL"); url != "" {
+		// app key has no per-run prefix, test can only clean up by flushing the whole database
+		if os.Getenv("TEST_REDIS_FLUSH") != "1" {
+			return "", func() {}, fmt.Errorf("TEST_REDIS_URL database is flushed before every test, set TEST_REDIS_FLUSH=1 to allow it")
+		}
+		return url, func() {}, nil
+	}
+	if url, stop, err := startLocalRedis(dir); err == nil {
+		return url, stop, nil
+	}
+	image := envOr("TEST_REDIS_IMAGE", "redis:7-alpine")
+	addr, stop, err := startContainer(image, "6379/tcp")
+	if err != nil {
+		return "", func() {}, fmt.Errorf("no redis available (set TEST_REDIS_URL, install redis-server or pull %s): %w", image, err)
+	}
+	return "redis://" + addr + "/0", stop, nil
+}
+
+// postgres refuse to run as root, so this only work as normal user
+func startLocalPostgres(dataDir string) (string, func(), error) {
+	initdb, err := exec.LookPath("initdb")
+	if err != nil {
+		return "", nil, err
+	}
+	pgCtl, err := exec.LookPath("pg_ctl")
+	if err != nil {
+		return "", nil, err
+	}
+	port, err := freePort()
+	if err != nil {
+		return "", nil, err
+	}
+	if out, err := exec.Command(initdb, "-D", dataDir, "-U", "postgres", "-A", "trust", "--no-sync").CombinedOutput(); err != nil {
+		return "", nil, fmt.Errorf("initdb: %w: %s", err, out)
+	}
+	opts := fmt.Sprintf("-p %d -k %s -c listen_addresses=127.0.0.1 -F", port, dataDir)
+	if out, err := exec.Command(pgCtl, "-D", dataDir, "-o", opts, "-l", filepath.Join(dataDir, "log"), "-w", "start").CombinedOutput(); err != nil {
+		return "", nil, fmt.Errorf("pg_ctl start: %w: %s", err, out)
+	}
+	stop := func() { exec.Command(pgCtl, "-D", dataDir, "-m", "immediate", "-w", "stop").Run() }
+	return fmt.Sprintf("postgres://postgres@127.0.0.1:%d/postgres?sslmode=disable", port), stop, nil
+}
+
+func startLocalRedis(dir string) (string, func(), error) {
+	server, err := exec.LookPath("redis-server")
+	if err != nil {
+		return "", nil, err
+	}
+	port, err := freePort()
+	if err != nil {
+		return "", nil, err
+	}
+	cmd := exec.Command(server, "--port", fmt.Sprint(port), "--bind", "127.0.0.1", "--save", "", "--appendonly", "no", "--dir", dir)
+	if err := cmd.Start(); err != nil {
+		return "", nil, err
+	}
+	stop := func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}
+	return fmt.Sprintf("redis://127.0.0.1:%d/0", port), stop, nil
+}
+
+// start container from local image only, return host:port of the published port
+func startContainer(image, port string, args ...string) (string, func(), error) {
+	docker, err := exec.LookPath("docker")
+	if err != nil {
+		return "", nil, err
+	}
+	runArgs := append([]string{"run", "-d", "--rm", "--pull=never", "-p", "127.0.0.1::" + strings.TrimSuffix(port, "/tcp")}, args...)
+	out, err := exec.Command(docker, append(runArgs, image)...).Output()
+	if err != nil {
+		return "", nil, fmt.Errorf("docker run %s: %w", image, err)
+	}
+	id := strings.TrimSpace(string(out))
+	stop := func() { exec.Command(docker, "rm", "-f", id).Run() }
+
+	out, err = exec.Command(docker, "port", id, port).Output()
+	if err != nil {
+		stop()
+		return "", nil, fmt.Errorf("docker port %s: %w", image, err)
+	}
+	// docker port may print ipv4 and ipv6 mapping, first line is enough
+	addr := strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+	return addr, stop, nil
+}
+
+func waitPostgres(c context.Context, cfg *pgx.ConnConfig) (*pgx.Conn, error) {
+	for {
+		conn, err := pgx.ConnectConfig(c, cfg)
+		if err == nil {
+			return conn, nil
+		}
+		select {
+		case <-c.Done():
+			return nil, fmt.Errorf("postgres isn't ready: %w", err)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+func waitRedis(c context.Context, rdb *redis.Client) error {
+	for {
+		err := rdb.Ping(c).Err()
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-c.Done():
+			return fmt.Errorf("redis isn't ready: %w", err)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
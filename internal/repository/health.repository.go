@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+type HealthRepository struct {
+	db  *pgxpool.Pool
+	rdb *redis.Client
+}
+
+func NewHealthRepository(db *pgxpool.Pool, rdb *redis.Client) *HealthRepository {
+	return &HealthRepository{db: db, rdb: rdb}
+}
+
+func (hr *HealthRepository) PingDB(c context.Context) error {
+	return hr.db.Ping(c)
+}
+
+func (hr *HealthRepository) PingRedis(c context.Context) error {
+	return hr.rdb.Ping(c).Err()
+}
+
+// MigrationVersion read version from table created by golang-migrate
+func (hr *HealthRepository) MigrationVersion(c context.Context) (uint, bool, error) {
+	sql := "SELECT version, dirty FROM schema_migrations LIMIT 1"
+
+	var version int64
+	var dirty bool
+	if err := hr.db.QueryRow(c, sql).Scan(&version, &dirty); err != nil {
+		return 0, false, err
+	}
+	return uint(version), dirty, nil
+}
@@ -1 +1,18 @@
 package repository
+
+import "errors"
+
+// error returned by repository when data doesn't exist or can't be accessed by the user
+// handler map it to apperror code with errors.Is
+var (
+	ErrUserNotFound         = errors.New("user not found")
+	ErrProfileNotFound      = errors.New("profile not found")
+	ErrEmailRegistered      = errors.New("email already registered")
+	ErrPINNotSet            = errors.New("pin not set")
+	ErrNoTransactions       = errors.New("no transactions found")
+	ErrTransactionNotFound  = errors.New("transaction not found or user not authorized")
+	ErrTopUpNotFound        = errors.New("topup not found")
+	ErrTopUpNotOwned        = errors.New("topup is not owned by user")
+	ErrCategoryRuleNotFound = errors.New("category rule not found")
+	ErrBudgetNotFound       = errors.New("budget not found")
+)
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// handler depend on these interfaces instead of the postgres implementation,
+// so it can be tested with in-memory implementation in repository/memory
+
+type AuthRepo interface {
+	GetEmail(c context.Context, email string) (*models.User, error)
+	CreateAccount(c context.Context, user *models.User) error
+	VerifyPassword(c context.Context, userId int) (string, error)
+	UpdatePassword(c context.Context, userId int, hashedPassword string) error
+	VerifyPIN(c context.Context, userId int) (string, error)
+	UpdatePIN(c context.Context, userId int, hashedPin string) error
+	BlacklistToken(c context.Context, token string) error
+	SaveLanguage(c context.Context, userId int, lang string) error
+	SaveResetToken(c context.Context, key, value string, ttl time.Duration) error
+	GetResetToken(c context.Context, key string) (string, error)
+	DeleteResetToken(c context.Context, key string) error
+	GetEmailForSMPT(c context.Context, email string) (*models.MailRecipient, error)
+	GetMailRecipient(c context.Context, userId int) (*models.MailRecipient, error)
+}
+
+type TransferRepo interface {
+	FilterUser(c context.Context, query string, offset, limit, page int) (models.ListprofileResponse, error)
+	SearchUserByCursor(c context.Context, query string, cursor *models.Cursor, limit int) (models.CursorPage[models.ProfileResponse], error)
+	GetHashedPin(c context.Context, senderId int) (models.UserPin, error)
+	TransferMoney(c context.Context, senderId int, body models.TransferBody) (int, error)
+	GetMailRecipients(c context.Context, senderId, receiverWalletId int) (*models.MailRecipient, *models.MailRecipient, error)
+}
+
+type EWalletRepo interface {
+	GetBalance(c context.Context, userId int) (*models.Balance, error)
+}
+
+type TransactionRepo interface {
+	GetHistory(c context.Context, userID int, filter models.HistoryFilter, offset, limit int) ([]models.TransactionHistory, error)
+	GetHistoryCount(c context.Context, userID int, filter models.HistoryFilter) (int, error)
+	SoftDeleteTransaction(c context.Context, transactionID, userID int) error
+	SoftDeleteTopup(c context.Context, topupID, userID int) error
+	HideTransactions(c context.Context, userID int, refs []models.TransactionRef) ([]models.TransactionRef, error)
+	RestoreTransactions(c context.Context, userID int, refs []models.TransactionRef) ([]models.TransactionRef, error)
+	GetTrash(c context.Context, userID int, offset, limit int) ([]models.TrashItem, error)
+	GetTrashCount(c context.Context, userID int) (int, error)
+	PurgeTrash(c context.Context, retention time.Duration) (int64, error)
+	SetCategory(c context.Context, userID int, ref models.TransactionRef, category string) (string, error)
+	GetTopupHistory(c context.Context, userID int) ([]models.TransactionHistory, error)
+	GetAllHistory(c context.Context, userID int, filter models.HistoryFilter, limit int, offset int) ([]models.TransactionHistory, error)
+	GetAllHistoryCount(c context.Context, userID int, filter models.HistoryFilter) (int, error)
+	GetHistoryByCursor(c context.Context, userID int, filter models.HistoryFilter, cursor *models.Cursor, limit int) (models.CursorPage[models.TransactionHistory], error)
+	GetTransactionDetail(c context.Context, kind string, id int) (*models.TransactionDetail, error)
+	GetOpeningBalance(c context.Context, userID int, before time.Time) (int, error)
+	StreamStatement(c context.Context, userID int, from, to time.Time, fn func(models.StatementEntry) error) error
+	GetMailRecipient(c context.Context, userID int) (*models.MailRecipient, error)
+}
+
+type ProfileRepo interface {
+	GetProfile(c context.Context, userId int) (*models.Profile, error)
+	UpdateProfile(c context.Context, profile *models.Profile) error
+	DeleteAvatar(c context.Context, userId int) error
+}
+
+type TopUpRepo interface {
+	CreateTopUp(c context.Context, topup *models.TopUp) (*models.TopUp, error)
+	UpdateStatusTopUp(c context.Context, topupID int, status models.TopUpStatus) error
+	GetTopUpByID(c context.Context, topupID int) (*models.TopUp, error)
+	ApplyToWallet(c context.Context, walletID int, topupID int, amount int) error
+	GetWalletIDByUserID(c context.Context, userID int) (int, error)
+	FindAllPaymentMethods(c context.Context) ([]models.PaymentMethod, error)
+	CreateTopUpTransaction(c context.Context, topup *models.TopUp, userID int) (*models.TopUp, error)
+}
+
+type ChartRepo interface {
+	GetChartData(c context.Context, userId int, r models.ChartRange) (models.ChartData, error)
+	GetCategoryTotals(c context.Context, userId int, r models.ChartRange) ([]models.CategoryTotal, error)
+	GetTopCounterparties(c context.Context, userId int, r models.ChartRange, limit int) ([]models.CounterpartyTotal, error)
+}
+
+type CategoryRepo interface {
+	GetRules(c context.Context, userID int) ([]models.CategoryRule, error)
+	CreateRule(c context.Context, userID int, rule *models.CategoryRule) error
+	DeleteRule(c context.Context, userID, ruleID int) error
+}
+
+type BudgetRepo interface {
+	GetBudgets(c context.Context, userID int) ([]models.Budget, error)
+	SaveBudget(c context.Context, userID int, budget *models.Budget) error
+	DeleteBudget(c context.Context, userID, budgetID int) error
+	MarkAlert(c context.Context, budgetID int, month time.Time, threshold int) (bool, error)
+	PublishAlert(c context.Context, userID int, alert models.BudgetAlert) error
+	GetMailRecipient(c context.Context, userID int) (*models.MailRecipient, error)
+}
+
+// Subscription is subscription of user event channel, *redis.PubSub implement it
+type Subscription interface {
+	Channel(opts ...redis.ChannelOption) <-chan *redis.Message
+	Close() error
+}
+
+type EventRepo interface {
+	Subscribe(c context.Context, userID int) (Subscription, error)
+}
+
+type HealthRepo interface {
+	PingDB(c context.Context) error
+	PingRedis(c context.Context) error
+	MigrationVersion(c context.Context) (uint, bool, error)
+}
+
+// make sure postgres implementation keep satisfying the interfaces
+var (
+	_ AuthRepo        = (*AuthRepository)(nil)
+	_ TransferRepo    = (*TransferRepository)(nil)
+	_ EWalletRepo     = (*EwalletRepository)(nil)
+	_ TransactionRepo = (*TransactionRepository)(nil)
+	_ ProfileRepo     = (*ProfileRepository)(nil)
+	_ TopUpRepo       = (*TopUpRepository)(nil)
+	_ ChartRepo       = (*ChartRepository)(nil)
+	_ CategoryRepo    = (*CategoryRepository)(nil)
+	_ BudgetRepo      = (*BudgetRepository)(nil)
+	_ EventRepo       = (*EventRepository)(nil)
+	_ HealthRepo      = (*HealthRepository)(nil)
+)
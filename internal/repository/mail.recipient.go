@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// get recipient (email, name and language preference) with condition on users u / profile p / wallets w
+// language is empty when user hasn't picked one
+func getMailRecipient(c context.Context, db *pgxpool.Pool, where string, arg any) (*models.MailRecipient, error) {
+	sql := `SELECT u.id, u.email, COALESCE(p.fullname, ''), COALESCE(p.language, '')
+	FROM users u
+	LEFT JOIN profile p ON p.user_id = u.id
+	LEFT JOIN wallets w ON w.user_id = u.id
+	WHERE ` + where + ` LIMIT 1`
+
+	var r models.MailRecipient
+	if err := db.QueryRow(c, sql, arg).Scan(&r.UserID, &r.Email, &r.Fullname, &r.Language); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+type AuthRepository struct {
+	s *Store
+}
+
+func NewAuthRepository(s *Store) *AuthRepository {
+	return &AuthRepository{s: s}
+}
+
+func (ar *AuthRepository) GetEmail(c context.Context, email string) (*models.User, error) {
+	ar.s.mu.Lock()
+	defer ar.s.mu.Unlock()
+
+	user := ar.s.userByEmail(email)
+	if user == nil {
+		return nil, repository.ErrUserNotFound
+	}
+	u := *user
+	u.Language = ar.s.mailRecipient(user).Language
+	return &u, nil
+}
+
+func (ar *AuthRepository) CreateAccount(c context.Context, user *models.User) error {
+	ar.s.mu.Lock()
+	defer ar.s.mu.Unlock()
+
+	if ar.s.userByEmail(user.Email) != nil {
+		return repository.ErrEmailRegistered
+	}
+	u := *user
+	ar.s.insertUser(&u)
+	user.ID = u.ID
+	user.CreatedAt = u.CreatedAt
+	return nil
+}
+
+func (ar *AuthRepository) VerifyPassword(c context.Context, userId int) (string, error) {
+	ar.s.mu.Lock()
+	defer ar.s.mu.Unlock()
+
+	user, ok := ar.s.users[userId]
+	if !ok {
+		return "", repository.ErrUserNotFound
+	}
+	return user.Password, nil
+}
+
+func (ar *AuthRepository) UpdatePassword(c context.Context, userId int, hashedPassword string) error {
+	ar.s.mu.Lock()
+	defer ar.s.mu.Unlock()
+
+	if user, ok := ar.s.users[userId]; ok {
+		now := ar.s.now()
+		user.Password = hashedPassword
+		user.UpdatedAt = &now
+	}
+	return nil
+}
+
+func (ar *AuthRepository) VerifyPIN(c context.Context, userId int) (string, error) {
+	ar.s.mu.Lock()
+	defer ar.s.mu.Unlock()
+
+	user, ok := ar.s.users[userId]
+	if !ok {
+		return "", repository.ErrUserNotFound
+	}
+	if user.Pin == nil {
+		return "", repository.ErrPINNotSet
+	}
+	return *user.Pin, nil
+}
+
+func (ar *AuthRepository) UpdatePIN(c context.Context, userId int, hashedPin string) error {
+	ar.s.mu.Lock()
+	defer ar.s.mu.Unlock()
+
+	if user, ok := ar.s.users[userId]; ok {
+		now := ar.s.now()
+		user.Pin = &hashedPin
+		user.UpdatedAt = &now
+	}
+	return nil
+}
+
+func (ar *AuthRepository) BlacklistToken(c context.Context, token string) error {
+	ar.s.mu.Lock()
+	defer ar.s.mu.Unlock()
+
+	ar.s.set(blacklistKey(token), "true", 30*time.Minute)
+	return nil
+}
+
+func (ar *AuthRepository) SaveLanguage(c context.Context, userId int, lang string) error {
+	ar.s.mu.Lock()
+	defer ar.s.mu.Unlock()
+
+	if lang == "" {
+		delete(ar.s.kv, utils.UserLanguageKey(userId))
+		return nil
+	}
+	ar.s.set(utils.UserLanguageKey(userId), lang, 0)
+	return nil
+}
+
+func (ar *AuthRepository) SaveResetToken(c context.Context, key, value string, ttl time.Duration) error {
+	ar.s.mu.Lock()
+	defer ar.s.mu.Unlock()
+
+	ar.s.set(key, value, ttl)
+	return nil
+}
+
+// missing or expired token return redis.Nil like the redis implementation
+func (ar *AuthRepository) GetResetToken(c context.Context, key string) (string, error) {
+	ar.s.mu.Lock()
+	defer ar.s.mu.Unlock()
+
+	value, ok := ar.s.get(key)
+	if !ok {
+		return "", redis.Nil
+	}
+	return value, nil
+}
+
+func (ar *AuthRepository) DeleteResetToken(c context.Context, key string) error {
+	ar.s.mu.Lock()
+	defer ar.s.mu.Unlock()
+
+	delete(ar.s.kv, key)
+	return nil
+}
+
+func (ar *AuthRepository) GetEmailForSMPT(c context.Context, email string) (*models.MailRecipient, error) {
+	ar.s.mu.Lock()
+	defer ar.s.mu.Unlock()
+
+	user := ar.s.userByEmail(email)
+	if user == nil {
+		return nil, repository.ErrUserNotFound
+	}
+	return ar.s.mailRecipient(user), nil
+}
+
+func (ar *AuthRepository) GetMailRecipient(c context.Context, userId int) (*models.MailRecipient, error) {
+	ar.s.mu.Lock()
+	defer ar.s.mu.Unlock()
+
+	user, ok := ar.s.users[userId]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	return ar.s.mailRecipient(user), nil
+}
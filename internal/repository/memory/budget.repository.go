@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+)
+
+type BudgetRepository struct {
+	s *Store
+}
+
+func NewBudgetRepository(s *Store) *BudgetRepository {
+	return &BudgetRepository{s: s}
+}
+
+func (br *BudgetRepository) GetBudgets(c context.Context, userID int) ([]models.Budget, error) {
+	br.s.mu.Lock()
+	defer br.s.mu.Unlock()
+
+	budgets := []models.Budget{}
+	for _, b := range br.s.budgets {
+		if b.userID == userID {
+			budgets = append(budgets, b.Budget)
+		}
+	}
+	sort.Slice(budgets, func(i, j int) bool { return budgets[i].Category < budgets[j].Category })
+	return budgets, nil
+}
+
+func (br *BudgetRepository) SaveBudget(c context.Context, userID int, b *models.Budget) error {
+	br.s.mu.Lock()
+	defer br.s.mu.Unlock()
+
+	now := br.s.now()
+	i := slices.IndexFunc(br.s.budgets, func(saved *budget) bool { return saved.userID == userID && saved.Category == b.Category })
+	if i < 0 {
+		b.ID = br.s.nextID("budget")
+		b.CreatedAt, b.UpdatedAt = now, nil
+		br.s.budgets = append(br.s.budgets, &budget{Budget: *b, userID: userID})
+		return nil
+	}
+
+	saved := br.s.budgets[i]
+	saved.Amount, saved.UpdatedAt = b.Amount, &now
+	*b = saved.Budget
+	for key := range br.s.budgetAlerts {
+		if key.budgetID == saved.ID {
+			delete(br.s.budgetAlerts, key)
+		}
+	}
+	return nil
+}
+
+func (br *BudgetRepository) DeleteBudget(c context.Context, userID, budgetID int) error {
+	br.s.mu.Lock()
+	defer br.s.mu.Unlock()
+
+	i := slices.IndexFunc(br.s.budgets, func(b *budget) bool { return b.ID == budgetID && b.userID == userID })
+	if i < 0 {
+		return repository.ErrBudgetNotFound
+	}
+	br.s.budgets = slices.Delete(br.s.budgets, i, i+1)
+	for key := range br.s.budgetAlerts {
+		if key.budgetID == budgetID {
+			delete(br.s.budgetAlerts, key)
+		}
+	}
+	return nil
+}
+
+func (br *BudgetRepository) MarkAlert(c context.Context, budgetID int, month time.Time, threshold int) (bool, error) {
+	br.s.mu.Lock()
+	defer br.s.mu.Unlock()
+
+	key := budgetAlertKey{budgetID: budgetID, month: month.Format(time.DateOnly), threshold: threshold}
+	if _, ok := br.s.budgetAlerts[key]; ok {
+		return false, nil
+	}
+	br.s.budgetAlerts[key] = struct{}{}
+	return true, nil
+}
+
+func (br *BudgetRepository) PublishAlert(c context.Context, userID int, alert models.BudgetAlert) error {
+	br.s.mu.Lock()
+	defer br.s.mu.Unlock()
+
+	br.s.publish(userID, models.EventBudgetAlert, alert)
+	return nil
+}
+
+func (br *BudgetRepository) GetMailRecipient(c context.Context, userID int) (*models.MailRecipient, error) {
+	br.s.mu.Lock()
+	defer br.s.mu.Unlock()
+
+	user, ok := br.s.users[userID]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	return br.s.mailRecipient(user), nil
+}
@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+)
+
+type CategoryRepository struct {
+	s *Store
+}
+
+func NewCategoryRepository(s *Store) *CategoryRepository {
+	return &CategoryRepository{s: s}
+}
+
+func (cr *CategoryRepository) GetRules(c context.Context, userID int) ([]models.CategoryRule, error) {
+	cr.s.mu.Lock()
+	defer cr.s.mu.Unlock()
+
+	rules := []models.CategoryRule{}
+	for _, r := range cr.s.categoryRules {
+		if r.userID == userID {
+			rules = append(rules, r.CategoryRule)
+		}
+	}
+	return rules, nil
+}
+
+func (cr *CategoryRepository) CreateRule(c context.Context, userID int, rule *models.CategoryRule) error {
+	cr.s.mu.Lock()
+	defer cr.s.mu.Unlock()
+
+	rule.ID = cr.s.nextID("category_rule")
+	rule.CreatedAt = cr.s.now()
+	cr.s.categoryRules = append(cr.s.categoryRules, categoryRule{CategoryRule: *rule, userID: userID})
+	return nil
+}
+
+func (cr *CategoryRepository) DeleteRule(c context.Context, userID, ruleID int) error {
+	cr.s.mu.Lock()
+	defer cr.s.mu.Unlock()
+
+	i := slices.IndexFunc(cr.s.categoryRules, func(r categoryRule) bool { return r.ID == ruleID && r.userID == userID })
+	if i < 0 {
+		return repository.ErrCategoryRuleNotFound
+	}
+	cr.s.categoryRules = slices.Delete(cr.s.categoryRules, i, i+1)
+	return nil
+}
@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+)
+
+type ChartRepository struct {
+	s *Store
+}
+
+func NewChartRepository(s *Store) *ChartRepository {
+	return &ChartRepository{s: s}
+}
+
+func (cr *ChartRepository) GetChartData(c context.Context, userId int, r models.ChartRange) (models.ChartData, error) {
+	cr.s.mu.Lock()
+	defer cr.s.mu.Unlock()
+
+	data := r.Empty()
+	index := make(map[string]int, len(data.Labels))
+	for i, start := range r.Buckets() {
+		index[start.Format(time.DateOnly)] = i
+	}
+
+	w := cr.s.walletOfUser(userId)
+	if w == nil {
+		return data, nil
+	}
+	add := func(t time.Time, income, expense int) {
+		if t.Before(r.From) || !t.Before(r.End()) {
+			return
+		}
+		if i, ok := index[r.Truncate(t).Format(time.DateOnly)]; ok {
+			data.IncomeData[i] += income
+			data.ExpenseData[i] += expense
+		}
+	}
+	for _, t := range cr.s.topups {
+		if t.walletID == w.id && t.Status == models.TopUpSuccess {
+			add(t.CreatedAt, t.Amount, 0)
+		}
+	}
+	for _, t := range cr.s.transfers {
+		if t.status != "success" {
+			continue
+		}
+		if t.receiverWalletID == w.id {
+			add(t.createdAt, t.amount, 0)
+		}
+		if t.senderWalletID == w.id {
+			add(t.createdAt, 0, t.amount)
+		}
+	}
+	return data, nil
+}
+
+// spending is success sent transfer of user in range
+func (cr *ChartRepository) spending(userId int, r models.ChartRange) []*transfer {
+	w := cr.s.walletOfUser(userId)
+	if w == nil {
+		return nil
+	}
+	var transfers []*transfer
+	for _, t := range cr.s.transfers {
+		if t.status == "success" && t.senderWalletID == w.id && !t.createdAt.Before(r.From) && t.createdAt.Before(r.End()) {
+			transfers = append(transfers, t)
+		}
+	}
+	return transfers
+}
+
+func (cr *ChartRepository) GetCategoryTotals(c context.Context, userId int, r models.ChartRange) ([]models.CategoryTotal, error) {
+	cr.s.mu.Lock()
+	defer cr.s.mu.Unlock()
+
+	totals := []models.CategoryTotal{}
+	index := map[string]int{}
+	for _, t := range cr.spending(userId, r) {
+		receiver := cr.s.wallets[t.receiverWalletID].userID
+		category := cr.s.category(userId, "transfer", t.id, "Send", t.notes, cr.s.fullname(receiver))
+		i, ok := index[category]
+		if !ok {
+			i = len(totals)
+			index[category] = i
+			totals = append(totals, models.CategoryTotal{Category: category})
+		}
+		totals[i].Amount += t.amount
+		totals[i].Count++
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Amount != totals[j].Amount {
+			return totals[i].Amount > totals[j].Amount
+		}
+		return totals[i].Category < totals[j].Category
+	})
+	return totals, nil
+}
+
+func (cr *ChartRepository) GetTopCounterparties(c context.Context, userId int, r models.ChartRange, limit int) ([]models.CounterpartyTotal, error) {
+	cr.s.mu.Lock()
+	defer cr.s.mu.Unlock()
+
+	totals := []models.CounterpartyTotal{}
+	index := map[int]int{}
+	for _, t := range cr.spending(userId, r) {
+		receiver := cr.s.wallets[t.receiverWalletID].userID
+		i, ok := index[receiver]
+		if !ok {
+			i = len(totals)
+			index[receiver] = i
+			total := models.CounterpartyTotal{UserID: receiver, Name: cr.s.fullname(receiver)}
+			if p, ok := cr.s.profiles[receiver]; ok && p.ProfilePicture != nil {
+				total.ProfilePicture = *p.ProfilePicture
+			}
+			totals = append(totals, total)
+		}
+		totals[i].Amount += t.amount
+		totals[i].Count++
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Amount != totals[j].Amount {
+			return totals[i].Amount > totals[j].Amount
+		}
+		return totals[i].UserID < totals[j].UserID
+	})
+	if len(totals) > limit {
+		totals = totals[:limit]
+	}
+	return totals, nil
+}
@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/redis/go-redis/v9"
+)
+
+// same size as default channel buffer of redis pubsub client
+const subscriptionBuffer = 100
+
+type subscription struct {
+	s      *Store
+	userID int
+	ch     chan *redis.Message
+	once   sync.Once
+}
+
+func (sub *subscription) Channel(opts ...redis.ChannelOption) <-chan *redis.Message {
+	return sub.ch
+}
+
+func (sub *subscription) Close() error {
+	sub.once.Do(func() {
+		sub.s.mu.Lock()
+		defer sub.s.mu.Unlock()
+		delete(sub.s.subscribers[sub.userID], sub)
+		close(sub.ch)
+	})
+	return nil
+}
+
+type EventRepository struct {
+	s *Store
+}
+
+func NewEventRepository(s *Store) *EventRepository {
+	return &EventRepository{s: s}
+}
+
+// Subscribe receive event published by transfer and topup of the same store
+func (er *EventRepository) Subscribe(c context.Context, userID int) (repository.Subscription, error) {
+	er.s.mu.Lock()
+	defer er.s.mu.Unlock()
+
+	sub := &subscription{s: er.s, userID: userID, ch: make(chan *redis.Message, subscriptionBuffer)}
+	if er.s.subscribers[userID] == nil {
+		er.s.subscribers[userID] = map[*subscription]struct{}{}
+	}
+	er.s.subscribers[userID][sub] = struct{}{}
+	return sub, nil
+}
@@ -0,0 +1,27 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+)
+
+type EWalletRepository struct {
+	s *Store
+}
+
+func NewEWalletRepository(s *Store) *EWalletRepository {
+	return &EWalletRepository{s: s}
+}
+
+func (er *EWalletRepository) GetBalance(c context.Context, userId int) (*models.Balance, error) {
+	er.s.mu.Lock()
+	defer er.s.mu.Unlock()
+
+	w := er.s.walletOfUser(userId)
+	if w == nil {
+		return nil, repository.ErrUserNotFound
+	}
+	return &models.Balance{User_id: userId, Balance: w.balance}, nil
+}
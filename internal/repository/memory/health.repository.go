@@ -0,0 +1,27 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Belalai-E-Wallet-Backend/db"
+)
+
+// HealthRepository is always healthy and report schema is migrated to the latest version
+type HealthRepository struct{}
+
+func NewHealthRepository() *HealthRepository {
+	return &HealthRepository{}
+}
+
+func (hr *HealthRepository) PingDB(c context.Context) error {
+	return nil
+}
+
+func (hr *HealthRepository) PingRedis(c context.Context) error {
+	return nil
+}
+
+func (hr *HealthRepository) MigrationVersion(c context.Context) (uint, bool, error) {
+	version, err := db.LatestVersion()
+	return version, false, err
+}
@@ -0,0 +1,18 @@
+package memory
+
+import "github.com/Belalai-E-Wallet-Backend/internal/repository"
+
+// make sure in-memory implementation keep satisfying the interfaces
+var (
+	_ repository.AuthRepo        = (*AuthRepository)(nil)
+	_ repository.TransferRepo    = (*TransferRepository)(nil)
+	_ repository.EWalletRepo     = (*EWalletRepository)(nil)
+	_ repository.TransactionRepo = (*TransactionRepository)(nil)
+	_ repository.ProfileRepo     = (*ProfileRepository)(nil)
+	_ repository.TopUpRepo       = (*TopUpRepository)(nil)
+	_ repository.ChartRepo       = (*ChartRepository)(nil)
+	_ repository.CategoryRepo    = (*CategoryRepository)(nil)
+	_ repository.BudgetRepo      = (*BudgetRepository)(nil)
+	_ repository.EventRepo       = (*EventRepository)(nil)
+	_ repository.HealthRepo      = (*HealthRepository)(nil)
+)
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+)
+
+type ProfileRepository struct {
+	s *Store
+}
+
+func NewProfileRepository(s *Store) *ProfileRepository {
+	return &ProfileRepository{s: s}
+}
+
+func (pr *ProfileRepository) GetProfile(c context.Context, userId int) (*models.Profile, error) {
+	pr.s.mu.Lock()
+	defer pr.s.mu.Unlock()
+
+	p, ok := pr.s.profiles[userId]
+	user, userOk := pr.s.users[userId]
+	if !ok || !userOk {
+		return nil, repository.ErrProfileNotFound
+	}
+	profile := *p
+	email := user.Email
+	profile.Email = &email
+	return &profile, nil
+}
+
+// only field which isn't nil is updated, like the postgres implementation
+func (pr *ProfileRepository) UpdateProfile(c context.Context, profile *models.Profile) error {
+	pr.s.mu.Lock()
+	defer pr.s.mu.Unlock()
+
+	now := pr.s.now()
+	if user, ok := pr.s.users[profile.UserID]; ok && profile.Email != nil {
+		user.Email = *profile.Email
+		user.UpdatedAt = &now
+	}
+
+	p, ok := pr.s.profiles[profile.UserID]
+	if !ok {
+		return nil
+	}
+	if profile.Fullname != nil {
+		p.Fullname = profile.Fullname
+	}
+	if profile.Phone != nil {
+		p.Phone = profile.Phone
+	}
+	if profile.ProfilePicture != nil {
+		p.ProfilePicture = profile.ProfilePicture
+	}
+	if profile.Language != nil {
+		p.Language = profile.Language
+		pr.s.set(utils.UserLanguageKey(profile.UserID), *profile.Language, 0)
+	}
+	p.UpdatedAt = &now
+	return nil
+}
+
+func (pr *ProfileRepository) DeleteAvatar(c context.Context, userId int) error {
+	pr.s.mu.Lock()
+	defer pr.s.mu.Unlock()
+
+	if p, ok := pr.s.profiles[userId]; ok {
+		now := pr.s.now()
+		p.ProfilePicture = nil
+		p.UpdatedAt = &now
+	}
+	return nil
+}
@@ -0,0 +1,377 @@
+// Package memory is in-memory implementation of repository interfaces,
+// it behave like the postgres/redis implementation (same sentinel error) and is used by handler tests
+package memory
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+type wallet struct {
+	id      int
+	userID  int
+	balance int
+}
+
+type transfer struct {
+	id                int
+	senderWalletID    int
+	receiverWalletID  int
+	amount            int
+	status            string
+	notes             string
+	createdAt         time.Time
+	deletedBySender   bool
+	deletedByReceiver bool
+}
+
+type topup struct {
+	models.TopUp
+	walletID  int
+	deletedAt *time.Time
+}
+
+// ownerKey is transaction of its owner, primary key of transaction_trash & transaction_category
+type ownerKey struct {
+	userID int
+	kind   string
+	id     int
+}
+
+// categoryRule is row of category_rule, userID 0 is default rule
+type categoryRule struct {
+	models.CategoryRule
+	userID int
+}
+
+// defaultCategoryRules is the same as rule inserted by migration 000014, as field, keyword, category
+var defaultCategoryRules = [][3]string{
+	{"notes", "makan", "food"}, {"notes", "food", "food"}, {"notes", "kopi", "food"}, {"notes", "coffee", "food"},
+	{"notes", "resto", "food"}, {"notes", "warung", "food"},
+	{"notes", "gojek", "transport"}, {"notes", "grab", "transport"}, {"notes", "ojek", "transport"}, {"notes", "bensin", "transport"},
+	{"notes", "parkir", "transport"}, {"notes", "taksi", "transport"}, {"notes", "kereta", "transport"},
+	{"notes", "belanja", "shopping"}, {"notes", "tokopedia", "shopping"}, {"notes", "shopee", "shopping"}, {"notes", "baju", "shopping"},
+	{"notes", "listrik", "bills"}, {"notes", "pulsa", "bills"}, {"notes", "internet", "bills"}, {"notes", "wifi", "bills"},
+	{"notes", "tagihan", "bills"}, {"notes", "bpjs", "bills"},
+	{"notes", "netflix", "entertainment"}, {"notes", "spotify", "entertainment"}, {"notes", "bioskop", "entertainment"},
+	{"notes", "konser", "entertainment"}, {"notes", "game", "entertainment"},
+	{"notes", "obat", "health"}, {"notes", "dokter", "health"}, {"notes", "apotek", "health"}, {"notes", "klinik", "health"},
+	{"notes", "sekolah", "education"}, {"notes", "kuliah", "education"}, {"notes", "spp", "education"}, {"notes", "kursus", "education"},
+	{"notes", "keluarga", "family"}, {"notes", "ortu", "family"}, {"notes", "arisan", "family"},
+}
+
+// budget is row of budget table
+type budget struct {
+	models.Budget
+	userID int
+}
+
+// budgetAlertKey is primary key of budget_alert
+type budgetAlertKey struct {
+	budgetID  int
+	month     string
+	threshold int
+}
+
+type kvEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// Store hold all data shared by in-memory repositories, like one database + redis
+type Store struct {
+	mu sync.Mutex
+
+	// now is used as created_at, can be replaced on test to get deterministic order
+	now func() time.Time
+
+	seq            map[string]int
+	users          map[int]*models.User
+	profiles       map[int]*models.Profile
+	wallets        map[int]*wallet
+	transfers      []*transfer
+	topups         []*topup
+	paymentMethods []models.PaymentMethod
+	// status timeline of transaction keyed by kind:id, like transaction_status_history
+	statusHistory map[string][]models.TransactionStatusEvent
+	// hidden transaction which can be restored, value is when it is hidden
+	trash map[ownerKey]time.Time
+	// category picked by user & keyword rule, oldest rule first
+	categories    map[ownerKey]string
+	categoryRules []categoryRule
+	budgets       []*budget
+	budgetAlerts  map[budgetAlertKey]struct{}
+	kv            map[string]kvEntry
+	subscribers   map[int]map[*subscription]struct{}
+}
+
+func NewStore() *Store {
+	s := &Store{
+		now:           time.Now,
+		seq:           map[string]int{},
+		users:         map[int]*models.User{},
+		profiles:      map[int]*models.Profile{},
+		wallets:       map[int]*wallet{},
+		statusHistory: map[string][]models.TransactionStatusEvent{},
+		trash:         map[ownerKey]time.Time{},
+		categories:    map[ownerKey]string{},
+		budgetAlerts:  map[budgetAlertKey]struct{}{},
+		kv:            map[string]kvEntry{},
+		subscribers:   map[int]map[*subscription]struct{}{},
+	}
+	for _, r := range defaultCategoryRules {
+		s.categoryRules = append(s.categoryRules, categoryRule{CategoryRule: models.CategoryRule{
+			ID: s.nextID("category_rule"), Field: r[0], Keyword: r[1], Category: r[2], CreatedAt: s.now(),
+		}})
+	}
+	return s
+}
+
+// SetClock replace time used as created_at of new data
+func (s *Store) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// AddUser insert user with its profile and wallet, used to prepare test data
+// password and pin must be already hashed, pin can be empty when user hasn't set it
+func (s *Store) AddUser(email, hashedPassword, hashedPin string, balance int) (userID, walletID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := &models.User{Email: email, Password: hashedPassword}
+	if hashedPin != "" {
+		user.Pin = &hashedPin
+	}
+	s.insertUser(user)
+	w := s.walletOfUser(user.ID)
+	w.balance = balance
+	return user.ID, w.id
+}
+
+// AddPaymentMethod insert payment method and return its id
+func (s *Store) AddPaymentMethod(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextID("payment_method")
+	s.paymentMethods = append(s.paymentMethods, models.PaymentMethod{ID: id, Name: name})
+	return id
+}
+
+// IsBlacklisted report whether token is blacklisted by logout
+func (s *Store) IsBlacklisted(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.get(blacklistKey(token))
+	return ok
+}
+
+// recordStatus append status to timeline of transaction like the trigger on postgres,
+// status which doesn't change isn't recorded, caller must hold the lock
+func (s *Store) recordStatus(kind string, id int, status string, at time.Time) {
+	key := kind + ":" + strconv.Itoa(id)
+	timeline := s.statusHistory[key]
+	if len(timeline) > 0 && timeline[len(timeline)-1].Status == status {
+		return
+	}
+	s.statusHistory[key] = append(timeline, models.TransactionStatusEvent{Status: status, CreatedAt: at})
+}
+
+// category of transaction for its owner like categorize function of migration 000014:
+// picked by user, topup & received transfer by type, sent transfer by the first matching rule (rule of user first)
+func (s *Store) category(userID int, kind string, id int, txType, notes, counterparty string) string {
+	if category, ok := s.categories[ownerKey{userID, kind, id}]; ok {
+		return category
+	}
+	switch txType {
+	case "Topup":
+		return "topup"
+	case "Transfer":
+		return "income"
+	}
+	for _, owner := range []int{userID, 0} {
+		for _, r := range s.categoryRules {
+			field := notes
+			if r.Field == "counterparty" {
+				field = counterparty
+			}
+			if r.userID == owner && strings.Contains(strings.ToLower(field), r.Keyword) {
+				return r.Category
+			}
+		}
+	}
+	return "other"
+}
+
+func (s *Store) nextID(table string) int {
+	s.seq[table]++
+	return s.seq[table]
+}
+
+// insert user, profile and wallet like CreateAccount transaction, caller must hold the lock
+func (s *Store) insertUser(user *models.User) {
+	now := s.now()
+	user.ID = s.nextID("users")
+	user.CreatedAt = now
+	s.users[user.ID] = user
+
+	// language isn't set on register
+	s.profiles[user.ID] = &models.Profile{UserID: user.ID, CreatedAt: now}
+
+	w := &wallet{id: s.nextID("wallets"), userID: user.ID}
+	s.wallets[w.id] = w
+}
+
+func (s *Store) userByEmail(email string) *models.User {
+	for _, u := range s.users {
+		if u.Email == email {
+			return u
+		}
+	}
+	return nil
+}
+
+func (s *Store) walletOfUser(userID int) *wallet {
+	for _, w := range s.wallets {
+		if w.userID == userID {
+			return w
+		}
+	}
+	return nil
+}
+
+// fullname of user, empty when it isn't set
+func (s *Store) fullname(userID int) string {
+	if p, ok := s.profiles[userID]; ok && p.Fullname != nil {
+		return *p.Fullname
+	}
+	return ""
+}
+
+func (s *Store) paymentMethod(id int) (models.PaymentMethod, bool) {
+	for _, pm := range s.paymentMethods {
+		if pm.ID == id {
+			return pm, true
+		}
+	}
+	return models.PaymentMethod{}, false
+}
+
+// mail recipient of user, fullname and language come from profile like the LEFT JOIN on postgres
+func (s *Store) mailRecipient(user *models.User) *models.MailRecipient {
+	r := &models.MailRecipient{UserID: user.ID, Email: user.Email}
+	if p, ok := s.profiles[user.ID]; ok {
+		if p.Fullname != nil {
+			r.Fullname = *p.Fullname
+		}
+		if p.Language != nil {
+			r.Language = *p.Language
+		}
+	}
+	return r
+}
+
+// get value of redis-like key, expired key is treated as missing
+func (s *Store) get(key string) (string, bool) {
+	e, ok := s.kv[key]
+	if !ok {
+		return "", false
+	}
+	if !e.expiresAt.IsZero() && !s.now().Before(e.expiresAt) {
+		delete(s.kv, key)
+		return "", false
+	}
+	return e.value, true
+}
+
+// ttl 0 mean the key never expire
+func (s *Store) set(key, value string, ttl time.Duration) {
+	e := kvEntry{value: value}
+	if ttl > 0 {
+		e.expiresAt = s.now().Add(ttl)
+	}
+	s.kv[key] = e
+}
+
+func blacklistKey(token string) string {
+	return "Belalai-E-wallet:blacklist:" + token
+}
+
+// same format as TO_CHAR(amount, 'FM999,999,999') on postgres
+func formatAmount(amount int) string {
+	digits := strconv.Itoa(amount)
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return sign + b.String()
+}
+
+// publish event to subscribers of user, caller must hold the lock
+// slow subscriber drop event instead of blocking the writer, like redis pubsub client buffer
+func (s *Store) publish(userID int, eventType models.EventType, data any) {
+	bt, err := json.Marshal(models.UserEvent{Type: eventType, Data: data, CreatedAt: s.now()})
+	if err != nil {
+		return
+	}
+	msg := &redis.Message{Channel: utils.UserEventChannel(userID), Payload: string(bt)}
+	for sub := range s.subscribers[userID] {
+		select {
+		case sub.ch <- msg:
+		default:
+		}
+	}
+}
+
+// sort history like ORDER BY created_at DESC, id DESC
+func sortHistory(histories []models.TransactionHistory) {
+	sort.SliceStable(histories, func(i, j int) bool {
+		if !histories[i].CreatedAt.Equal(histories[j].CreatedAt) {
+			return histories[i].CreatedAt.After(histories[j].CreatedAt)
+		}
+		return histories[i].ID > histories[j].ID
+	})
+}
+
+// afterCursor filter rows sorted newest first to what keyset query return: limit+1 rows after (or before) cursor
+func afterCursor[T any](rows []T, cursor *models.Cursor, limit int, cursorOf func(T) models.Cursor) []T {
+	var result []T
+	if cursor == nil {
+		return rows[:min(len(rows), limit+1)]
+	}
+	if cursor.Backward {
+		for i := len(rows) - 1; i >= 0 && len(result) <= limit; i-- {
+			if utils.CompareCursor(cursorOf(rows[i]), *cursor) < 0 {
+				result = append(result, rows[i])
+			}
+		}
+		return result
+	}
+	for _, row := range rows {
+		if len(result) > limit {
+			break
+		}
+		if utils.CompareCursor(cursorOf(row), *cursor) > 0 {
+			result = append(result, row)
+		}
+	}
+	return result
+}
@@ -0,0 +1,546 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+)
+
+type TransactionRepository struct {
+	s *Store
+}
+
+func NewTransactionRepository(s *Store) *TransactionRepository {
+	return &TransactionRepository{s: s}
+}
+
+// transfer of user which is (or isn't) soft deleted by the user, sorted by created_at desc
+func (tr *TransactionRepository) transferHistory(userID int, deleted bool) []models.TransactionHistory {
+	w := tr.s.walletOfUser(userID)
+	if w == nil {
+		return nil
+	}
+
+	var histories []models.TransactionHistory
+	for _, t := range tr.s.transfers {
+		var counterpartyWallet int
+		history := models.TransactionHistory{
+			ID:             t.id,
+			Kind:           "transfer",
+			Amount:         "Rp " + formatAmount(t.amount),
+			OriginalAmount: t.amount,
+			Status:         t.status,
+			Notes:          t.notes,
+			CreatedAt:      t.createdAt,
+		}
+		switch {
+		case t.senderWalletID == w.id:
+			if t.deletedBySender != deleted {
+				continue
+			}
+			history.Type = "Send"
+			counterpartyWallet = t.receiverWalletID
+		case t.receiverWalletID == w.id:
+			if t.deletedByReceiver != deleted {
+				continue
+			}
+			history.Type = "Transfer"
+			counterpartyWallet = t.senderWalletID
+		default:
+			continue
+		}
+
+		history.ContactName, history.PhoneNumber = "Unknown", "Unknown"
+		if cw, ok := tr.s.wallets[counterpartyWallet]; ok {
+			history.CounterpartyID = cw.userID
+			if p, ok := tr.s.profiles[cw.userID]; ok {
+				if p.ProfilePicture != nil {
+					history.ProfilePicture = *p.ProfilePicture
+				}
+				if p.Fullname != nil {
+					history.ContactName = *p.Fullname
+				}
+				if p.Phone != nil {
+					history.PhoneNumber = *p.Phone
+				}
+			}
+		}
+		history.Category = tr.s.category(userID, "transfer", t.id, history.Type, t.notes, tr.s.fullname(history.CounterpartyID))
+		histories = append(histories, history)
+	}
+	sortHistory(histories)
+	return histories
+}
+
+func (tr *TransactionRepository) GetHistory(c context.Context, userID int, filter models.HistoryFilter, offset, limit int) ([]models.TransactionHistory, error) {
+	filter.Kind = "transfer"
+	return tr.GetAllHistory(c, userID, filter, limit, offset)
+}
+
+func (tr *TransactionRepository) GetHistoryCount(c context.Context, userID int, filter models.HistoryFilter) (int, error) {
+	filter.Kind = "transfer"
+	return tr.GetAllHistoryCount(c, userID, filter)
+}
+
+func (tr *TransactionRepository) SoftDeleteTransaction(c context.Context, transactionID, userID int) error {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	w := tr.s.walletOfUser(userID)
+	for _, t := range tr.s.transfers {
+		if t.id != transactionID {
+			continue
+		}
+		if w == nil || (t.senderWalletID != w.id && t.receiverWalletID != w.id) {
+			return repository.ErrTransactionNotFound
+		}
+		tr.hide(userID, models.TransactionRef{Kind: "transfer", ID: transactionID})
+		return nil
+	}
+	return repository.ErrTransactionNotFound
+}
+
+func (tr *TransactionRepository) SoftDeleteTopup(c context.Context, topupID, userID int) error {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	for _, t := range tr.s.topups {
+		if t.ID != topupID || t.walletID == 0 || t.deletedAt != nil {
+			continue
+		}
+		if w, ok := tr.s.wallets[t.walletID]; !ok || w.userID != userID {
+			return repository.ErrTopUpNotOwned
+		}
+		tr.hide(userID, models.TransactionRef{Kind: "topup", ID: topupID})
+		return nil
+	}
+	return repository.ErrTopUpNotFound
+}
+
+// hide set hidden flag of transaction for user and put it into trash like hideSQL,
+// report false when it isn't user's or is already hidden, caller must hold the lock
+func (tr *TransactionRepository) hide(userID int, ref models.TransactionRef) bool {
+	w := tr.s.walletOfUser(userID)
+	if w == nil {
+		return false
+	}
+	now := tr.s.now()
+	switch ref.Kind {
+	case "transfer":
+		i := slices.IndexFunc(tr.s.transfers, func(t *transfer) bool { return t.id == ref.ID })
+		if i < 0 {
+			return false
+		}
+		t := tr.s.transfers[i]
+		switch {
+		case t.senderWalletID == w.id && !t.deletedBySender:
+			t.deletedBySender = true
+		case t.receiverWalletID == w.id && !t.deletedByReceiver:
+			t.deletedByReceiver = true
+		default:
+			return false
+		}
+	case "topup":
+		i := slices.IndexFunc(tr.s.topups, func(t *topup) bool { return t.ID == ref.ID && t.walletID == w.id })
+		if i < 0 || tr.s.topups[i].deletedAt != nil {
+			return false
+		}
+		tr.s.topups[i].deletedAt = &now
+	default:
+		return false
+	}
+	tr.s.trash[ownerKey{userID, ref.Kind, ref.ID}] = now
+	return true
+}
+
+// restore take transaction out of trash and clear its hidden flag like restoreSQL,
+// report false when it isn't in trash, caller must hold the lock
+func (tr *TransactionRepository) restore(userID int, ref models.TransactionRef) bool {
+	key := ownerKey{userID, ref.Kind, ref.ID}
+	if _, ok := tr.s.trash[key]; !ok {
+		return false
+	}
+	delete(tr.s.trash, key)
+
+	w := tr.s.walletOfUser(userID)
+	for _, t := range tr.s.transfers {
+		if ref.Kind == "transfer" && t.id == ref.ID {
+			t.deletedBySender = t.deletedBySender && t.senderWalletID != w.id
+			t.deletedByReceiver = t.deletedByReceiver && t.receiverWalletID != w.id
+		}
+	}
+	for _, t := range tr.s.topups {
+		if ref.Kind == "topup" && t.ID == ref.ID {
+			t.deletedAt = nil
+		}
+	}
+	return true
+}
+
+func (tr *TransactionRepository) HideTransactions(c context.Context, userID int, refs []models.TransactionRef) ([]models.TransactionRef, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	var updated []models.TransactionRef
+	for _, ref := range refs {
+		if tr.hide(userID, ref) {
+			updated = append(updated, ref)
+		}
+	}
+	return updated, nil
+}
+
+func (tr *TransactionRepository) RestoreTransactions(c context.Context, userID int, refs []models.TransactionRef) ([]models.TransactionRef, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	var updated []models.TransactionRef
+	for _, ref := range refs {
+		if tr.restore(userID, ref) {
+			updated = append(updated, ref)
+		}
+	}
+	return updated, nil
+}
+
+// trash is hidden transaction of user still in trash, last hidden first
+func (tr *TransactionRepository) trash(userID int) []models.TrashItem {
+	var items []models.TrashItem
+	for _, h := range append(tr.transferHistory(userID, true), tr.topupHistory(userID, true)...) {
+		if deletedAt, ok := tr.s.trash[ownerKey{userID, h.Kind, h.ID}]; ok {
+			items = append(items, models.TrashItem{TransactionHistory: h, DeletedAt: deletedAt})
+		}
+	}
+	slices.SortStableFunc(items, func(a, b models.TrashItem) int {
+		if c := b.DeletedAt.Compare(a.DeletedAt); c != 0 {
+			return c
+		}
+		if a.ID != b.ID {
+			return b.ID - a.ID
+		}
+		return strings.Compare(b.Kind, a.Kind)
+	})
+	return items
+}
+
+func (tr *TransactionRepository) GetTrash(c context.Context, userID int, offset, limit int) ([]models.TrashItem, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	items := tr.trash(userID)
+	start, end := min(offset, len(items)), min(offset+limit, len(items))
+	return items[start:end], nil
+}
+
+func (tr *TransactionRepository) GetTrashCount(c context.Context, userID int) (int, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	return len(tr.trash(userID)), nil
+}
+
+func (tr *TransactionRepository) PurgeTrash(c context.Context, retention time.Duration) (int64, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	before := tr.s.now().Add(-retention)
+	var purged int64
+	for key, deletedAt := range tr.s.trash {
+		if deletedAt.Before(before) {
+			delete(tr.s.trash, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (tr *TransactionRepository) topupHistory(userID int, deleted bool) []models.TransactionHistory {
+	w := tr.s.walletOfUser(userID)
+	if w == nil {
+		return nil
+	}
+
+	var histories []models.TransactionHistory
+	for _, t := range tr.s.topups {
+		if t.walletID != w.id || (t.deletedAt != nil) != deleted {
+			continue
+		}
+		pm, _ := tr.s.paymentMethod(t.PaymentID)
+		histories = append(histories, models.TransactionHistory{
+			ID:             t.ID,
+			Kind:           "topup",
+			Type:           "Topup",
+			ContactName:    pm.Name,
+			Amount:         "+Rp " + formatAmount(t.Amount),
+			OriginalAmount: t.Amount,
+			Status:         string(t.Status),
+			Notes:          "Tax: Rp " + formatAmount(t.Tax),
+			Category:       tr.s.category(userID, "topup", t.ID, "Topup", "", ""),
+			CreatedAt:      t.CreatedAt,
+		})
+	}
+	sortHistory(histories)
+	return histories
+}
+
+func (tr *TransactionRepository) SetCategory(c context.Context, userID int, ref models.TransactionRef, category string) (string, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	// find history of ref, hidden transaction is still owned
+	find := func() (models.TransactionHistory, bool) {
+		for _, deleted := range []bool{false, true} {
+			histories := tr.transferHistory(userID, deleted)
+			if ref.Kind == "topup" {
+				histories = tr.topupHistory(userID, deleted)
+			}
+			if i := slices.IndexFunc(histories, func(h models.TransactionHistory) bool { return h.ID == ref.ID }); i >= 0 {
+				return histories[i], true
+			}
+		}
+		return models.TransactionHistory{}, false
+	}
+	if _, ok := find(); !ok {
+		if ref.Kind == "topup" {
+			return "", repository.ErrTopUpNotFound
+		}
+		return "", repository.ErrTransactionNotFound
+	}
+
+	key := ownerKey{userID, ref.Kind, ref.ID}
+	if category == "" {
+		delete(tr.s.categories, key)
+	} else {
+		tr.s.categories[key] = category
+	}
+	h, _ := find()
+	return h.Category, nil
+}
+
+func (tr *TransactionRepository) GetTopupHistory(c context.Context, userID int) ([]models.TransactionHistory, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	return tr.topupHistory(userID, false), nil
+}
+
+// filteredHistory is history of user matching filter, sorted like transactions view
+func (tr *TransactionRepository) filteredHistory(userID int, f models.HistoryFilter) []models.TransactionHistory {
+	var histories []models.TransactionHistory
+	if f.Kind == "" || f.Kind == "transfer" {
+		histories = append(histories, tr.transferHistory(userID, false)...)
+	}
+	if f.Kind == "" || f.Kind == "topup" {
+		histories = append(histories, tr.topupHistory(userID, false)...)
+	}
+	histories = slices.DeleteFunc(histories, func(h models.TransactionHistory) bool { return !matchHistory(h, f) })
+	sortHistory(histories)
+	return histories
+}
+
+// matchHistory is WHERE of historyWhere on postgres repository
+func matchHistory(h models.TransactionHistory, f models.HistoryFilter) bool {
+	if f.From != nil && h.CreatedAt.Before(*f.From) {
+		return false
+	}
+	if f.To != nil && !h.CreatedAt.Before(f.To.AddDate(0, 0, 1)) {
+		return false
+	}
+	if len(f.Types) > 0 && !slices.ContainsFunc(f.Types, func(typ string) bool { return models.HistoryTypes[typ] == h.Type }) {
+		return false
+	}
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, h.Status) {
+		return false
+	}
+	if (f.MinAmount > 0 && h.OriginalAmount < f.MinAmount) || (f.MaxAmount > 0 && h.OriginalAmount > f.MaxAmount) {
+		return false
+	}
+	if f.Counterparty > 0 && h.CounterpartyID != f.Counterparty {
+		return false
+	}
+	if search := strings.ToLower(strings.TrimSpace(f.Search)); search != "" {
+		// notes of topup is the tax shown on history, not searchable
+		notes := h.Notes
+		if h.Kind == "topup" {
+			notes = ""
+		}
+		if !strings.Contains(strings.ToLower(notes), search) && !strings.Contains(strings.ToLower(h.ContactName), search) {
+			return false
+		}
+	}
+	return true
+}
+
+func (tr *TransactionRepository) GetAllHistory(c context.Context, userID int, filter models.HistoryFilter, limit int, offset int) ([]models.TransactionHistory, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	histories := tr.filteredHistory(userID, filter)
+	start, end := min(offset, len(histories)), min(offset+limit, len(histories))
+	if start >= end {
+		return nil, repository.ErrNoTransactions
+	}
+	return histories[start:end], nil
+}
+
+func (tr *TransactionRepository) GetAllHistoryCount(c context.Context, userID int, filter models.HistoryFilter) (int, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	return len(tr.filteredHistory(userID, filter)), nil
+}
+
+func (tr *TransactionRepository) GetHistoryByCursor(c context.Context, userID int, filter models.HistoryFilter, cursor *models.Cursor, limit int) (models.CursorPage[models.TransactionHistory], error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	rows := afterCursor(tr.filteredHistory(userID, filter), cursor, limit, utils.HistoryCursor)
+	return utils.CursorPageOf(rows, cursor, limit, utils.HistoryCursor), nil
+}
+
+// party is user of wallet with its profile, caller must hold the lock
+func (tr *TransactionRepository) party(walletID int) models.TransactionParty {
+	party := models.TransactionParty{WalletID: walletID}
+	w, ok := tr.s.wallets[walletID]
+	if !ok {
+		return party
+	}
+	party.UserID = w.userID
+	if p, ok := tr.s.profiles[w.userID]; ok {
+		if p.Fullname != nil {
+			party.Name = *p.Fullname
+		}
+		if p.Phone != nil {
+			party.Phone = *p.Phone
+		}
+		if p.ProfilePicture != nil {
+			party.ProfilePicture = *p.ProfilePicture
+		}
+	}
+	return party
+}
+
+func (tr *TransactionRepository) GetTransactionDetail(c context.Context, kind string, id int) (*models.TransactionDetail, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	detail := &models.TransactionDetail{ID: id, Kind: kind}
+	switch kind {
+	case "transfer":
+		i := slices.IndexFunc(tr.s.transfers, func(t *transfer) bool { return t.id == id })
+		if i < 0 {
+			return nil, repository.ErrTransactionNotFound
+		}
+		t := tr.s.transfers[i]
+		detail.Amount, detail.Status, detail.Notes, detail.CreatedAt = t.amount, t.status, t.notes, t.createdAt
+		detail.Sender, detail.Receiver = tr.party(t.senderWalletID), tr.party(t.receiverWalletID)
+	case "topup":
+		i := slices.IndexFunc(tr.s.topups, func(t *topup) bool { return t.ID == id && t.walletID != 0 })
+		if i < 0 {
+			return nil, repository.ErrTopUpNotFound
+		}
+		t := tr.s.topups[i]
+		pm, _ := tr.s.paymentMethod(t.PaymentID)
+		detail.Amount, detail.Fee, detail.Status = t.Amount, t.Tax, string(t.Status)
+		detail.CreatedAt, detail.UpdatedAt = t.CreatedAt, t.UpdatedAt
+		detail.Sender = models.TransactionParty{Name: pm.Name}
+		detail.Receiver = tr.party(t.walletID)
+	default:
+		return nil, fmt.Errorf("unknown transaction kind %q", kind)
+	}
+	detail.Total = detail.Amount + detail.Fee
+	detail.Timeline = slices.Clone(tr.s.statusHistory[kind+":"+strconv.Itoa(id)])
+	return detail, nil
+}
+
+// statement is success transaction of user (soft deleted included), oldest first
+func (tr *TransactionRepository) statement(userID int) []models.StatementEntry {
+	w := tr.s.walletOfUser(userID)
+	if w == nil {
+		return nil
+	}
+
+	var entries []models.StatementEntry
+	for _, t := range tr.s.transfers {
+		if t.status != "success" {
+			continue
+		}
+		e := models.StatementEntry{ID: t.id, Kind: "transfer", Notes: t.notes, CreatedAt: t.createdAt}
+		switch w.id {
+		case t.senderWalletID:
+			e.Type, e.Debit, e.Counterparty = "Send", t.amount, tr.party(t.receiverWalletID).Name
+		case t.receiverWalletID:
+			e.Type, e.Credit, e.Counterparty = "Transfer", t.amount, tr.party(t.senderWalletID).Name
+		default:
+			continue
+		}
+		entries = append(entries, e)
+	}
+	for _, t := range tr.s.topups {
+		if t.walletID != w.id || t.Status != models.TopUpSuccess {
+			continue
+		}
+		pm, _ := tr.s.paymentMethod(t.PaymentID)
+		entries = append(entries, models.StatementEntry{
+			ID: t.ID, Kind: "topup", Type: "Topup", Counterparty: pm.Name, Credit: t.Amount, CreatedAt: t.CreatedAt,
+		})
+	}
+	slices.SortStableFunc(entries, func(a, b models.StatementEntry) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Kind, b.Kind); c != 0 {
+			return c
+		}
+		return a.ID - b.ID
+	})
+	return entries
+}
+
+func (tr *TransactionRepository) GetOpeningBalance(c context.Context, userID int, before time.Time) (int, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	var balance int
+	for _, e := range tr.statement(userID) {
+		if e.CreatedAt.Before(before) {
+			balance += e.Credit - e.Debit
+		}
+	}
+	return balance, nil
+}
+
+func (tr *TransactionRepository) StreamStatement(c context.Context, userID int, from, to time.Time, fn func(models.StatementEntry) error) error {
+	tr.s.mu.Lock()
+	entries := tr.statement(userID)
+	tr.s.mu.Unlock()
+
+	// fn may be slow (writing response), so it is called without the lock
+	for _, e := range entries {
+		if e.CreatedAt.Before(from) || !e.CreatedAt.Before(to) {
+			continue
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tr *TransactionRepository) GetMailRecipient(c context.Context, userID int) (*models.MailRecipient, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	user, ok := tr.s.users[userID]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	return tr.s.mailRecipient(user), nil
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+)
+
+type TransferRepository struct {
+	s *Store
+}
+
+func NewTransferRepository(s *Store) *TransferRepository {
+	return &TransferRepository{s: s}
+}
+
+// user matching query sorted like ORDER BY created_at DESC, user_id DESC
+func (tr *TransferRepository) searchUser(query string) []models.ProfileResponse {
+	query = strings.ToLower(query)
+	contains := func(v *string) bool {
+		return v != nil && strings.Contains(strings.ToLower(*v), query)
+	}
+
+	var matched []models.ProfileResponse
+	for _, p := range tr.s.profiles {
+		if query != "" && !contains(p.Fullname) && !contains(p.Phone) {
+			continue
+		}
+		createdAt := p.CreatedAt
+		matched = append(matched, models.ProfileResponse{
+			UserID:         p.UserID,
+			ProfilePicture: p.ProfilePicture,
+			Fullname:       p.Fullname,
+			Phone:          p.Phone,
+			CreatedAt:      &createdAt,
+		})
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return utils.CompareCursor(utils.ProfileCursor(matched[i]), utils.ProfileCursor(matched[j])) < 0
+	})
+	return matched
+}
+
+// filter user by name or phone number (case insensitive like ILIKE)
+func (tr *TransferRepository) FilterUser(c context.Context, query string, offset, limit, page int) (models.ListprofileResponse, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	matched := tr.searchUser(query)
+	total := len(matched)
+	start, end := min(offset, total), min(offset+limit, total)
+	var users []models.ProfileResponse
+	if start < end {
+		users = matched[start:end]
+	}
+	return models.ListprofileResponse{
+		Users:     users,
+		Page:      page,
+		Limit:     limit,
+		TotalUser: total,
+		TotalPage: int(math.Ceil(float64(total) / float64(limit))),
+	}, nil
+}
+
+func (tr *TransferRepository) SearchUserByCursor(c context.Context, query string, cursor *models.Cursor, limit int) (models.CursorPage[models.ProfileResponse], error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	rows := afterCursor(tr.searchUser(query), cursor, limit, utils.ProfileCursor)
+	return utils.CursorPageOf(rows, cursor, limit, utils.ProfileCursor), nil
+}
+
+func (tr *TransferRepository) GetHashedPin(c context.Context, senderId int) (models.UserPin, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	user, ok := tr.s.users[senderId]
+	if !ok || user.Pin == nil {
+		return models.UserPin{}, errors.New("failed get pin user")
+	}
+	return models.UserPin{Id: user.ID, Pin: *user.Pin}, nil
+}
+
+func (tr *TransferRepository) TransferMoney(c context.Context, senderId int, body models.TransferBody) (int, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	sender := tr.s.walletOfUser(senderId)
+	if sender == nil {
+		return 0, errors.New("user invalid or wrong pin inputs")
+	}
+	if sender.id == body.IdReceiver {
+		return 0, repository.ErrCantSendingToYourself
+	}
+	if sender.balance < body.Amount {
+		return 0, repository.ErrNotEnoughBalance
+	}
+	receiver, ok := tr.s.wallets[body.IdReceiver]
+	if !ok {
+		return 0, errors.New("no row effected when UPDATE wallets maybe failed?")
+	}
+
+	now := tr.s.now()
+	sender.balance -= body.Amount
+	receiver.balance += body.Amount
+	t := &transfer{
+		id:               tr.s.nextID("transfer"),
+		senderWalletID:   sender.id,
+		receiverWalletID: receiver.id,
+		amount:           body.Amount,
+		status:           "success",
+		notes:            body.Notes,
+		createdAt:        now,
+	}
+	tr.s.transfers = append(tr.s.transfers, t)
+	tr.s.recordStatus("transfer", t.id, t.status, now)
+
+	trx := models.TransactionEvent{ID: t.id, Amount: t.amount, Status: t.status, Notes: t.notes, CreatedAt: now}
+	sent, received := trx, trx
+	sent.Type, sent.CounterpartyID = "Send", receiver.userID
+	received.Type, received.CounterpartyID = "Transfer", senderId
+	tr.s.publish(senderId, models.EventBalanceUpdated, models.BalanceEvent{Balance: sender.balance})
+	tr.s.publish(senderId, models.EventTransactionCreated, sent)
+	tr.s.publish(receiver.userID, models.EventBalanceUpdated, models.BalanceEvent{Balance: receiver.balance})
+	tr.s.publish(receiver.userID, models.EventTransactionCreated, received)
+
+	return t.id, nil
+}
+
+func (tr *TransferRepository) GetMailRecipients(c context.Context, senderId, receiverWalletId int) (*models.MailRecipient, *models.MailRecipient, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	sender, ok := tr.s.users[senderId]
+	if !ok {
+		return nil, nil, repository.ErrUserNotFound
+	}
+	w, ok := tr.s.wallets[receiverWalletId]
+	if !ok {
+		return nil, nil, repository.ErrUserNotFound
+	}
+	return tr.s.mailRecipient(sender), tr.s.mailRecipient(tr.s.users[w.userID]), nil
+}
@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+)
+
+type TopUpRepository struct {
+	s *Store
+}
+
+func NewTopUpRepository(s *Store) *TopUpRepository {
+	return &TopUpRepository{s: s}
+}
+
+// insert topup which isn't applied to any wallet yet, caller must hold the lock
+func (tr *TopUpRepository) insert(data *models.TopUp) (*topup, error) {
+	pm, ok := tr.s.paymentMethod(data.PaymentID)
+	if !ok {
+		return nil, fmt.Errorf("payment method %d not found", data.PaymentID)
+	}
+	data.ID = tr.s.nextID("topup")
+	data.CreatedAt = tr.s.now()
+	data.PaymentMethod = pm.Name
+	t := &topup{TopUp: *data}
+	tr.s.topups = append(tr.s.topups, t)
+	tr.s.recordStatus("topup", t.ID, string(t.Status), t.CreatedAt)
+	return t, nil
+}
+
+func (tr *TopUpRepository) find(topupID int) *topup {
+	for _, t := range tr.s.topups {
+		if t.ID == topupID {
+			return t
+		}
+	}
+	return nil
+}
+
+func (tr *TopUpRepository) CreateTopUp(c context.Context, topup *models.TopUp) (*models.TopUp, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	if _, err := tr.insert(topup); err != nil {
+		return nil, err
+	}
+	return topup, nil
+}
+
+func (tr *TopUpRepository) UpdateStatusTopUp(c context.Context, topupID int, status models.TopUpStatus) error {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	if t := tr.find(topupID); t != nil {
+		now := tr.s.now()
+		t.Status = status
+		t.UpdatedAt = &now
+		tr.s.recordStatus("topup", topupID, string(status), now)
+	}
+	return nil
+}
+
+func (tr *TopUpRepository) GetTopUpByID(c context.Context, topupID int) (*models.TopUp, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	t := tr.find(topupID)
+	if t == nil {
+		return nil, fmt.Errorf("topup %d not found", topupID)
+	}
+	result := t.TopUp
+	result.PaymentMethod = ""
+	return &result, nil
+}
+
+func (tr *TopUpRepository) ApplyToWallet(c context.Context, walletID int, topupID int, amount int) error {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	w, ok := tr.s.wallets[walletID]
+	if !ok {
+		return fmt.Errorf("wallet %d not found", walletID)
+	}
+	t := tr.find(topupID)
+	if t == nil {
+		return fmt.Errorf("topup %d not found", topupID)
+	}
+	t.walletID = walletID
+	w.balance += amount
+	return nil
+}
+
+func (tr *TopUpRepository) GetWalletIDByUserID(c context.Context, userID int) (int, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	w := tr.s.walletOfUser(userID)
+	if w == nil {
+		return 0, fmt.Errorf("wallet of user %d not found", userID)
+	}
+	return w.id, nil
+}
+
+func (tr *TopUpRepository) FindAllPaymentMethods(c context.Context) ([]models.PaymentMethod, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	if len(tr.s.paymentMethods) == 0 {
+		return nil, nil
+	}
+	return append([]models.PaymentMethod(nil), tr.s.paymentMethods...), nil
+}
+
+func (tr *TopUpRepository) CreateTopUpTransaction(c context.Context, topup *models.TopUp, userID int) (*models.TopUp, error) {
+	tr.s.mu.Lock()
+	defer tr.s.mu.Unlock()
+
+	w := tr.s.walletOfUser(userID)
+	if w == nil {
+		return nil, fmt.Errorf("wallet of user %d not found", userID)
+	}
+	topup.Status = models.TopUpSuccess
+	t, err := tr.insert(topup)
+	if err != nil {
+		return nil, err
+	}
+	t.walletID = w.id
+	w.balance += topup.Amount
+
+	tr.s.publish(userID, models.EventBalanceUpdated, models.BalanceEvent{Balance: w.balance})
+	tr.s.publish(userID, models.EventTransactionCreated, models.TransactionEvent{
+		ID:        topup.ID,
+		Type:      "Topup",
+		Amount:    topup.Amount,
+		Status:    string(topup.Status),
+		CreatedAt: topup.CreatedAt,
+	})
+	return topup, nil
+}
@@ -0,0 +1,47 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Belalai-E-Wallet-Backend/db"
+)
+
+// every down file must revert its up file, so the whole schema can be dropped and created again
+func TestMigrationDownUp(t *testing.T) {
+	pool, _ := setup(t)
+	migrator, err := db.NewMigrator(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := context.Background()
+	latest := migrator.Migrations()[len(migrator.Migrations())-1].Version
+
+	reverted, err := migrator.Down(c, len(migrator.Migrations()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reverted) != len(migrator.Migrations()) {
+		t.Errorf("reverted %d migration, want %d", len(reverted), len(migrator.Migrations()))
+	}
+	if version, _, err := migrator.Version(c); err != nil || version != 0 {
+		t.Errorf("version after down = %d (%v), want 0", version, err)
+	}
+
+	if _, err := migrator.Up(c); err != nil {
+		t.Fatal(err)
+	}
+	version, dirty, err := migrator.Version(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != latest || dirty {
+		t.Errorf("version after up = %d dirty %v, want %d", version, dirty, latest)
+	}
+	// nothing left to apply
+	if applied, err := migrator.Up(c); err != nil || len(applied) != 0 {
+		t.Errorf("second up applied %d migration (%v), want 0", len(applied), err)
+	}
+}
@@ -2,11 +2,10 @@ package repository
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"log"
 	"strings"
 
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
 	"github.com/Belalai-E-Wallet-Backend/internal/models"
 	"github.com/Belalai-E-Wallet-Backend/internal/utils"
 	"github.com/jackc/pgx/v5"
@@ -34,6 +33,7 @@ func (pr *ProfileRepository) GetProfile(c context.Context, userId int) (*models.
 				p.fullname,
 				p.phone,
 				u.email,
+				p.language,
 				p.created_at,
 				p.updated_at
 		FROM profile p
@@ -42,9 +42,9 @@ func (pr *ProfileRepository) GetProfile(c context.Context, userId int) (*models.
 	`
 
 	var p models.Profile
-	if err := pr.db.QueryRow(c, sql, userId).Scan(&p.UserID, &p.ProfilePicture, &p.Fullname, &p.Phone, &p.Email, &p.CreatedAt, &p.UpdatedAt); err != nil {
+	if err := pr.db.QueryRow(c, sql, userId).Scan(&p.UserID, &p.ProfilePicture, &p.Fullname, &p.Phone, &p.Email, &p.Language, &p.CreatedAt, &p.UpdatedAt); err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, errors.New("profile not found")
+			return nil, ErrProfileNotFound
 		}
 		return nil, err
 	}
@@ -85,6 +85,11 @@ func (pr *ProfileRepository) UpdateProfile(c context.Context, profile *models.Pr
 		args = append(args, *profile.ProfilePicture)
 		argPos++
 	}
+	if profile.Language != nil {
+		setClauses = append(setClauses, fmt.Sprintf("language = $%d", argPos))
+		args = append(args, *profile.Language)
+		argPos++
+	}
 
 	if len(setClauses) > 0 {
 		setClauses = append(setClauses, "updated_at = now()")
@@ -104,9 +109,14 @@ func (pr *ProfileRepository) UpdateProfile(c context.Context, profile *models.Pr
 
 	// Handle caching after successful profile update
 	if err := utils.HandleUpdateProfileCache(c, *pr.rdb, int64(profile.UserID), profile); err != nil {
-		log.Println("Cache operation warning:", err)
+		logger.FromContext(c).Warn("Cache operation warning", "err", err)
 
 	}
+	if profile.Language != nil {
+		if err := utils.SaveUserLanguage(c, *pr.rdb, profile.UserID, *profile.Language); err != nil {
+			logger.FromContext(c).Warn("Cache operation warning", "err", err)
+		}
+	}
 
 	return nil
 }
@@ -0,0 +1,66 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/Belalai-E-Wallet-Backend/internal/seed"
+)
+
+// seeded balance must match its history, otherwise chart and history don't add up on the demo
+func TestSeed(t *testing.T) {
+	pool, _ := setup(t)
+	c := context.Background()
+	opt := seed.Options{Users: 5, Months: 12, Password: "Belalai123!", PIN: "123456", Seed: 42}
+
+	result, err := seed.Run(c, pool, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Users != 5 || result.TopUps == 0 || result.Transfers == 0 {
+		t.Fatalf("result = %+v, want 5 users with topup and transfer", result)
+	}
+	// seeding again add new users, payment method isn't duplicated
+	if _, err := seed.Run(c, pool, opt); err != nil {
+		t.Fatal(err)
+	}
+
+	var users, methods, mismatch int
+	if err := pool.QueryRow(c, `SELECT COUNT(*) FROM users`).Scan(&users); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.QueryRow(c, `SELECT COUNT(*) FROM payment_method`).Scan(&methods); err != nil {
+		t.Fatal(err)
+	}
+	if users != 10 || methods != 7 {
+		t.Errorf("got %d users and %d payment method, want 10 and 7", users, methods)
+	}
+
+	err = pool.QueryRow(c, `SELECT COUNT(*) FROM wallets w WHERE w.balance < 0 OR w.balance <> (
+		COALESCE((SELECT SUM(t.amount) FROM topup t JOIN wallets_topup wt ON wt.topup_id = t.id WHERE wt.wallets_id = w.id AND t.topup_status = 'success'), 0)
+		+ COALESCE((SELECT SUM(amount) FROM transfer WHERE receiver_wallet_id = w.id), 0)
+		- COALESCE((SELECT SUM(amount) FROM transfer WHERE sender_wallet_id = w.id), 0))`).Scan(&mismatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mismatch != 0 {
+		t.Errorf("%d wallet balance doesn't match its history", mismatch)
+	}
+
+	// history is long enough to paginate
+	var userID int
+	if err := pool.QueryRow(c, `SELECT id FROM users WHERE email = 'seed1@belalai.test'`).Scan(&userID); err != nil {
+		t.Fatal(err)
+	}
+	count, err := repository.NewTransactionRepository(pool).GetHistoryCount(c, userID, models.HistoryFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count <= 10 {
+		t.Errorf("seed1 has %d transfer, want more than a page", count)
+	}
+}
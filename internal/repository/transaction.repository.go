@@ -4,9 +4,14 @@ package repository
 import (
 	"context"
 	"errors"
-	"log"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
 	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -18,66 +23,87 @@ func NewTransactionRepository(db *pgxpool.Pool) *TransactionRepository {
 	return &TransactionRepository{db: db}
 }
 
-func (tr *TransactionRepository) GetHistory(ctx context.Context, userID int, offset, limit int) ([]models.TransactionHistory, error) {
-	sql := `SELECT 
-		t.id,
-		CASE 
-			WHEN w_receiver.user_id = $1 THEN 'Transfer'
-			ELSE 'Send'
-		END as transaction_type,
+// historySelect map row of transactions view to the shape shown on history,
+// counterparty of transfer is the other user, of topup is the payment method
+const historySelect = `SELECT` + historyColumns + historyFrom
 
-		CASE 
-			WHEN w_receiver.user_id = $1 THEN
-				COALESCE(p_sender.profile_picture, '')  
-			ELSE
-				COALESCE(p_receiver.profile_picture, '')  
-		END as profile_picture,
-
-		CASE 
-			WHEN w_receiver.user_id = $1 THEN
-				COALESCE(p_sender.fullname, 'Unknown')  
-			ELSE
-				COALESCE(p_receiver.fullname, 'Unknown')  
-		END as contact_name,
-
-		CASE 
-			WHEN w_receiver.user_id = $1 THEN
-				COALESCE(p_sender.phone, 'Unknown')  
-			ELSE
-				COALESCE(p_receiver.phone, 'Unknown')  
-		END as phone_number,
+const historyColumns = `
+		t.id,
+		t.kind,
+		t.transaction_type,
+		COALESCE(t.counterparty_user_id, 0) AS counterparty_user_id,
+		COALESCE(p.profile_picture, '') AS profile_picture,
+		CASE WHEN t.kind = 'topup' THEN COALESCE(pm.name, 'Unknown') ELSE COALESCE(p.fullname, 'Unknown') END AS contact_name,
+		CASE WHEN t.kind = 'topup' THEN '' ELSE COALESCE(p.phone, 'Unknown') END AS phone_number,
+		CASE WHEN t.kind = 'topup' THEN '+Rp ' ELSE 'Rp ' END || TO_CHAR(t.amount, 'FM999,999,999') AS display_amount,
+		t.amount AS original_amount,
+		COALESCE(t.status, 'pending') AS status,
+		CASE WHEN t.kind = 'topup' THEN CONCAT('Tax: Rp ', TO_CHAR(t.tax, 'FM999,999,999')) ELSE t.notes END AS notes,
+		` + categoryColumn + ` AS category,
+		t.created_at`
+
+// categoryColumn is category of row of transactions view for its owner, it need join of profile p (see historyFrom)
+const categoryColumn = `categorize(t.user_id, t.kind, t.id, t.transaction_type, t.notes, COALESCE(p.fullname, ''))`
+
+const historyFrom = `
+	FROM transactions t
+	LEFT JOIN profile p ON p.user_id = t.counterparty_user_id
+	LEFT JOIN payment_method pm ON pm.id = t.payment_id`
+
+// historyWhere build WHERE of history query for user, ? of a condition become its argument placeholder
+func historyWhere(userID int, f models.HistoryFilter) (string, []any) {
+	where := ` WHERE t.user_id = $1 AND NOT t.deleted`
+	args := []any{userID}
+	and := func(cond string, arg any) {
+		args = append(args, arg)
+		where += " AND " + strings.ReplaceAll(cond, "?", fmt.Sprintf("$%d", len(args)))
+	}
 
-		CASE 
-			WHEN w_receiver.user_id = $1 THEN
-				CONCAT('Rp ', TO_CHAR(t.amount, 'FM999,999,999')) 
-			ELSE
-				CONCAT('Rp ', TO_CHAR(t.amount, 'FM999,999,999'))
-		END as display_amount,
+	if f.Kind != "" {
+		and("t.kind = ?", f.Kind)
+	}
+	if f.From != nil {
+		and("t.created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		and("t.created_at < ?", f.To.AddDate(0, 0, 1))
+	}
+	if len(f.Types) > 0 {
+		types := make([]string, 0, len(f.Types))
+		for _, typ := range f.Types {
+			types = append(types, models.HistoryTypes[typ])
+		}
+		and("t.transaction_type = ANY(?)", types)
+	}
+	if len(f.Statuses) > 0 {
+		and("t.status = ANY(?)", f.Statuses)
+	}
+	if f.MinAmount > 0 {
+		and("t.amount >= ?", f.MinAmount)
+	}
+	if f.MaxAmount > 0 {
+		and("t.amount <= ?", f.MaxAmount)
+	}
+	if f.Counterparty > 0 {
+		and("t.counterparty_user_id = ?", f.Counterparty)
+	}
+	if search := strings.TrimSpace(f.Search); search != "" {
+		// trigram index on notes & fullname serve the ILIKE
+		and("(t.notes ILIKE ? OR p.fullname ILIKE ? OR pm.name ILIKE ?)", "%"+likeEscaper.Replace(search)+"%")
+	}
+	return where, args
+}
 
-		t.amount as original_amount,
-		COALESCE(t.transfer_status::text, 'pending') as status,
-		COALESCE(t.notes, '') as notes,
-		t.created_at
+// backslash is the default ESCAPE of LIKE
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
 
-	FROM transfer t
-	JOIN wallets w_sender ON t.sender_wallet_id = w_sender.id
-	JOIN wallets w_receiver ON t.receiver_wallet_id = w_receiver.id
-	LEFT JOIN profile p_sender ON w_sender.user_id = p_sender.user_id
-	LEFT JOIN profile p_receiver ON w_receiver.user_id = p_receiver.user_id
-	WHERE 
-		(w_sender.user_id = $1 OR w_receiver.user_id = $1)
-		AND (
-			CASE 
-				WHEN w_sender.user_id = $1 THEN t.deleted_by_sender = FALSE
-				WHEN w_receiver.user_id = $1 THEN t.deleted_by_receiver = FALSE
-			END
-		)
-	ORDER BY t.created_at DESC
-	LIMIT $2 OFFSET $3`
+// newest first, id & kind keep order stable between page when created_at is the same
+const historyOrder = ` ORDER BY t.created_at DESC, t.id DESC, t.kind DESC`
 
-	rows, err := tr.db.Query(ctx, sql, userID, limit, offset)
+func (tr *TransactionRepository) queryHistory(ctx context.Context, sql string, args ...any) ([]models.TransactionHistory, error) {
+	rows, err := tr.db.Query(ctx, sql, args...)
 	if err != nil {
-		log.Printf("Error querying transaction history: %v", err)
+		logger.FromContext(ctx).Error("Error querying transaction history", "err", err)
 		return nil, err
 	}
 	defer rows.Close()
@@ -85,59 +111,82 @@ func (tr *TransactionRepository) GetHistory(ctx context.Context, userID int, off
 	var histories []models.TransactionHistory
 	for rows.Next() {
 		var history models.TransactionHistory
-		if err := rows.Scan(
-			&history.ID,
-			&history.Type,
-			&history.ProfilePicture,
-			&history.ContactName,
-			&history.PhoneNumber,
-			&history.Amount,
-			&history.OriginalAmount,
-			&history.Status,
-			&history.Notes,
-			&history.CreatedAt,
-		); err != nil {
-			log.Printf("Error scanning transaction row: %v", err)
+		if err := rows.Scan(historyFields(&history)...); err != nil {
+			logger.FromContext(ctx).Error("Error scanning transaction row", "err", err)
 			return nil, err
 		}
 		histories = append(histories, history)
 	}
 
 	if err := rows.Err(); err != nil {
-		log.Printf("Error iterating transaction rows: %v", err)
+		logger.FromContext(ctx).Error("Error iterating transaction rows", "err", err)
 		return nil, err
 	}
+	return histories, nil
+}
 
-	if len(histories) == 0 {
-		return nil, errors.New("no transactions found")
+// historyFields is scan destination of historyColumns
+func historyFields(h *models.TransactionHistory) []any {
+	return []any{
+		&h.ID,
+		&h.Kind,
+		&h.Type,
+		&h.CounterpartyID,
+		&h.ProfilePicture,
+		&h.ContactName,
+		&h.PhoneNumber,
+		&h.Amount,
+		&h.OriginalAmount,
+		&h.Status,
+		&h.Notes,
+		&h.Category,
+		&h.CreatedAt,
 	}
+}
 
-	return histories, nil
+func (tr *TransactionRepository) countHistory(ctx context.Context, sql string, args ...any) (int, error) {
+	var count int
+	if err := tr.db.QueryRow(ctx, sql, args...).Scan(&count); err != nil {
+		logger.FromContext(ctx).Error("Error counting transaction history", "err", err)
+		return 0, err
+	}
+	return count, nil
 }
 
-// GetHistoryCount - Get total count of transaction history for pagination
-func (tr *TransactionRepository) GetHistoryCount(ctx context.Context, userID int) (int, error) {
-	sql := `SELECT COUNT(*)
-	FROM transfer t
-	JOIN wallets w_sender ON t.sender_wallet_id = w_sender.id
-	JOIN wallets w_receiver ON t.receiver_wallet_id = w_receiver.id
-	WHERE 
-		(w_sender.user_id = $1 OR w_receiver.user_id = $1)
-		AND (
-			CASE 
-				WHEN w_sender.user_id = $1 THEN t.deleted_by_sender = FALSE
-				WHEN w_receiver.user_id = $1 THEN t.deleted_by_receiver = FALSE
-			END
-		)`
+// GetHistory - transfer history of user, excluding the one soft deleted by user
+func (tr *TransactionRepository) GetHistory(ctx context.Context, userID int, filter models.HistoryFilter, offset, limit int) ([]models.TransactionHistory, error) {
+	filter.Kind = "transfer"
+	return tr.GetAllHistory(ctx, userID, filter, limit, offset)
+}
 
-	var count int
-	err := tr.db.QueryRow(ctx, sql, userID).Scan(&count)
+// GetHistoryByCursor - keyset pagination on (created_at, id, kind), new transaction doesn't shift the page
+func (tr *TransactionRepository) GetHistoryByCursor(ctx context.Context, userID int, filter models.HistoryFilter, cursor *models.Cursor, limit int) (models.CursorPage[models.TransactionHistory], error) {
+	where, args := historyWhere(userID, filter)
+	order := historyOrder
+	if cursor != nil {
+		args = append(args, cursor.CreatedAt, cursor.ID, cursor.Kind)
+		n := len(args)
+		if cursor.Backward {
+			where += fmt.Sprintf(` AND (t.created_at, t.id, t.kind) > ($%d, $%d, $%d)`, n-2, n-1, n)
+			order = ` ORDER BY t.created_at, t.id, t.kind`
+		} else {
+			where += fmt.Sprintf(` AND (t.created_at, t.id, t.kind) < ($%d, $%d, $%d)`, n-2, n-1, n)
+		}
+	}
+	args = append(args, limit+1)
+	sql := historySelect + where + order + fmt.Sprintf(` LIMIT $%d`, len(args))
+
+	histories, err := tr.queryHistory(ctx, sql, args...)
 	if err != nil {
-		log.Printf("Error counting transaction history: %v", err)
-		return 0, err
+		return models.CursorPage[models.TransactionHistory]{}, err
 	}
+	return utils.CursorPageOf(histories, cursor, limit, utils.HistoryCursor), nil
+}
 
-	return count, nil
+// GetHistoryCount - Get total count of transaction history for pagination
+func (tr *TransactionRepository) GetHistoryCount(ctx context.Context, userID int, filter models.HistoryFilter) (int, error) {
+	filter.Kind = "transfer"
+	return tr.GetAllHistoryCount(ctx, userID, filter)
 }
 
 // SoftDeleteTransaction - untuk soft delete transaksi
@@ -157,25 +206,20 @@ func (tr *TransactionRepository) SoftDeleteTransaction(ctx context.Context, tran
 	var userRole string
 	err := tr.db.QueryRow(ctx, checkSQL, transactionID, userID).Scan(&userRole)
 	if err != nil {
-		log.Printf("Error checking user role: %v", err)
+		if err == pgx.ErrNoRows {
+			return ErrTransactionNotFound
+		}
+		logger.FromContext(ctx).Error("Error checking user role", "err", err)
 		return err
 	}
 
 	if userRole == "none" {
-		return errors.New("transaction not found or user not authorized")
+		return ErrTransactionNotFound
 	}
 
-	// Update berdasarkan role user
-	var updateSQL string
-	if userRole == "sender" {
-		updateSQL = `UPDATE transfer SET deleted_by_sender = TRUE WHERE id = $1`
-	} else {
-		updateSQL = `UPDATE transfer SET deleted_by_receiver = TRUE WHERE id = $1`
-	}
-
-	_, err = tr.db.Exec(ctx, updateSQL, transactionID)
-	if err != nil {
-		log.Printf("Error soft deleting transaction: %v", err)
+	// transaction already hidden stay as it is
+	if _, err := tr.db.Exec(ctx, hideSQL["transfer"], transactionID, userID); err != nil {
+		logger.FromContext(ctx).Error("Error soft deleting transaction", "err", err)
 		return err
 	}
 
@@ -194,16 +238,15 @@ WHERE t.id = $1
 	var ownerID int
 	err := tr.db.QueryRow(ctx, checkSQL, topupID).Scan(&ownerID)
 	if err != nil {
-		return errors.New("topup not found")
+		return ErrTopUpNotFound
 	}
 
 	if ownerID != userID {
-		return errors.New("unauthorized")
+		return ErrTopUpNotOwned
 	}
 
 	// Update untuk soft delete
-	updateSQL := `UPDATE topup SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1`
-	_, err = tr.db.Exec(ctx, updateSQL, topupID)
+	_, err = tr.db.Exec(ctx, hideSQL["topup"], topupID, userID)
 	if err != nil {
 		return err
 	}
@@ -211,90 +254,309 @@ WHERE t.id = $1
 	return nil
 }
 
-func (tr *TransactionRepository) GetTopupHistory(ctx context.Context, userID int) ([]models.TransactionHistory, error) {
-	sql := `SELECT 
-    t.id,
-    'Topup' AS transaction_type,
-    '' AS profile_picture,
-    pm.name AS contact_name,
-    '' AS phone_number,
-    CONCAT('+Rp ', TO_CHAR(t.amount, 'FM999,999,999')) AS display_amount,
-    t.amount AS original_amount,
-    COALESCE(t.topup_status::text, 'pending') AS status,
-    CONCAT('Tax: Rp ', TO_CHAR(COALESCE(t.tax, 0), 'FM999,999,999')) AS notes,
-    t.created_at
-FROM topup t
-JOIN wallets_topup wt ON t.id = wt.topup_id
-JOIN wallets w ON wt.wallets_id = w.id
-JOIN payment_method pm ON t.payment_id = pm.id
-WHERE w.user_id = $1 
-  AND t.deleted_at IS NULL
-ORDER BY t.created_at DESC;`
+// hideSQL set hidden flag of transaction $1 for user $2 and put it into trash,
+// nothing is changed when the transaction isn't user's or is already hidden
+var hideSQL = map[string]string{
+	"transfer": `WITH hidden AS (
+		UPDATE transfer t SET
+			deleted_by_sender = t.deleted_by_sender IS TRUE OR ws.user_id = $2,
+			deleted_by_receiver = t.deleted_by_receiver IS TRUE OR wr.user_id = $2
+		FROM wallets ws, wallets wr
+		WHERE t.id = $1 AND ws.id = t.sender_wallet_id AND wr.id = t.receiver_wallet_id
+			AND ((ws.user_id = $2 AND t.deleted_by_sender IS NOT TRUE) OR (wr.user_id = $2 AND t.deleted_by_receiver IS NOT TRUE))
+		RETURNING t.id
+	)
+	INSERT INTO transaction_trash (user_id, kind, transaction_id)
+	SELECT $2, 'transfer', id FROM hidden
+	ON CONFLICT DO NOTHING`,
+	"topup": `WITH hidden AS (
+		UPDATE topup tp SET deleted_at = CURRENT_TIMESTAMP
+		FROM wallets_topup wt, wallets w
+		WHERE tp.id = $1 AND wt.topup_id = tp.id AND w.id = wt.wallets_id AND w.user_id = $2 AND tp.deleted_at IS NULL
+		RETURNING tp.id
+	)
+	INSERT INTO transaction_trash (user_id, kind, transaction_id)
+	SELECT $2, 'topup', id FROM hidden
+	ON CONFLICT DO NOTHING`,
+}
+
+// restoreSQL take transaction $1 of user $2 out of trash and clear its hidden flag,
+// nothing is changed when it isn't in trash (never hidden or already purged)
+var restoreSQL = map[string]string{
+	"transfer": `WITH restored AS (
+		DELETE FROM transaction_trash WHERE user_id = $2 AND kind = 'transfer' AND transaction_id = $1
+		RETURNING transaction_id
+	)
+	UPDATE transfer t SET
+		deleted_by_sender = t.deleted_by_sender IS TRUE AND ws.user_id <> $2,
+		deleted_by_receiver = t.deleted_by_receiver IS TRUE AND wr.user_id <> $2
+	FROM restored, wallets ws, wallets wr
+	WHERE t.id = restored.transaction_id AND ws.id = t.sender_wallet_id AND wr.id = t.receiver_wallet_id`,
+	"topup": `WITH restored AS (
+		DELETE FROM transaction_trash WHERE user_id = $2 AND kind = 'topup' AND transaction_id = $1
+		RETURNING transaction_id
+	)
+	UPDATE topup tp SET deleted_at = NULL
+	FROM restored
+	WHERE tp.id = restored.transaction_id`,
+}
 
-	rows, err := tr.db.Query(ctx, sql, userID)
+// HideTransactions - soft delete many transaction at once, return the one actually hidden
+func (tr *TransactionRepository) HideTransactions(ctx context.Context, userID int, refs []models.TransactionRef) ([]models.TransactionRef, error) {
+	return tr.applyAll(ctx, "hide", hideSQL, userID, refs)
+}
+
+// RestoreTransactions - bring back transaction from trash, return the one actually restored
+func (tr *TransactionRepository) RestoreTransactions(ctx context.Context, userID int, refs []models.TransactionRef) ([]models.TransactionRef, error) {
+	return tr.applyAll(ctx, "restore", restoreSQL, userID, refs)
+}
+
+// applyAll run sql of kind for every ref in one transaction
+func (tr *TransactionRepository) applyAll(ctx context.Context, action string, sql map[string]string, userID int, refs []models.TransactionRef) ([]models.TransactionRef, error) {
+	tx, err := tr.db.Begin(ctx)
 	if err != nil {
-		log.Printf("Error querying topup history: %v", err)
 		return nil, err
 	}
-	defer rows.Close()
+	defer tx.Rollback(ctx)
 
-	var histories []models.TransactionHistory
-	for rows.Next() {
-		var history models.TransactionHistory
-		if err := rows.Scan(
-			&history.ID,
-			&history.Type,
-			&history.ProfilePicture,
-			&history.ContactName,
-			&history.PhoneNumber,
-			&history.Amount,
-			&history.OriginalAmount,
-			&history.Status,
-			&history.Notes,
-			&history.CreatedAt,
-		); err != nil {
-			log.Printf("Error scanning topup row: %v", err)
+	var updated []models.TransactionRef
+	for _, ref := range refs {
+		query, ok := sql[ref.Kind]
+		if !ok {
+			return nil, fmt.Errorf("unknown transaction kind %q", ref.Kind)
+		}
+		tag, err := tx.Exec(ctx, query, ref.ID, userID)
+		if err != nil {
+			logger.FromContext(ctx).Error("Error "+action+" transaction", "kind", ref.Kind, "id", ref.ID, "err", err)
 			return nil, err
 		}
-		histories = append(histories, history)
+		if tag.RowsAffected() > 0 {
+			updated = append(updated, ref)
+		}
 	}
 
-	return histories, nil
+	if err := tx.Commit(ctx); err != nil {
+		logger.FromContext(ctx).Error("Error "+action+" transaction", "err", err)
+		return nil, err
+	}
+	return updated, nil
 }
 
-// GetAllHistory - untuk mendapatkan gabungan transfer dan topup history
-func (tr *TransactionRepository) GetAllHistory(ctx context.Context, userID int, limit int, offset int) ([]models.TransactionHistory, error) {
-	// Get transfer history
-	transferHistory, err := tr.GetHistory(ctx, userID, offset, limit)
-	if err != nil && err.Error() != "no transactions found" {
+// SetCategory - category picked by user for own transaction, empty category let rule decide again.
+// it return category of transaction after the change, not found is ErrTransactionNotFound or ErrTopUpNotFound
+func (tr *TransactionRepository) SetCategory(ctx context.Context, userID int, ref models.TransactionRef, category string) (string, error) {
+	// transaction_category has no foreign key to transfer & topup, so owner is checked first
+	var owned bool
+	sql := `SELECT EXISTS (SELECT 1 FROM transactions WHERE user_id = $1 AND kind = $2 AND id = $3)`
+	if err := tr.db.QueryRow(ctx, sql, userID, ref.Kind, ref.ID).Scan(&owned); err != nil {
+		logger.FromContext(ctx).Error("Error checking transaction owner", "err", err)
+		return "", err
+	}
+	if !owned {
+		if ref.Kind == "topup" {
+			return "", ErrTopUpNotFound
+		}
+		return "", ErrTransactionNotFound
+	}
+
+	args := []any{userID, ref.Kind, ref.ID}
+	sql = `DELETE FROM transaction_category WHERE user_id = $1 AND kind = $2 AND transaction_id = $3`
+	if category != "" {
+		sql = `INSERT INTO transaction_category (user_id, kind, transaction_id, category) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, kind, transaction_id) DO UPDATE SET category = EXCLUDED.category`
+		args = append(args, category)
+	}
+	if _, err := tr.db.Exec(ctx, sql, args...); err != nil {
+		logger.FromContext(ctx).Error("Error saving transaction category", "err", err)
+		return "", err
+	}
+
+	sql = `SELECT ` + categoryColumn + historyFrom + ` WHERE t.user_id = $1 AND t.kind = $2 AND t.id = $3`
+	if err := tr.db.QueryRow(ctx, sql, userID, ref.Kind, ref.ID).Scan(&category); err != nil {
+		logger.FromContext(ctx).Error("Error getting transaction category", "err", err)
+		return "", err
+	}
+	return category, nil
+}
+
+const trashFrom = historyFrom + `
+	JOIN transaction_trash tt ON tt.user_id = t.user_id AND tt.kind = t.kind AND tt.transaction_id = t.id
+	WHERE t.user_id = $1 AND t.deleted`
+
+// GetTrash - transaction hidden by user which can still be restored, last hidden first
+func (tr *TransactionRepository) GetTrash(ctx context.Context, userID int, offset, limit int) ([]models.TrashItem, error) {
+	sql := `SELECT` + historyColumns + `, tt.deleted_at` + trashFrom + `
+	ORDER BY tt.deleted_at DESC, t.id DESC, t.kind DESC LIMIT $2 OFFSET $3`
+	rows, err := tr.db.Query(ctx, sql, userID, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).Error("Error querying trash", "err", err)
+		return nil, err
+	}
+
+	var items []models.TrashItem
+	var item models.TrashItem
+	_, err = pgx.ForEachRow(rows, append(historyFields(&item.TransactionHistory), &item.DeletedAt), func() error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("Error scanning trash", "err", err)
 		return nil, err
 	}
+	return items, nil
+}
+
+// GetTrashCount - total of GetTrash for pagination
+func (tr *TransactionRepository) GetTrashCount(ctx context.Context, userID int) (int, error) {
+	return tr.countHistory(ctx, `SELECT COUNT(*)`+trashFrom, userID)
+}
+
+// PurgeTrash - remove trash hidden longer than retention, the transaction stay hidden but can't be restored anymore
+func (tr *TransactionRepository) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	tag, err := tr.db.Exec(ctx, `DELETE FROM transaction_trash WHERE deleted_at < CURRENT_TIMESTAMP - $1::INTERVAL`, retention)
+	if err != nil {
+		logger.FromContext(ctx).Error("Error purging trash", "err", err)
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (tr *TransactionRepository) GetTopupHistory(ctx context.Context, userID int) ([]models.TransactionHistory, error) {
+	where, args := historyWhere(userID, models.HistoryFilter{Kind: "topup"})
+	return tr.queryHistory(ctx, historySelect+where+historyOrder, args...)
+}
 
-	// Get topup history
-	topupHistory, err := tr.GetTopupHistory(ctx, userID)
+// GetAllHistory - transfer and topup (and any other kind on transactions view) sorted & paginated by postgres
+func (tr *TransactionRepository) GetAllHistory(ctx context.Context, userID int, filter models.HistoryFilter, limit int, offset int) ([]models.TransactionHistory, error) {
+	where, args := historyWhere(userID, filter)
+	args = append(args, limit, offset)
+	sql := historySelect + where + historyOrder + fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+	histories, err := tr.queryHistory(ctx, sql, args...)
 	if err != nil {
-		log.Printf("Error getting topup history: %v", err)
-		// Continue even if topup history fails
-		topupHistory = []models.TransactionHistory{}
-	}
-
-	// Combine histories
-	allHistory := append(transferHistory, topupHistory...)
-
-	// Sort by created_at DESC
-	if len(allHistory) > 1 {
-		for i := 0; i < len(allHistory)-1; i++ {
-			for j := i + 1; j < len(allHistory); j++ {
-				if allHistory[i].CreatedAt.Before(allHistory[j].CreatedAt) {
-					allHistory[i], allHistory[j] = allHistory[j], allHistory[i]
-				}
-			}
+		return nil, err
+	}
+	if len(histories) == 0 {
+		return nil, ErrNoTransactions
+	}
+	return histories, nil
+}
+
+// GetAllHistoryCount - total of GetAllHistory for pagination
+func (tr *TransactionRepository) GetAllHistoryCount(ctx context.Context, userID int, filter models.HistoryFilter) (int, error) {
+	where, args := historyWhere(userID, filter)
+	return tr.countHistory(ctx, `SELECT COUNT(*)`+historyFrom+where, args...)
+}
+
+// GetTransactionDetail - transfer or topup with both party and status timeline, caller check who can see it
+// not found is ErrTransactionNotFound for transfer and ErrTopUpNotFound for topup
+func (tr *TransactionRepository) GetTransactionDetail(ctx context.Context, kind string, id int) (*models.TransactionDetail, error) {
+	detail := models.TransactionDetail{ID: id, Kind: kind}
+	var err error
+	switch kind {
+	case "transfer":
+		sql := `SELECT
+			t.amount, t.transfer_status::TEXT, COALESCE(t.notes, ''), t.created_at, t.updated_at,
+			ws.user_id, ws.id, COALESCE(ps.fullname, ''), COALESCE(ps.phone, ''), COALESCE(ps.profile_picture, ''),
+			wr.user_id, wr.id, COALESCE(pr.fullname, ''), COALESCE(pr.phone, ''), COALESCE(pr.profile_picture, '')
+		FROM transfer t
+		JOIN wallets ws ON ws.id = t.sender_wallet_id
+		JOIN wallets wr ON wr.id = t.receiver_wallet_id
+		LEFT JOIN profile ps ON ps.user_id = ws.user_id
+		LEFT JOIN profile pr ON pr.user_id = wr.user_id
+		WHERE t.id = $1`
+		s, r := &detail.Sender, &detail.Receiver
+		err = tr.db.QueryRow(ctx, sql, id).Scan(
+			&detail.Amount, &detail.Status, &detail.Notes, &detail.CreatedAt, &detail.UpdatedAt,
+			&s.UserID, &s.WalletID, &s.Name, &s.Phone, &s.ProfilePicture,
+			&r.UserID, &r.WalletID, &r.Name, &r.Phone, &r.ProfilePicture,
+		)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTransactionNotFound
+		}
+	case "topup":
+		// topup which isn't applied to any wallet yet has no owner, so nobody can see it
+		sql := `SELECT
+			tp.amount, COALESCE(tp.tax, 0), tp.topup_status::TEXT, tp.created_at, tp.updated_at, pm.name,
+			w.user_id, w.id, COALESCE(p.fullname, ''), COALESCE(p.phone, ''), COALESCE(p.profile_picture, '')
+		FROM topup tp
+		JOIN wallets_topup wt ON wt.topup_id = tp.id
+		JOIN wallets w ON w.id = wt.wallets_id
+		JOIN payment_method pm ON pm.id = tp.payment_id
+		LEFT JOIN profile p ON p.user_id = w.user_id
+		WHERE tp.id = $1`
+		r := &detail.Receiver
+		err = tr.db.QueryRow(ctx, sql, id).Scan(
+			&detail.Amount, &detail.Fee, &detail.Status, &detail.CreatedAt, &detail.UpdatedAt, &detail.Sender.Name,
+			&r.UserID, &r.WalletID, &r.Name, &r.Phone, &r.ProfilePicture,
+		)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTopUpNotFound
 		}
+	default:
+		return nil, fmt.Errorf("unknown transaction kind %q", kind)
 	}
+	if err != nil {
+		logger.FromContext(ctx).Error("Error getting transaction detail", "kind", kind, "id", id, "err", err)
+		return nil, err
+	}
+	detail.Total = detail.Amount + detail.Fee
 
-	if len(allHistory) == 0 {
-		return nil, errors.New("no transactions found")
+	rows, err := tr.db.Query(ctx, `SELECT status, created_at FROM transaction_status_history
+		WHERE kind = $1 AND transaction_id = $2 ORDER BY created_at, id`, kind, id)
+	if err != nil {
+		logger.FromContext(ctx).Error("Error querying transaction timeline", "err", err)
+		return nil, err
+	}
+	detail.Timeline, err = pgx.CollectRows(rows, pgx.RowToStructByPos[models.TransactionStatusEvent])
+	if err != nil {
+		logger.FromContext(ctx).Error("Error scanning transaction timeline", "err", err)
+		return nil, err
+	}
+	return &detail, nil
+}
+
+// statement only count success transaction, pending & failed never move the balance.
+// soft deleted transaction is only hidden from history, it is still on statement
+const statementWhere = ` WHERE t.user_id = $1 AND t.status = 'success'`
+
+// GetOpeningBalance - balance of user right before the given time, computed from transfer & topup
+func (tr *TransactionRepository) GetOpeningBalance(ctx context.Context, userID int, before time.Time) (int, error) {
+	sql := `SELECT COALESCE(SUM(CASE WHEN t.transaction_type = 'Send' THEN -t.amount ELSE t.amount END), 0)
+	FROM transactions t` + statementWhere + ` AND t.created_at < $2`
+	var balance int
+	if err := tr.db.QueryRow(ctx, sql, userID, before).Scan(&balance); err != nil {
+		logger.FromContext(ctx).Error("Error getting opening balance", "err", err)
+		return 0, err
 	}
+	return balance, nil
+}
+
+// StreamStatement - success transaction of user in [from, to), oldest first
+// fn is called for every row while it is read, so long range is never loaded at once
+func (tr *TransactionRepository) StreamStatement(ctx context.Context, userID int, from, to time.Time, fn func(models.StatementEntry) error) error {
+	sql := `SELECT
+		t.id,
+		t.kind,
+		t.transaction_type,
+		CASE WHEN t.kind = 'topup' THEN COALESCE(pm.name, '') ELSE COALESCE(p.fullname, '') END,
+		t.notes,
+		CASE WHEN t.transaction_type = 'Send' THEN t.amount ELSE 0 END,
+		CASE WHEN t.transaction_type = 'Send' THEN 0 ELSE t.amount END,
+		t.created_at` + historyFrom + statementWhere + ` AND t.created_at >= $2 AND t.created_at < $3
+	ORDER BY t.created_at, t.kind, t.id`
+	rows, err := tr.db.Query(ctx, sql, userID, from, to)
+	if err != nil {
+		logger.FromContext(ctx).Error("Error querying statement", "err", err)
+		return err
+	}
+
+	var e models.StatementEntry
+	_, err = pgx.ForEachRow(rows, []any{&e.ID, &e.Kind, &e.Type, &e.Counterparty, &e.Notes, &e.Debit, &e.Credit, &e.CreatedAt}, func() error {
+		return fn(e)
+	})
+	return err
+}
 
-	return allHistory, nil
+func (tr *TransactionRepository) GetMailRecipient(ctx context.Context, userID int) (*models.MailRecipient, error) {
+	return getMailRecipient(ctx, tr.db, "u.id = $1", userID)
 }
@@ -0,0 +1,488 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+)
+
+func TestGetHistory(t *testing.T) {
+	pool, _ := setup(t)
+	f := fixture{t: t, db: pool}
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	budiUser, budi := f.user("budi@mail.com", "Budi", "0811", 0)
+	aniUser, ani := f.user("ani@mail.com", "Ani", "0812", 0)
+	_, cici := f.user("cici@mail.com", "Cici", "0813", 0)
+	dodiUser, _ := f.user("dodi@mail.com", "Dodi", "0814", 0)
+	bri := f.paymentMethod("BRI")
+
+	sent := f.transfer(budi, ani, 1500000, "success", now)
+	received := f.transfer(ani, budi, 2000, "success", now.Add(time.Minute))
+	f.transfer(cici, ani, 3000, "success", now.Add(2*time.Minute)) // doesn't involve budi
+	deleted := f.transfer(budi, cici, 4000, "success", now.Add(3*time.Minute))
+	f.topup(budi, bri, 50000, "success", now.Add(4*time.Minute))
+
+	tr := repository.NewTransactionRepository(pool)
+	c := context.Background()
+	if err := tr.SoftDeleteTransaction(c, deleted, budiUser); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("newest first with counterparty", func(t *testing.T) {
+		histories, err := tr.GetHistory(c, budiUser, models.HistoryFilter{}, 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(histories) != 2 {
+			t.Fatalf("got %d history, want 2: %+v", len(histories), histories)
+		}
+		got := histories[0]
+		if got.ID != received || got.Type != "Transfer" || got.ContactName != "Ani" || got.PhoneNumber != "0812" || got.Amount != "Rp 2,000" {
+			t.Errorf("received transfer = %+v", got)
+		}
+		got = histories[1]
+		if got.ID != sent || got.Type != "Send" || got.ContactName != "Ani" || got.Amount != "Rp 1,500,000" || got.OriginalAmount != 1500000 {
+			t.Errorf("sent transfer = %+v", got)
+		}
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		histories, err := tr.GetHistory(c, budiUser, models.HistoryFilter{}, 1, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(histories) != 1 || histories[0].ID != sent {
+			t.Errorf("second page = %+v, want only transfer %d", histories, sent)
+		}
+		if _, err := tr.GetHistory(c, budiUser, models.HistoryFilter{}, 2, 1); !errors.Is(err, repository.ErrNoTransactions) {
+			t.Errorf("page out of range err = %v, want ErrNoTransactions", err)
+		}
+	})
+
+	t.Run("count exclude soft deleted", func(t *testing.T) {
+		count, err := tr.GetHistoryCount(c, budiUser, models.HistoryFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 2 {
+			t.Errorf("budi count = %d, want 2", count)
+		}
+		// soft delete only hide transfer of the user who delete it
+		count, err = tr.GetHistoryCount(c, aniUser, models.HistoryFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 3 {
+			t.Errorf("ani count = %d, want 3", count)
+		}
+	})
+
+	t.Run("user without transaction", func(t *testing.T) {
+		if _, err := tr.GetHistory(c, dodiUser, models.HistoryFilter{}, 0, 10); !errors.Is(err, repository.ErrNoTransactions) {
+			t.Errorf("err = %v, want ErrNoTransactions", err)
+		}
+	})
+
+	t.Run("soft delete transaction of other user", func(t *testing.T) {
+		if err := tr.SoftDeleteTransaction(c, sent, dodiUser); !errors.Is(err, repository.ErrTransactionNotFound) {
+			t.Errorf("err = %v, want ErrTransactionNotFound", err)
+		}
+		if err := tr.SoftDeleteTransaction(c, 9999, budiUser); !errors.Is(err, repository.ErrTransactionNotFound) {
+			t.Errorf("unknown transaction err = %v, want ErrTransactionNotFound", err)
+		}
+	})
+
+	t.Run("all history merge topup", func(t *testing.T) {
+		histories, err := tr.GetAllHistory(c, budiUser, models.HistoryFilter{}, 10, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(histories) != 3 {
+			t.Fatalf("got %d history, want 3: %+v", len(histories), histories)
+		}
+		if got := histories[0]; got.Type != "Topup" || got.ContactName != "BRI" || got.PhoneNumber != "" || got.Amount != "+Rp 50,000" || got.Notes != "Tax: Rp 1,000" {
+			t.Errorf("topup = %+v", got)
+		}
+		if got := histories[1]; got.ID != received || got.Type != "Transfer" || got.ContactName != "Ani" || got.Notes != "fixture" {
+			t.Errorf("received transfer = %+v", got)
+		}
+		count, err := tr.GetAllHistoryCount(c, budiUser, models.HistoryFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 3 {
+			t.Errorf("count = %d, want 3", count)
+		}
+	})
+
+	t.Run("all history paginated by database", func(t *testing.T) {
+		// topup is newest, so second page must start from the received transfer
+		histories, err := tr.GetAllHistory(c, budiUser, models.HistoryFilter{}, 1, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(histories) != 1 || histories[0].ID != received || histories[0].Type != "Transfer" {
+			t.Errorf("second page = %+v, want only transfer %d", histories, received)
+		}
+		if _, err := tr.GetAllHistory(c, budiUser, models.HistoryFilter{}, 1, 3); !errors.Is(err, repository.ErrNoTransactions) {
+			t.Errorf("page out of range err = %v, want ErrNoTransactions", err)
+		}
+	})
+}
+
+func TestGetHistoryByCursor(t *testing.T) {
+	pool, _ := setup(t)
+	f := fixture{t: t, db: pool}
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	budiUser, budi := f.user("budi@mail.com", "Budi", "0811", 0)
+	_, ani := f.user("ani@mail.com", "Ani", "0812", 0)
+	bri := f.paymentMethod("BRI")
+
+	// transfer and topup at the same time may share the same id, kind break the tie
+	var rows []models.Cursor
+	for i := range 3 {
+		at := now.Add(time.Duration(i) * time.Minute)
+		rows = append(rows,
+			models.Cursor{CreatedAt: at, ID: f.transfer(budi, ani, 1000*(i+1), "success", at), Kind: "transfer"},
+			models.Cursor{CreatedAt: at, ID: f.topup(budi, bri, 10000*(i+1), "success", at), Kind: "topup"},
+		)
+	}
+	slices.SortFunc(rows, utils.CompareCursor)
+	var want []string
+	for _, row := range rows {
+		want = append(want, fmt.Sprintf("%s %d", row.Kind, row.ID))
+	}
+
+	tr := repository.NewTransactionRepository(pool)
+	c := context.Background()
+	key := func(histories []models.TransactionHistory) []string {
+		var keys []string
+		for _, h := range histories {
+			keys = append(keys, fmt.Sprintf("%s %d", h.Kind, h.ID))
+		}
+		return keys
+	}
+
+	t.Run("forward and backward", func(t *testing.T) {
+		var got []string
+		var cursor *models.Cursor
+		var last models.CursorPage[models.TransactionHistory]
+		for range len(want) {
+			page, err := tr.GetHistoryByCursor(c, budiUser, models.HistoryFilter{}, cursor, 4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, key(page.Items)...)
+			last = page
+			if cursor = page.Next; cursor == nil {
+				break
+			}
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("walked history = %v, want %v", got, want)
+		}
+
+		back, err := tr.GetHistoryByCursor(c, budiUser, models.HistoryFilter{}, last.Prev, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := key(back.Items); !slices.Equal(got, want[:4]) {
+			t.Errorf("previous page = %v, want %v", got, want[:4])
+		}
+		if back.Prev != nil {
+			t.Errorf("first page has prev cursor %+v", back.Prev)
+		}
+	})
+
+	t.Run("filter by kind", func(t *testing.T) {
+		page, err := tr.GetHistoryByCursor(c, budiUser, models.HistoryFilter{Kind: "topup"}, nil, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Items) != 3 || page.Next != nil {
+			t.Errorf("topup page = %v next %+v, want 3 topup and no next", key(page.Items), page.Next)
+		}
+	})
+}
+
+func TestGetHistoryFilter(t *testing.T) {
+	pool, _ := setup(t)
+	f := fixture{t: t, db: pool}
+	day := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	budiUser, budi := f.user("budi@mail.com", "Budi", "0811", 0)
+	aniUser, ani := f.user("ani@mail.com", "Ani Lestari", "0812", 0)
+	_, cici := f.user("cici@mail.com", "Cici", "0813", 0)
+	bca := f.paymentMethod("BCA")
+
+	sent := f.transfer(budi, ani, 10000, "success", day)
+	failed := f.transfer(budi, cici, 50000, "failed", day.AddDate(0, 0, 1))
+	received := f.transfer(cici, budi, 2000, "success", day.AddDate(0, 0, 2))
+	topup := f.topup(budi, bca, 100000, "pending", day.AddDate(0, 0, 3))
+	if _, err := pool.Exec(context.Background(), `UPDATE transfer SET notes = 'diskon 100%' WHERE id = $1`, received); err != nil {
+		t.Fatal(err)
+	}
+
+	date := func(d time.Time) *time.Time {
+		d = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	tests := []struct {
+		name   string
+		filter models.HistoryFilter
+		want   []int
+	}{
+		{name: "no filter", want: []int{topup, received, failed, sent}},
+		{name: "kind", filter: models.HistoryFilter{Kind: "transfer"}, want: []int{received, failed, sent}},
+		{name: "type", filter: models.HistoryFilter{Types: []string{"sent", "topup"}}, want: []int{topup, failed, sent}},
+		{name: "status", filter: models.HistoryFilter{Statuses: []string{"failed", "pending"}}, want: []int{topup, failed}},
+		{name: "amount", filter: models.HistoryFilter{MinAmount: 2000, MaxAmount: 10000}, want: []int{received, sent}},
+		{name: "date inclusive", filter: models.HistoryFilter{From: date(day.AddDate(0, 0, 1)), To: date(day.AddDate(0, 0, 2))}, want: []int{received, failed}},
+		{name: "counterparty", filter: models.HistoryFilter{Counterparty: aniUser}, want: []int{sent}},
+		{name: "search counterparty name", filter: models.HistoryFilter{Search: "lestari"}, want: []int{sent}},
+		{name: "search payment method", filter: models.HistoryFilter{Search: "bca"}, want: []int{topup}},
+		{name: "search notes", filter: models.HistoryFilter{Search: "DISKON"}, want: []int{received}},
+		// % is matched literally, not as wildcard
+		{name: "search escape wildcard", filter: models.HistoryFilter{Search: "0%"}, want: []int{received}},
+		{name: "search without match", filter: models.HistoryFilter{Search: "_"}, want: nil},
+	}
+
+	tr := repository.NewTransactionRepository(pool)
+	c := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			histories, err := tr.GetAllHistory(c, budiUser, tt.filter, 10, 0)
+			if err != nil && !errors.Is(err, repository.ErrNoTransactions) {
+				t.Fatal(err)
+			}
+			var got []int
+			for _, h := range histories {
+				got = append(got, h.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("history = %v, want %v", got, tt.want)
+			}
+			count, err := tr.GetAllHistoryCount(c, budiUser, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != len(tt.want) {
+				t.Errorf("count = %d, want %d", count, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestGetTransactionDetail(t *testing.T) {
+	pool, _ := setup(t)
+	f := fixture{t: t, db: pool}
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	budiUser, budi := f.user("budi@mail.com", "Budi", "0811", 0)
+	aniUser, ani := f.user("ani@mail.com", "Ani", "0812", 0)
+	bri := f.paymentMethod("BRI")
+	transfer := f.transfer(budi, ani, 15000, "success", now)
+	topup := f.topup(budi, bri, 50000, "pending", now.Add(time.Minute))
+
+	tr := repository.NewTransactionRepository(pool)
+	c := context.Background()
+
+	t.Run("transfer", func(t *testing.T) {
+		d, err := tr.GetTransactionDetail(c, "transfer", transfer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Amount != 15000 || d.Total != 15000 || d.Status != "success" || !d.CreatedAt.Equal(now) {
+			t.Errorf("detail = %+v", d)
+		}
+		if d.Sender.UserID != budiUser || d.Sender.Name != "Budi" || d.Receiver.UserID != aniUser || d.Receiver.Phone != "0812" {
+			t.Errorf("sender = %+v, receiver = %+v", d.Sender, d.Receiver)
+		}
+		if len(d.Timeline) != 1 || d.Timeline[0].Status != "success" {
+			t.Errorf("timeline = %+v, want one success", d.Timeline)
+		}
+	})
+
+	t.Run("topup timeline recorded by trigger", func(t *testing.T) {
+		if _, err := pool.Exec(c, `UPDATE topup SET topup_status = 'success' WHERE id = $1`, topup); err != nil {
+			t.Fatal(err)
+		}
+		// update without status change isn't part of timeline
+		if _, err := pool.Exec(c, `UPDATE topup SET amount = amount WHERE id = $1`, topup); err != nil {
+			t.Fatal(err)
+		}
+		d, err := tr.GetTransactionDetail(c, "topup", topup)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Amount != 50000 || d.Fee != 1000 || d.Total != 51000 || d.Sender.Name != "BRI" || d.Receiver.UserID != budiUser {
+			t.Errorf("detail = %+v", d)
+		}
+		var statuses []string
+		for _, e := range d.Timeline {
+			statuses = append(statuses, e.Status)
+		}
+		if !slices.Equal(statuses, []string{"pending", "success"}) {
+			t.Errorf("timeline = %v, want [pending success]", statuses)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		if _, err := tr.GetTransactionDetail(c, "transfer", transfer+100); !errors.Is(err, repository.ErrTransactionNotFound) {
+			t.Errorf("transfer err = %v, want ErrTransactionNotFound", err)
+		}
+		if _, err := tr.GetTransactionDetail(c, "topup", topup+100); !errors.Is(err, repository.ErrTopUpNotFound) {
+			t.Errorf("topup err = %v, want ErrTopUpNotFound", err)
+		}
+	})
+}
+
+func TestStatement(t *testing.T) {
+	pool, _ := setup(t)
+	f := fixture{t: t, db: pool}
+	day := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	budiUser, budi := f.user("budi@mail.com", "Budi", "0811", 0)
+	_, ani := f.user("ani@mail.com", "Ani", "0812", 0)
+	bri := f.paymentMethod("BRI")
+
+	f.topup(budi, bri, 100000, "success", day.AddDate(0, 0, -1))
+	f.topup(budi, bri, 70000, "failed", day.AddDate(0, 0, -1)) // never move the balance
+	sent := f.transfer(budi, ani, 25000, "success", day)
+	received := f.transfer(ani, budi, 5000, "success", day.Add(time.Hour))
+	f.transfer(budi, ani, 9000, "pending", day.Add(2*time.Hour))
+	f.transfer(budi, ani, 1000, "success", day.AddDate(0, 0, 1)) // after the period
+
+	tr := repository.NewTransactionRepository(pool)
+	c := context.Background()
+	// soft deleted transfer is still on statement
+	if err := tr.SoftDeleteTransaction(c, sent, budiUser); err != nil {
+		t.Fatal(err)
+	}
+
+	opening, err := tr.GetOpeningBalance(c, budiUser, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opening != 100000 {
+		t.Errorf("opening = %d, want 100000", opening)
+	}
+
+	var entries []models.StatementEntry
+	err = tr.StreamStatement(c, budiUser, day, day.AddDate(0, 0, 1), func(e models.StatementEntry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2: %+v", len(entries), entries)
+	}
+	if e := entries[0]; e.ID != sent || e.Type != "Send" || e.Debit != 25000 || e.Credit != 0 || e.Counterparty != "Ani" {
+		t.Errorf("sent entry = %+v", e)
+	}
+	if e := entries[1]; e.ID != received || e.Type != "Transfer" || e.Debit != 0 || e.Credit != 5000 {
+		t.Errorf("received entry = %+v", e)
+	}
+
+	// error of fn stop the stream
+	stop := errors.New("stop")
+	calls := 0
+	err = tr.StreamStatement(c, budiUser, day, day.AddDate(0, 0, 1), func(e models.StatementEntry) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("err = %v after %d call, want stop after 1", err, calls)
+	}
+}
+
+func TestTrash(t *testing.T) {
+	pool, _ := setup(t)
+	f := fixture{t: t, db: pool}
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	budiUser, budi := f.user("budi@mail.com", "Budi", "0811", 0)
+	aniUser, ani := f.user("ani@mail.com", "Ani", "0812", 0)
+	bri := f.paymentMethod("BRI")
+	sent := f.transfer(budi, ani, 15000, "success", now)
+	received := f.transfer(ani, budi, 5000, "success", now.Add(time.Minute))
+	topup := f.topup(budi, bri, 50000, "success", now.Add(2*time.Minute))
+
+	tr := repository.NewTransactionRepository(pool)
+	c := context.Background()
+
+	hidden, err := tr.HideTransactions(c, budiUser, []models.TransactionRef{
+		{Kind: "transfer", ID: sent}, {Kind: "topup", ID: topup}, {Kind: "transfer", ID: sent + 100},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hidden) != 2 {
+		t.Errorf("hidden = %+v, want transfer & topup", hidden)
+	}
+	// single delete put transaction into trash too, the same transfer is hidden separately for ani
+	if err := tr.SoftDeleteTransaction(c, sent, aniUser); err != nil {
+		t.Fatal(err)
+	}
+
+	trash, err := tr.GetTrash(c, budiUser, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trash) != 2 || trash[0].ContactName == "" || trash[0].DeletedAt.IsZero() {
+		t.Fatalf("trash = %+v, want 2 item", trash)
+	}
+	if count, err := tr.GetTrashCount(c, aniUser); err != nil || count != 1 {
+		t.Errorf("ani trash count = %d, %v, want 1", count, err)
+	}
+
+	t.Run("restore", func(t *testing.T) {
+		restored, err := tr.RestoreTransactions(c, budiUser, []models.TransactionRef{
+			{Kind: "transfer", ID: sent}, {Kind: "transfer", ID: received},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(restored) != 1 || restored[0].ID != sent {
+			t.Errorf("restored = %+v, want only sent transfer", restored)
+		}
+		// restored for budi only, ani still hide it
+		if count, _ := tr.GetAllHistoryCount(c, budiUser, models.HistoryFilter{}); count != 2 {
+			t.Errorf("budi history count = %d, want 2", count)
+		}
+		if count, _ := tr.GetAllHistoryCount(c, aniUser, models.HistoryFilter{}); count != 1 {
+			t.Errorf("ani history count = %d, want 1", count)
+		}
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		if _, err := pool.Exec(c, `UPDATE transaction_trash SET deleted_at = CURRENT_TIMESTAMP - INTERVAL '31 days' WHERE kind = 'topup'`); err != nil {
+			t.Fatal(err)
+		}
+		purged, err := tr.PurgeTrash(c, 30*24*time.Hour)
+		if err != nil || purged != 1 {
+			t.Fatalf("purged = %d, %v, want 1", purged, err)
+		}
+		// topup stay hidden, but can't be restored anymore
+		restored, err := tr.RestoreTransactions(c, budiUser, []models.TransactionRef{{Kind: "topup", ID: topup}})
+		if err != nil || len(restored) != 0 {
+			t.Errorf("restored = %+v, %v, want nothing", restored, err)
+		}
+		if count, _ := tr.GetAllHistoryCount(c, budiUser, models.HistoryFilter{Kind: "topup"}); count != 0 {
+			t.Errorf("topup history count = %d, want 0", count)
+		}
+	})
+}
@@ -3,10 +3,11 @@ package repository
 import (
 	"context"
 	"errors"
-	"log"
+	"fmt"
 	"math"
 	"time"
 
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
 	"github.com/Belalai-E-Wallet-Backend/internal/models"
 	"github.com/Belalai-E-Wallet-Backend/internal/utils"
 	"github.com/jackc/pgx/v5"
@@ -36,7 +37,7 @@ func (ur *TransferRepository) FilterUser(c context.Context, query string, offset
 	if offset == 0 && query == "" {
 		cachedUser, err := utils.RedisGetData[models.ListprofileResponse](c, *ur.rdb, rdbKey)
 		if err != nil {
-			log.Println("Redis error :", err)
+			logger.FromContext(c).Error("Redis error", "err", err)
 		} else if cachedUser != nil && len(cachedUser.Users) > 0 {
 			return *cachedUser, nil
 		}
@@ -51,17 +52,18 @@ func (ur *TransferRepository) FilterUser(c context.Context, query string, offset
 	countErr := ur.db.QueryRow(c, countSql, searchQuery).Scan(&totalUser)
 	if countErr != nil {
 		// Tangani error, bisa jadi error umum atau NoRows, tapi COUNT() biasanya selalu mengembalikan 0 atau lebih.
-		log.Println("Error getting total user count:", countErr.Error())
+		logger.FromContext(c).Error("Error getting total user count", "err", countErr)
 		return models.ListprofileResponse{}, countErr
 	}
 
 	// get filtered list user
-	sql := `SELECT user_id, profile_picture, fullname, phone FROM profile
+	sql := `SELECT user_id, profile_picture, fullname, phone, created_at FROM profile
     WHERE fullname ILIKE $1 OR phone ILIKE $1
+		ORDER BY created_at DESC, user_id DESC
 		LIMIT $2 OFFSET $3`
 	rows, err := ur.db.Query(c, sql, values...)
 	if err != nil {
-		log.Println("internal server error : ", err.Error())
+		logger.FromContext(c).Error("internal server error", "err", err)
 		return models.ListprofileResponse{}, err
 	}
 	defer rows.Close()
@@ -70,8 +72,8 @@ func (ur *TransferRepository) FilterUser(c context.Context, query string, offset
 	var users []models.ProfileResponse
 	for rows.Next() {
 		var user models.ProfileResponse
-		if err := rows.Scan(&user.UserID, &user.ProfilePicture, &user.Fullname, &user.Phone); err != nil {
-			log.Println("Scan Error, ", err.Error())
+		if err := rows.Scan(&user.UserID, &user.ProfilePicture, &user.Fullname, &user.Phone, &user.CreatedAt); err != nil {
+			logger.FromContext(c).Error("Scan Error", "err", err)
 			return models.ListprofileResponse{}, err
 		}
 		users = append(users, user)
@@ -90,7 +92,7 @@ func (ur *TransferRepository) FilterUser(c context.Context, query string, offset
 	// Get and renew cache only for page 1 only (offset 0)
 	if offset == 0 && query == "" {
 		if err := utils.RedisRenewData(c, *ur.rdb, rdbKey, finalResponse, 10*time.Minute); err != nil {
-			log.Println("Failed to renew Redis cache:", err.Error())
+			logger.FromContext(c).Error("Failed to renew Redis cache", "err", err)
 		}
 	}
 
@@ -98,12 +100,51 @@ func (ur *TransferRepository) FilterUser(c context.Context, query string, offset
 	return finalResponse, nil
 }
 
+// SearchUserByCursor - keyset pagination of FilterUser on (created_at, user_id), new user doesn't shift the page
+func (ur *TransferRepository) SearchUserByCursor(c context.Context, query string, cursor *models.Cursor, limit int) (models.CursorPage[models.ProfileResponse], error) {
+	where := `WHERE (fullname ILIKE $1 OR phone ILIKE $1)`
+	args := []any{"%" + query + "%"}
+	order := `ORDER BY created_at DESC, user_id DESC`
+	if cursor != nil {
+		args = append(args, cursor.CreatedAt, cursor.ID)
+		if cursor.Backward {
+			where += ` AND (created_at, user_id) > ($2, $3)`
+			order = `ORDER BY created_at, user_id`
+		} else {
+			where += ` AND (created_at, user_id) < ($2, $3)`
+		}
+	}
+	args = append(args, limit+1)
+	sql := fmt.Sprintf(`SELECT user_id, profile_picture, fullname, phone, created_at FROM profile %s %s LIMIT $%d`, where, order, len(args))
+
+	rows, err := ur.db.Query(c, sql, args...)
+	if err != nil {
+		logger.FromContext(c).Error("internal server error", "err", err)
+		return models.CursorPage[models.ProfileResponse]{}, err
+	}
+	defer rows.Close()
+
+	var users []models.ProfileResponse
+	for rows.Next() {
+		var user models.ProfileResponse
+		if err := rows.Scan(&user.UserID, &user.ProfilePicture, &user.Fullname, &user.Phone, &user.CreatedAt); err != nil {
+			logger.FromContext(c).Error("Scan Error", "err", err)
+			return models.CursorPage[models.ProfileResponse]{}, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return models.CursorPage[models.ProfileResponse]{}, err
+	}
+	return utils.CursorPageOf(users, cursor, limit, utils.ProfileCursor), nil
+}
+
 // get user pin for validate user on handler
 func (ur *TransferRepository) GetHashedPin(rqCntxt context.Context, senderId int) (models.UserPin, error) {
 	var userPin models.UserPin
 	sql := `SELECT id, pin FROM users WHERE id=$1`
 	if err := ur.db.QueryRow(rqCntxt, sql, senderId).Scan(&userPin.Id, &userPin.Pin); err != nil {
-		log.Println("failed get pin user \ncause :", err)
+		logger.FromContext(rqCntxt).Error("failed get pin user", "err", err)
 		return models.UserPin{}, errors.New("failed get pin user")
 	}
 	return userPin, nil
@@ -113,98 +154,164 @@ func (ur *TransferRepository) GetHashedPin(rqCntxt context.Context, senderId int
 var ErrNotEnoughBalance = errors.New("not enough balance for this transfer")
 var ErrCantSendingToYourself = errors.New("can't sending money to yourself")
 
-func (ur *TransferRepository) TransferMoney(rqCntxt context.Context, senderId int, body models.TransferBody) error {
+// return id of new transfer if success
+func (ur *TransferRepository) TransferMoney(rqCntxt context.Context, senderId int, body models.TransferBody) (int, error) {
 
 	// using tx transaction postgresql
 	tx, err := ur.db.Begin(rqCntxt)
 	if err != nil {
-		log.Println("Failed to begin DB transaction\nCause: ", err)
-		return err
+		logger.FromContext(rqCntxt).Error("Failed to begin DB transaction", "err", err)
+		return 0, err
 	}
 	defer tx.Rollback(rqCntxt)
 
-	// get balance sender and validate pin sender
+	// get balance sender and lock wallet of sender and receiver
+	// wallets are locked in the same order (by id), so transfers of opposite direction
+	// running at the same time wait for each other instead of deadlock
 	// if balance sender is not enough to do transfer, abort transaction
 	var senderWalletID int
 	var senderBalance float64
-	qBalSender := `SELECT w.id, w.balance FROM wallets w 
-         JOIN users u ON w.user_id = u.id 
-         WHERE u.id = $1 FOR UPDATE`
-	if err := tx.QueryRow(rqCntxt, qBalSender, senderId).Scan(&senderWalletID, &senderBalance); err != nil {
-		if err == pgx.ErrNoRows {
-			log.Println("error no rows or user invalid", err)
-			return errors.New("user invalid or wrong pin inputs")
+	qLockWallets := `SELECT id, user_id, balance FROM wallets
+         WHERE user_id = $1 OR id = $2
+         ORDER BY id FOR UPDATE`
+	rows, err := tx.Query(rqCntxt, qLockWallets, senderId, body.IdReceiver)
+	if err != nil {
+		logger.FromContext(rqCntxt).Error("Internal Server Error", "err", err)
+		return 0, err
+	}
+	for rows.Next() {
+		var walletID, walletUserID int
+		var balance float64
+		if err := rows.Scan(&walletID, &walletUserID, &balance); err != nil {
+			rows.Close()
+			logger.FromContext(rqCntxt).Error("Internal Server Error", "err", err)
+			return 0, err
+		}
+		if walletUserID == senderId {
+			senderWalletID, senderBalance = walletID, balance
 		}
-		log.Println("Internal Server Error.\nCause: ", err.Error())
-		return err
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		logger.FromContext(rqCntxt).Error("Internal Server Error", "err", err)
+		return 0, err
+	}
+	if senderWalletID == 0 {
+		logger.FromContext(rqCntxt).Error("error no rows or user invalid", "user_id", senderId)
+		return 0, errors.New("user invalid or wrong pin inputs")
 	}
 	// validate not sending money to self
 	if senderWalletID == body.IdReceiver {
-		return ErrCantSendingToYourself
+		return 0, ErrCantSendingToYourself
 	}
 
 	// validate if sender balance is have enough money to do transfer
 	if senderBalance < float64(body.Amount) {
-		return ErrNotEnoughBalance
+		return 0, ErrNotEnoughBalance
 	}
 
 	// execute tranfser
 	// update saldo sender
 	now := time.Now()
-	sqlSenderWallet := `UPDATE wallets SET balance = balance - $1, updated_at = $2 WHERE id = $3`
-	values := []any{body.Amount, now, senderId}
-	cmd, err := tx.Exec(rqCntxt, sqlSenderWallet, values...)
-	if err != nil {
-		log.Println("Failed execute query sqlSenderWallet\nCause:", err)
-		return err
-	}
-	if cmd.RowsAffected() == 0 {
-		log.Println("no row effected when UPDATE wallets maybe failed?")
-		return errors.New("no row effected when UPDATE wallets maybe failed?")
+	var senderNewBalance int
+	sqlSenderWallet := `UPDATE wallets SET balance = balance - $1, updated_at = $2 WHERE id = $3 RETURNING balance`
+	values := []any{body.Amount, now, senderWalletID}
+	if err := tx.QueryRow(rqCntxt, sqlSenderWallet, values...).Scan(&senderNewBalance); err != nil {
+		if err == pgx.ErrNoRows {
+			logger.FromContext(rqCntxt).Warn("no row effected when UPDATE wallets maybe failed?")
+			return 0, errors.New("no row effected when UPDATE wallets maybe failed?")
+		}
+		logger.FromContext(rqCntxt).Error("Failed execute query sqlSenderWallet", "err", err)
+		return 0, err
 	}
 	// update saldo receiver
-	sqlReceiverWallet := `UPDATE wallets SET balance = balance + $1, updated_at = $2 WHERE id = $3`
+	var receiverUserID, receiverNewBalance int
+	sqlReceiverWallet := `UPDATE wallets SET balance = balance + $1, updated_at = $2 WHERE id = $3 RETURNING user_id, balance`
 	values = []any{body.Amount, now, body.IdReceiver}
-	cmd, err = tx.Exec(rqCntxt, sqlReceiverWallet, values...)
-	if err != nil {
-		log.Println("Failed execute query sqlReceiverWallet\nCause:", err)
-		return err
-	}
-	if cmd.RowsAffected() == 0 {
-		log.Println("no row effected when UPDATE wallets maybe failed?")
-		return errors.New("no row effected when UPDATE wallets maybe failed?")
+	if err := tx.QueryRow(rqCntxt, sqlReceiverWallet, values...).Scan(&receiverUserID, &receiverNewBalance); err != nil {
+		if err == pgx.ErrNoRows {
+			logger.FromContext(rqCntxt).Warn("no row effected when UPDATE wallets maybe failed?")
+			return 0, errors.New("no row effected when UPDATE wallets maybe failed?")
+		}
+		logger.FromContext(rqCntxt).Error("Failed execute query sqlReceiverWallet", "err", err)
+		return 0, err
 	}
 
 	// insert transfer data
 	var transferID int
 	sqlTansferTable := `INSERT INTO transfer (sender_wallet_id, receiver_wallet_id, amount, transfer_status, notes, created_at, updated_at)
     VALUES ($1, $2, $3, 'success', $4, $5, $5) RETURNING id`
-	values = []any{senderId, body.IdReceiver, body.Amount, body.Notes, now}
+	values = []any{senderWalletID, body.IdReceiver, body.Amount, body.Notes, now}
 	if err := tx.QueryRow(rqCntxt, sqlTansferTable, values...).Scan(&transferID); err != nil {
-		log.Println("Failed execute query sqlTansferTable \nCause :", err)
-		return err
+		logger.FromContext(rqCntxt).Error("Failed execute query sqlTansferTable", "err", err)
+		return 0, err
 	}
 
 	// insert wallet_transfer
 	sqlTransferWalletTable := `INSERT INTO wallets_transfer (wallets_id, transfer_id) VALUES ($1, $3), ($2, $3)`
-	values = []any{senderId, body.IdReceiver, transferID}
-	cmd, err = tx.Exec(rqCntxt, sqlTransferWalletTable, values...)
+	values = []any{senderWalletID, body.IdReceiver, transferID}
+	cmd, err := tx.Exec(rqCntxt, sqlTransferWalletTable, values...)
 	if err != nil {
-		log.Println("Failed execute query sqlTransferWalletTable\nCause:", err)
-		return err
+		logger.FromContext(rqCntxt).Error("Failed execute query sqlTransferWalletTable", "err", err)
+		return 0, err
 	}
 	if cmd.RowsAffected() == 0 {
-		log.Println("no row effected when INSERT INTO wallets_transfer maybe failed?")
-		return errors.New("no row effected when INSERT INTO wallets_transfer maybe failed?")
+		logger.FromContext(rqCntxt).Warn("no row effected when INSERT INTO wallets_transfer maybe failed?")
+		return 0, errors.New("no row effected when INSERT INTO wallets_transfer maybe failed?")
 	}
 
 	// commit transaction if all query success execute
 	if err := tx.Commit(rqCntxt); err != nil {
-		log.Println("Failed to commit DB transaction\nCause: ", err)
-		return err
+		logger.FromContext(rqCntxt).Error("Failed to commit DB transaction", "err", err)
+		return 0, err
 	}
-	log.Println("success to commit DB transaction")
+	logger.FromContext(rqCntxt).Info("success to commit DB transaction")
+
+	// push balance and new transaction to all connected devices of sender and receiver
+	ur.publishTransferEvents(rqCntxt, senderId, senderNewBalance, receiverUserID, receiverNewBalance, models.TransactionEvent{
+		ID:        transferID,
+		Amount:    body.Amount,
+		Status:    "success",
+		Notes:     body.Notes,
+		CreatedAt: now,
+	})
 
 	// error nil if success
-	return nil
+	return transferID, nil
+}
+
+// publish event after transfer is commited, failure only logged because transfer is already success
+func (ur *TransferRepository) publishTransferEvents(c context.Context, senderId, senderBalance, receiverId, receiverBalance int, trx models.TransactionEvent) {
+	sent := trx
+	sent.Type = "Send"
+	sent.CounterpartyID = receiverId
+	received := trx
+	received.Type = "Transfer"
+	received.CounterpartyID = senderId
+
+	if err := utils.PublishUserEvent(c, *ur.rdb, senderId, models.EventBalanceUpdated, models.BalanceEvent{Balance: senderBalance}); err != nil {
+		logger.FromContext(c).Error("Failed publish sender balance event", "err", err)
+	}
+	if err := utils.PublishUserEvent(c, *ur.rdb, senderId, models.EventTransactionCreated, sent); err != nil {
+		logger.FromContext(c).Error("Failed publish sender transaction event", "err", err)
+	}
+	if err := utils.PublishUserEvent(c, *ur.rdb, receiverId, models.EventBalanceUpdated, models.BalanceEvent{Balance: receiverBalance}); err != nil {
+		logger.FromContext(c).Error("Failed publish receiver balance event", "err", err)
+	}
+	if err := utils.PublishUserEvent(c, *ur.rdb, receiverId, models.EventTransactionCreated, received); err != nil {
+		logger.FromContext(c).Error("Failed publish receiver transaction event", "err", err)
+	}
+}
+
+// get sender and receiver email data, used to notify receiver after transfer
+func (ur *TransferRepository) GetMailRecipients(c context.Context, senderId, receiverWalletId int) (*models.MailRecipient, *models.MailRecipient, error) {
+	sender, err := getMailRecipient(c, ur.db, "u.id = $1", senderId)
+	if err != nil {
+		return nil, nil, err
+	}
+	receiver, err := getMailRecipient(c, ur.db, "w.id = $1", receiverWalletId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sender, receiver, nil
 }
@@ -0,0 +1,176 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// assertNoNegativeBalance check every wallet, not only the one used by the test
+func assertNoNegativeBalance(t *testing.T, pool *pgxpool.Pool) {
+	t.Helper()
+	var negative int
+	if err := pool.QueryRow(context.Background(), `SELECT COUNT(*) FROM wallets WHERE balance < 0`).Scan(&negative); err != nil {
+		t.Fatal(err)
+	}
+	if negative != 0 {
+		t.Errorf("%d wallet has negative balance", negative)
+	}
+}
+
+func TestTransferMoney(t *testing.T) {
+	pool, rdb := setup(t)
+	f := fixture{t: t, db: pool}
+	budiUser, budi := f.user("budi@mail.com", "Budi", "0811", 10000)
+	_, ani := f.user("ani@mail.com", "Ani", "0812", 0)
+
+	tr := repository.NewTransferRepository(pool, rdb)
+	c := context.Background()
+
+	id, err := tr.TransferMoney(c, budiUser, models.TransferBody{IdReceiver: ani, Amount: 4000, Notes: "makan"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.balance(budi) != 6000 || f.balance(ani) != 4000 {
+		t.Errorf("balance = %d/%d, want 6000/4000", f.balance(budi), f.balance(ani))
+	}
+	var sender, receiver int
+	if err := pool.QueryRow(c, `SELECT sender_wallet_id, receiver_wallet_id FROM transfer WHERE id = $1`, id).Scan(&sender, &receiver); err != nil {
+		t.Fatal(err)
+	}
+	if sender != budi || receiver != ani {
+		t.Errorf("transfer wallet = %d -> %d, want %d -> %d", sender, receiver, budi, ani)
+	}
+
+	if _, err := tr.TransferMoney(c, budiUser, models.TransferBody{IdReceiver: ani, Amount: 6001}); !errors.Is(err, repository.ErrNotEnoughBalance) {
+		t.Errorf("insufficient balance err = %v, want ErrNotEnoughBalance", err)
+	}
+	if _, err := tr.TransferMoney(c, budiUser, models.TransferBody{IdReceiver: budi, Amount: 1}); !errors.Is(err, repository.ErrCantSendingToYourself) {
+		t.Errorf("self transfer err = %v, want ErrCantSendingToYourself", err)
+	}
+	if f.balance(budi) != 6000 || f.balance(ani) != 4000 {
+		t.Errorf("failed transfer change balance to %d/%d", f.balance(budi), f.balance(ani))
+	}
+}
+
+// many transfer from the same wallet at the same time must never spend more than the balance
+func TestTransferMoneyConcurrentSameSender(t *testing.T) {
+	pool, rdb := setup(t)
+	f := fixture{t: t, db: pool}
+	budiUser, budi := f.user("budi@mail.com", "Budi", "0811", 100000)
+	_, ani := f.user("ani@mail.com", "Ani", "0812", 0)
+	tr := repository.NewTransferRepository(pool, rdb)
+
+	const attempts, amount = 50, 7000
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	success, rejected := 0, 0
+	for range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := tr.TransferMoney(context.Background(), budiUser, models.TransferBody{IdReceiver: ani, Amount: amount})
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				success++
+			case errors.Is(err, repository.ErrNotEnoughBalance):
+				rejected++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if want := 100000 / amount; success != want || rejected != attempts-want {
+		t.Errorf("success %d rejected %d, want %d and %d", success, rejected, want, attempts-want)
+	}
+	if got, want := f.balance(budi), 100000-success*amount; got != want {
+		t.Errorf("sender balance = %d, want %d", got, want)
+	}
+	if got := f.balance(ani); got != success*amount {
+		t.Errorf("receiver balance = %d, want %d", got, success*amount)
+	}
+	assertNoNegativeBalance(t, pool)
+}
+
+// transfer of opposite direction lock both wallet, it must not deadlock or create/lose money
+func TestTransferMoneyConcurrentBothDirection(t *testing.T) {
+	pool, rdb := setup(t)
+	f := fixture{t: t, db: pool}
+	budiUser, budi := f.user("budi@mail.com", "Budi", "0811", 30000)
+	aniUser, ani := f.user("ani@mail.com", "Ani", "0812", 30000)
+	tr := repository.NewTransferRepository(pool, rdb)
+
+	var wg sync.WaitGroup
+	for i := range 60 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body := models.TransferBody{IdReceiver: ani, Amount: 4000}
+			sender := budiUser
+			if i%2 == 1 {
+				body.IdReceiver, sender = budi, aniUser
+			}
+			if _, err := tr.TransferMoney(context.Background(), sender, body); err != nil && !errors.Is(err, repository.ErrNotEnoughBalance) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if total := f.balance(budi) + f.balance(ani); total != 60000 {
+		t.Errorf("total balance = %d, want 60000", total)
+	}
+	var transferred int
+	if err := pool.QueryRow(context.Background(), `SELECT COALESCE(SUM(CASE WHEN sender_wallet_id = $1 THEN -amount ELSE amount END), 0) FROM transfer`, budi).Scan(&transferred); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.balance(budi); got != 30000+transferred {
+		t.Errorf("budi balance = %d, doesn't match transfer history %d", got, 30000+transferred)
+	}
+	assertNoNegativeBalance(t, pool)
+}
+
+func TestSearchUserByCursor(t *testing.T) {
+	pool, rdb := setup(t)
+	f := fixture{t: t, db: pool}
+	var ids []int
+	for i := 1; i <= 5; i++ {
+		id, _ := f.user(fmt.Sprintf("budi%d@mail.com", i), fmt.Sprintf("Budi %d", i), fmt.Sprintf("081%d", i), 0)
+		ids = append([]int{id}, ids...)
+	}
+	f.user("ani@mail.com", "Ani", "0820", 0)
+
+	tr := repository.NewTransferRepository(pool, rdb)
+	c := context.Background()
+
+	var got []int
+	var cursor *models.Cursor
+	for range len(ids) {
+		page, err := tr.SearchUserByCursor(c, "budi", cursor, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, u := range page.Items {
+			got = append(got, u.UserID)
+		}
+		if cursor = page.Next; cursor == nil {
+			break
+		}
+	}
+	if !slices.Equal(got, ids) {
+		t.Errorf("walked user = %v, want %v (newest first)", got, ids)
+	}
+}
@@ -3,30 +3,35 @@ package repository
 import (
 	"context"
 
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
+	"github.com/Belalai-E-Wallet-Backend/internal/metrics"
 	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 )
 
 type TopUpRepository struct {
-	db *pgxpool.Pool
+	db  *pgxpool.Pool
+	rdb *redis.Client
 }
 
-func NewTopUpRepository(db *pgxpool.Pool) *TopUpRepository {
-	return &TopUpRepository{db: db}
+func NewTopUpRepository(db *pgxpool.Pool, rdb *redis.Client) *TopUpRepository {
+	return &TopUpRepository{db: db, rdb: rdb}
 }
 
 func (tr *TopUpRepository) CreateTopUp(ctx context.Context, topup *models.TopUp) (*models.TopUp, error) {
 	query := `
 		INSERT INTO topup (amount, tax, payment_id, topup_status, created_at)
 		VALUES ($1, $2, $3, $4, NOW())
-		RETURNING id, created_at
+		RETURNING id, created_at, (SELECT name FROM payment_method WHERE id = payment_id)
 	`
 	err := tr.db.QueryRow(ctx, query,
 		topup.Amount,
 		topup.Tax,
 		topup.PaymentID,
 		topup.Status,
-	).Scan(&topup.ID, &topup.CreatedAt)
+	).Scan(&topup.ID, &topup.CreatedAt, &topup.PaymentMethod)
 	if err != nil {
 		return nil, err
 	}
@@ -58,8 +63,11 @@ func (tr *TopUpRepository) ApplyToWallet(c context.Context, walletID int, topupI
 	}
 	defer tx.Rollback(c)
 
-	qInsertIntoWalletTopup := "INSERT INTO wallets_topup (wallets_id, topup_id) VALUES ($1, $2)"
-	_, err = tx.Exec(c, qInsertIntoWalletTopup, walletID, topupID)
+	// payment method is only needed for metric
+	var paymentMethod string
+	qInsertIntoWalletTopup := `INSERT INTO wallets_topup (wallets_id, topup_id) VALUES ($1, $2)
+	RETURNING (SELECT pm.name FROM topup t JOIN payment_method pm ON pm.id = t.payment_id WHERE t.id = $2)`
+	err = tx.QueryRow(c, qInsertIntoWalletTopup, walletID, topupID).Scan(&paymentMethod)
 	if err != nil {
 		return err
 	}
@@ -70,7 +78,12 @@ func (tr *TopUpRepository) ApplyToWallet(c context.Context, walletID int, topupI
 		return err
 	}
 
-	return tx.Commit(c)
+	if err := tx.Commit(c); err != nil {
+		return err
+	}
+	// pending topup become success here
+	metrics.ObserveTopUp(paymentMethod, amount)
+	return nil
 }
 
 func (tr *TopUpRepository) GetWalletIDByUserID(c context.Context, userID int) (int, error) {
@@ -114,14 +127,14 @@ func (tr *TopUpRepository) CreateTopUpTransaction(ctx context.Context, topup *mo
 	queryInsertTopup := `
 		INSERT INTO topup (amount, tax, payment_id, topup_status, created_at)
 		VALUES ($1, $2, $3, $4, NOW())
-		RETURNING id, created_at
+		RETURNING id, created_at, (SELECT name FROM payment_method WHERE id = payment_id)
 	`
 	err = tx.QueryRow(ctx, queryInsertTopup,
 		topup.Amount,
 		topup.Tax,
 		topup.PaymentID,
 		models.TopUpSuccess,
-	).Scan(&topup.ID, &topup.CreatedAt)
+	).Scan(&topup.ID, &topup.CreatedAt, &topup.PaymentMethod)
 	if err != nil {
 		return nil, err
 	}
@@ -137,8 +150,9 @@ func (tr *TopUpRepository) CreateTopUpTransaction(ctx context.Context, topup *mo
 		return nil, err
 	}
 
-	qUpdateWallet := `UPDATE wallets SET balance = balance + $1 WHERE id = $2`
-	if _, err := tx.Exec(ctx, qUpdateWallet, topup.Amount, walletID); err != nil {
+	var newBalance int
+	qUpdateWallet := `UPDATE wallets SET balance = balance + $1 WHERE id = $2 RETURNING balance`
+	if err := tx.QueryRow(ctx, qUpdateWallet, topup.Amount, walletID).Scan(&newBalance); err != nil {
 		return nil, err
 	}
 
@@ -147,5 +161,20 @@ func (tr *TopUpRepository) CreateTopUpTransaction(ctx context.Context, topup *mo
 	}
 
 	topup.Status = models.TopUpSuccess
+	metrics.ObserveTopUp(topup.PaymentMethod, topup.Amount)
+
+	// push new balance and topup to all connected devices of user
+	if err := utils.PublishUserEvent(ctx, *tr.rdb, userID, models.EventBalanceUpdated, models.BalanceEvent{Balance: newBalance}); err != nil {
+		logger.FromContext(ctx).Error("Failed publish balance event", "err", err)
+	}
+	if err := utils.PublishUserEvent(ctx, *tr.rdb, userID, models.EventTransactionCreated, models.TransactionEvent{
+		ID:        topup.ID,
+		Type:      "Topup",
+		Amount:    topup.Amount,
+		Status:    string(topup.Status),
+		CreatedAt: topup.CreatedAt,
+	}); err != nil {
+		logger.FromContext(ctx).Error("Failed publish topup event", "err", err)
+	}
 	return topup, nil
 }
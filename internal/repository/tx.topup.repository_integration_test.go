@@ -0,0 +1,90 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// counterValue read counter of default registry by its payment_method label, 0 when it isn't observed yet
+func counterValue(t *testing.T, name, paymentMethod string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "payment_method" && label.GetValue() == paymentMethod {
+					return m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestTopUpMetrics(t *testing.T) {
+	pool, rdb := setup(t)
+	f := fixture{t: t, db: pool}
+	budiUser, budi := f.user("budi@mail.com", "Budi", "0811", 0)
+	bri := f.paymentMethod("BRI")
+	gopay := f.paymentMethod("Gopay")
+
+	tr := repository.NewTopUpRepository(pool, rdb)
+	c := context.Background()
+	// counter is global, compare with value before the topup
+	type counters struct{ count, amount, unlabeled float64 }
+	read := func(method string) counters {
+		return counters{
+			count:     counterValue(t, "belalai_topups_total", method),
+			amount:    counterValue(t, "belalai_topup_amount_rupiah_total", method),
+			unlabeled: counterValue(t, "belalai_topups_total", ""),
+		}
+	}
+
+	t.Run("direct topup", func(t *testing.T) {
+		before := read("BRI")
+		topup, err := tr.CreateTopUpTransaction(c, &models.TopUp{Amount: 50000, PaymentID: bri}, budiUser)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if topup.PaymentMethod != "BRI" {
+			t.Errorf("payment method = %q, want BRI", topup.PaymentMethod)
+		}
+		after := read("BRI")
+		if after.count-before.count != 1 || after.amount-before.amount != 50000 || after.unlabeled != before.unlabeled {
+			t.Errorf("metric before %+v after %+v, want 1 topup of 50000 labeled BRI", before, after)
+		}
+	})
+
+	t.Run("pending topup applied", func(t *testing.T) {
+		topup, err := tr.CreateTopUp(c, &models.TopUp{Amount: 20000, PaymentID: gopay, Status: models.TopUpPending})
+		if err != nil {
+			t.Fatal(err)
+		}
+		before := read("Gopay")
+		if err := tr.UpdateStatusTopUp(c, topup.ID, models.TopUpSuccess); err != nil {
+			t.Fatal(err)
+		}
+		if err := tr.ApplyToWallet(c, budi, topup.ID, topup.Amount); err != nil {
+			t.Fatal(err)
+		}
+		after := read("Gopay")
+		if after.count-before.count != 1 || after.amount-before.amount != 20000 || after.unlabeled != before.unlabeled {
+			t.Errorf("metric before %+v after %+v, want 1 topup of 20000 labeled Gopay", before, after)
+		}
+		if got := f.balance(budi); got != 70000 {
+			t.Errorf("balance = %d, want 70000", got)
+		}
+	})
+}
@@ -1,30 +1,34 @@
 package routers
 
 import (
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
 	"github.com/Belalai-E-Wallet-Backend/internal/handler"
 	"github.com/Belalai-E-Wallet-Backend/internal/middleware"
 	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
 
-func InitAuthRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
-	authRouter := router.Group("/auth")
+func InitAuthRouter(router, legacy gin.IRouter, db *pgxpool.Pool, rdb *redis.Client, mailer utils.Mailer, bg *utils.Background, cfg *configs.Config) {
 	authRepository := repository.NewAuthRepository(db, rdb)
-	authHandler := handler.NewAuthHandler(authRepository)
+	authHandler := handler.NewAuthHandler(authRepository, mailer, bg, cfg)
 
-	authRouter.POST("", authHandler.Login)
-	authRouter.POST("/register", authHandler.Register)
-	authRouter.DELETE("", authHandler.Logout)
-	authRouter.PATCH("/update-pin", middleware.VerifyToken(rdb), authHandler.UpdatePIN)
-	authRouter.PATCH("/change-pin", middleware.VerifyToken(rdb), authHandler.ChangePIN)
-	authRouter.PATCH("/change-password", middleware.VerifyToken(rdb), authHandler.ChangePassword)
+	for _, r := range withLegacy(router, legacy) {
+		authRouter := r.Group("/auth")
+		authRouter.POST("", authHandler.Login)
+		authRouter.POST("/register", authHandler.Register)
+		authRouter.DELETE("", authHandler.Logout)
+		authRouter.PATCH("/update-pin", middleware.VerifyToken(rdb, cfg.JWT), authHandler.UpdatePIN)
+		authRouter.PATCH("/change-pin", middleware.VerifyToken(rdb, cfg.JWT), authHandler.ChangePIN)
+		authRouter.PATCH("/change-password", middleware.VerifyToken(rdb, cfg.JWT), authHandler.ChangePassword)
 
-	authRouter.POST("/forgot-password", authHandler.ForgotPassword)
-	authRouter.POST("/reset-password", authHandler.ResetPassword)
-	authRouter.POST("/forgot-pin", authHandler.ForgotPIN)
-	authRouter.POST("/reset-pin", authHandler.ResetPIN)
+		authRouter.POST("/forgot-password", authHandler.ForgotPassword)
+		authRouter.POST("/reset-password", authHandler.ResetPassword)
+		authRouter.POST("/forgot-pin", authHandler.ForgotPIN)
+		authRouter.POST("/reset-pin", authHandler.ResetPIN)
 
-	authRouter.POST("/confirm-pin", middleware.VerifyToken(rdb), authHandler.ConfirmPIN)
+		authRouter.POST("/confirm-pin", middleware.VerifyToken(rdb, cfg.JWT), authHandler.ConfirmPIN)
+	}
 }
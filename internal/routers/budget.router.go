@@ -0,0 +1,22 @@
+package routers
+
+import (
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+	"github.com/Belalai-E-Wallet-Backend/internal/handler"
+	"github.com/Belalai-E-Wallet-Backend/internal/middleware"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitBudgetRouter(router gin.IRouter, db *pgxpool.Pool, rdb *redis.Client, mailer utils.Mailer, bg *utils.Background, cfg *configs.Config) {
+	budgetRouter := router.Group("/budgets")
+	budgetRepository := repository.NewBudgetRepository(db, rdb)
+	budgetHandler := handler.NewBudgetHandler(budgetRepository, repository.NewChartRepository(db), mailer, bg, cfg)
+
+	budgetRouter.GET("", middleware.VerifyToken(rdb, cfg.JWT), budgetHandler.GetBudgets)
+	budgetRouter.PUT("", middleware.VerifyToken(rdb, cfg.JWT), budgetHandler.SaveBudget)
+	budgetRouter.DELETE("/:id", middleware.VerifyToken(rdb, cfg.JWT), budgetHandler.DeleteBudget)
+}
@@ -0,0 +1,22 @@
+package routers
+
+import (
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+	"github.com/Belalai-E-Wallet-Backend/internal/handler"
+	"github.com/Belalai-E-Wallet-Backend/internal/middleware"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitCategoryRouter(router gin.IRouter, db *pgxpool.Pool, rdb *redis.Client, cfg *configs.Config) {
+	categoryRouter := router.Group("/category")
+	categoryRepository := repository.NewCategoryRepository(db)
+	categoryHandler := handler.NewCategoryHandler(categoryRepository)
+
+	categoryRouter.GET("", middleware.VerifyToken(rdb, cfg.JWT), categoryHandler.GetCategories)
+	categoryRouter.GET("/rules", middleware.VerifyToken(rdb, cfg.JWT), categoryHandler.GetRules)
+	categoryRouter.POST("/rules", middleware.VerifyToken(rdb, cfg.JWT), categoryHandler.CreateRule)
+	categoryRouter.DELETE("/rules/:id", middleware.VerifyToken(rdb, cfg.JWT), categoryHandler.DeleteRule)
+}
@@ -1,6 +1,7 @@
 package routers
 
 import (
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
 	"github.com/Belalai-E-Wallet-Backend/internal/handler"
 	"github.com/Belalai-E-Wallet-Backend/internal/middleware"
 	"github.com/Belalai-E-Wallet-Backend/internal/repository"
@@ -9,10 +10,17 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-func InitChartRoouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
+func InitChartRoouter(router, legacy gin.IRouter, db *pgxpool.Pool, rdb *redis.Client, cfg *configs.Config) {
 	chartRouter := router.Group("/chart")
 	chartRepository := repository.NewChartRepository(db)
-	chartHandler := handler.NewChartHandler(chartRepository)
+	chartHandler := handler.NewChartHandler(chartRepository, cfg)
 
-	chartRouter.GET("/:duration", middleware.VerifyToken(rdb), chartHandler.GetDataChart)
+	chartRouter.GET("", middleware.VerifyToken(rdb, cfg.JWT), chartHandler.GetChartRange)
+	chartRouter.GET("/categories", middleware.VerifyToken(rdb, cfg.JWT), chartHandler.GetCategoryBreakdown)
+	chartRouter.GET("/counterparties", middleware.VerifyToken(rdb, cfg.JWT), chartHandler.GetTopCounterparties)
+	chartRouter.GET("/compare", middleware.VerifyToken(rdb, cfg.JWT), chartHandler.GetMonthComparison)
+	// route before versioning, old client still call it on root path
+	for _, r := range withLegacy(router, legacy) {
+		r.GET("/chart/:duration", middleware.VerifyToken(rdb, cfg.JWT), chartHandler.GetDataChart)
+	}
 }
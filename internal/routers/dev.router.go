@@ -0,0 +1,30 @@
+package routers
+
+import (
+	"log/slog"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+	"github.com/Belalai-E-Wallet-Backend/internal/handler"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// InitDevRouter only register routes outside production and if mailer capture the mail (file or memory transport),
+// captured mail contain reset password & PIN link so it must never be public
+func InitDevRouter(router *gin.Engine, mailer utils.Mailer, cfg *configs.Config) {
+	if cfg.IsProduction() {
+		return
+	}
+	mailbox, ok := mailer.(utils.MailCapture)
+	if !ok {
+		return
+	}
+	slog.Info("Mail is captured locally, view it on /dev/mails")
+
+	devRouter := router.Group("/dev")
+	devHandler := handler.NewDevHandler(mailbox)
+
+	devRouter.GET("/mails", devHandler.ListMails)
+	devRouter.GET("/mails/:id", devHandler.GetMail)
+	devRouter.DELETE("/mails", devHandler.ClearMails)
+}
@@ -0,0 +1,22 @@
+package routers
+
+import (
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+	"github.com/Belalai-E-Wallet-Backend/internal/handler"
+	"github.com/Belalai-E-Wallet-Backend/internal/middleware"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitEventRouter(router gin.IRouter, db *pgxpool.Pool, rdb *redis.Client, bg *utils.Background, cfg *configs.Config) {
+	eventRouter := router.Group("/events")
+	eventRepository := repository.NewEventRepository(rdb)
+	eWalletRepository := repository.NewEWalletRepository(db)
+	eventHandler := handler.NewEventHandler(eventRepository, eWalletRepository, bg)
+
+	eventRouter.GET("/stream", middleware.TokenFromQuery, middleware.VerifyToken(rdb, cfg.JWT), eventHandler.StreamEvents)
+	eventRouter.GET("/ws", middleware.TokenFromQuery, middleware.VerifyToken(rdb, cfg.JWT), eventHandler.StreamWebSocket)
+}
@@ -1,6 +1,7 @@
 package routers
 
 import (
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
 	"github.com/Belalai-E-Wallet-Backend/internal/handler"
 	"github.com/Belalai-E-Wallet-Backend/internal/middleware"
 	"github.com/Belalai-E-Wallet-Backend/internal/repository"
@@ -9,10 +10,11 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-func InitEWalletRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
-	eWalletRouter := router.Group("/balance")
+func InitEWalletRouter(router, legacy gin.IRouter, db *pgxpool.Pool, rdb *redis.Client, cfg *configs.Config) {
 	eWalletRepository := repository.NewEWalletRepository(db)
 	eWalletHandler := handler.NewEWalletHandler(eWalletRepository)
 
-	eWalletRouter.GET("", middleware.VerifyToken(rdb), eWalletHandler.GetBalance)
+	for _, r := range withLegacy(router, legacy) {
+		r.GET("/balance", middleware.VerifyToken(rdb, cfg.JWT), eWalletHandler.GetBalance)
+	}
 }
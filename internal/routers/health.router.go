@@ -0,0 +1,18 @@
+package routers
+
+import (
+	"github.com/Belalai-E-Wallet-Backend/internal/handler"
+	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitHealthRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client, mailer utils.Mailer, bg *utils.Background) {
+	healthRepository := repository.NewHealthRepository(db, rdb)
+	healthHandler := handler.NewHealthHandler(healthRepository, mailer, bg)
+
+	router.GET("/healthz", healthHandler.Liveness)
+	router.GET("/readyz", healthHandler.Readiness)
+}
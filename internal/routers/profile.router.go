@@ -1,6 +1,7 @@
 package routers
 
 import (
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
 	"github.com/Belalai-E-Wallet-Backend/internal/handler"
 	"github.com/Belalai-E-Wallet-Backend/internal/middleware"
 	"github.com/Belalai-E-Wallet-Backend/internal/repository"
@@ -9,12 +10,14 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-func InitProfileRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
-	profile := router.Group("/profile")
+func InitProfileRouter(router, legacy gin.IRouter, db *pgxpool.Pool, rdb *redis.Client, cfg *configs.Config) {
 	profileRepo := repository.NewProfileRepository(db, *rdb)
 	profileHandler := handler.NewProfileHandler(profileRepo)
 
-	profile.GET("", middleware.VerifyToken(rdb), profileHandler.GetProfile)
-	profile.PATCH("", middleware.VerifyToken(rdb), profileHandler.UpdateProfile)
-	profile.DELETE("/avatar", middleware.VerifyToken(rdb), profileHandler.DeleteAvatar)
+	for _, r := range withLegacy(router, legacy) {
+		profile := r.Group("/profile")
+		profile.GET("", middleware.VerifyToken(rdb, cfg.JWT), profileHandler.GetProfile)
+		profile.PATCH("", middleware.VerifyToken(rdb, cfg.JWT), profileHandler.UpdateProfile)
+		profile.DELETE("/avatar", middleware.VerifyToken(rdb, cfg.JWT), profileHandler.DeleteAvatar)
+	}
 }
@@ -1,52 +1,58 @@
 package routers
 
 import (
-	"net/http"
-
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 
 	docs "github.com/Belalai-E-Wallet-Backend/docs"
+	"github.com/Belalai-E-Wallet-Backend/internal/apperror"
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+	"github.com/Belalai-E-Wallet-Backend/internal/metrics"
 	"github.com/Belalai-E-Wallet-Backend/internal/middleware"
-	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func InitRouter(db *pgxpool.Pool, rdb *redis.Client) *gin.Engine {
+func InitRouter(db *pgxpool.Pool, rdb *redis.Client, mailer utils.Mailer, bg *utils.Background, cfg *configs.Config) *gin.Engine {
 	// inizialization engine gin
-	router := gin.Default()
+	router := gin.New()
+	// gin.Context passed to repository carry request context (trace span, logger, cancellation)
+	router.ContextWithFallback = true
+	router.Use(middleware.Tracing)
+	router.Use(middleware.RequestID)
+	router.Use(middleware.Locale)
+	router.Use(middleware.AccessLog)
+	router.Use(middleware.Metrics)
+	router.Use(middleware.ErrorHandler)
+	router.Use(middleware.Recovery)
 	router.Use(middleware.CORSMiddleware)
 
+	// prometheus scrape endpoint
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
 	// swaggo configuration
 	docs.SwaggerInfo.BasePath = "/"
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 
 	// setup routing
-	InitAuthRouter(router, db, rdb)
-
-	InitTransferRouter(router, db, rdb)
-
-	InitEWalletRouter(router, db, rdb)
-
-	InitTransactionRouter(router, db, rdb)
-
-	InitProfileRouter(router, db, rdb)
+	InitHealthRouter(router, db, rdb, mailer, bg)
 
-	InitTopUpRouter(router, db, rdb)
+	// old app still call route without version, keep it until sunset
+	var legacy gin.IRouter
+	if cfg.Legacy.Enabled {
+		legacy = router.Group("", middleware.Deprecated(cfg.Legacy.DeprecatedAt, cfg.Legacy.SunsetAt, "/v1"))
+	}
+	InitV1Router(router.Group("/v1"), legacy, db, rdb, mailer, bg, cfg)
 
-	InitChartRoouter(router, db, rdb)
+	InitDevRouter(router, mailer, cfg)
 
 	// make directori public accesible
 	router.Static("/img", "public")
 
 	router.NoRoute(func(ctx *gin.Context) {
-		ctx.JSON(http.StatusNotFound, models.Response{
-			IsSuccess: false,
-			Code:      http.StatusNotFound,
-			Msg:       "Page not found!",
-		})
+		ctx.Error(apperror.New(apperror.RouteNotFound))
 	})
 
 	return router
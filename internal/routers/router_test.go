@@ -0,0 +1,90 @@
+package routers_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+	"github.com/Belalai-E-Wallet-Backend/internal/routers"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// route served on root path before /v1, old client only call these
+var baselineRoutes = []string{
+	"DELETE /auth",
+	"DELETE /profile/avatar",
+	"DELETE /transaction/:id",
+	"DELETE /transaction/topup/:id",
+	"GET /balance",
+	"GET /chart/:duration",
+	"GET /profile",
+	"GET /topup/methods",
+	"GET /transaction/history",
+	"GET /transaction/history/all",
+	"GET /transfer",
+	"PATCH /auth/change-password",
+	"PATCH /auth/change-pin",
+	"PATCH /auth/update-pin",
+	"PATCH /profile",
+	"POST /auth",
+	"POST /auth/confirm-pin",
+	"POST /auth/forgot-password",
+	"POST /auth/forgot-pin",
+	"POST /auth/register",
+	"POST /auth/reset-password",
+	"POST /auth/reset-pin",
+	"POST /topup",
+	"POST /transfer",
+}
+
+// routes on root path which aren't part of the api
+var rootRoutes = []string{"/healthz", "/readyz", "/metrics", "/swagger/", "/img/", "/dev/"}
+
+func TestLegacyRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// client is lazy, nothing is connected while routes are mounted
+	rdb := redis.NewClient(&redis.Options{})
+	defer rdb.Close()
+
+	for _, enabled := range []bool{true, false} {
+		cfg := &configs.Config{
+			App:    configs.AppConfig{Env: "production"},
+			Legacy: configs.LegacyConfig{Enabled: enabled, DeprecatedAt: time.Now(), SunsetAt: time.Now().AddDate(0, 6, 0)},
+		}
+		router := routers.InitRouter(nil, rdb, utils.NewMemoryMailer("test@belalai.local", 0), utils.NewBackground(), cfg)
+
+		var legacy []string
+		v1 := map[string]bool{}
+		for _, r := range router.Routes() {
+			if path, ok := strings.CutPrefix(r.Path, "/v1"); ok {
+				v1[r.Method+" "+path] = true
+				continue
+			}
+			if slices.ContainsFunc(rootRoutes, func(p string) bool { return strings.HasPrefix(r.Path, p) }) {
+				continue
+			}
+			legacy = append(legacy, r.Method+" "+r.Path)
+		}
+		slices.Sort(legacy)
+
+		want := baselineRoutes
+		if !enabled {
+			want = nil
+		}
+		if !slices.Equal(legacy, want) {
+			t.Errorf("legacy enabled=%v routes = %q, want %q", enabled, legacy, want)
+		}
+		for _, route := range baselineRoutes {
+			if !v1[route] {
+				t.Errorf("legacy enabled=%v: %s isn't mounted on /v1", enabled, route)
+			}
+		}
+		if !v1["GET /transaction/statement"] || !v1["GET /budgets"] {
+			t.Errorf("legacy enabled=%v: route added after versioning isn't mounted on /v1", enabled)
+		}
+	}
+}
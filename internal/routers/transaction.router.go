@@ -1,21 +1,43 @@
 package routers
 
 import (
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
 	"github.com/Belalai-E-Wallet-Backend/internal/handler"
 	"github.com/Belalai-E-Wallet-Backend/internal/middleware"
 	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
 
-func InitTransactionRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
+func InitTransactionRouter(router, legacy gin.IRouter, db *pgxpool.Pool, rdb *redis.Client, mailer utils.Mailer, bg *utils.Background, cfg *configs.Config) {
 	transactionRouter := router.Group("/transaction")
 	transactionRepository := repository.NewTransactionRepository(db)
-	transactionHandler := handler.NewTransactionHandler(transactionRepository)
+	transactionHandler := handler.NewTransactionHandler(transactionRepository, mailer, bg, cfg)
 
-	transactionRouter.GET("/history", middleware.VerifyToken(rdb), transactionHandler.GetTransactionHistory)
-	transactionRouter.GET("/history/all", middleware.VerifyToken(rdb), transactionHandler.GetAllTransactionHistory)
-	transactionRouter.DELETE("/:id", middleware.VerifyToken(rdb), transactionHandler.DeleteTransaction)
-	transactionRouter.DELETE("/topup/:id", middleware.VerifyToken(rdb), transactionHandler.DeleteTopup)
+	transactionRouter.GET("/statement", middleware.VerifyToken(rdb, cfg.JWT), transactionHandler.GetStatement)
+	transactionRouter.GET("/trash", middleware.VerifyToken(rdb, cfg.JWT), transactionHandler.GetTrash)
+	transactionRouter.POST("/trash", middleware.VerifyToken(rdb, cfg.JWT), transactionHandler.HideTransactions)
+	transactionRouter.POST("/trash/restore", middleware.VerifyToken(rdb, cfg.JWT), transactionHandler.RestoreTransactions)
+	transactionRouter.GET("/:id", middleware.VerifyToken(rdb, cfg.JWT), transactionHandler.GetTransactionDetail)
+	transactionRouter.GET("/:id/receipt", middleware.VerifyToken(rdb, cfg.JWT), transactionHandler.GetTransactionReceipt)
+	transactionRouter.GET("/topup/:id", middleware.VerifyToken(rdb, cfg.JWT), transactionHandler.GetTopupDetail)
+	transactionRouter.GET("/topup/:id/receipt", middleware.VerifyToken(rdb, cfg.JWT), transactionHandler.GetTopupReceipt)
+	transactionRouter.POST("/:id/restore", middleware.VerifyToken(rdb, cfg.JWT), transactionHandler.RestoreTransaction)
+	transactionRouter.POST("/topup/:id/restore", middleware.VerifyToken(rdb, cfg.JWT), transactionHandler.RestoreTopup)
+	transactionRouter.PUT("/:id/category", middleware.VerifyToken(rdb, cfg.JWT), transactionHandler.SetTransactionCategory)
+	transactionRouter.PUT("/topup/:id/category", middleware.VerifyToken(rdb, cfg.JWT), transactionHandler.SetTopupCategory)
+
+	// public, receipt is shared to people without account
+	router.GET("/receipt/verify/:code", transactionHandler.VerifyReceipt)
+
+	// route before versioning, old client still call it on root path
+	for _, r := range withLegacy(router, legacy) {
+		baseline := r.Group("/transaction")
+		baseline.GET("/history", middleware.VerifyToken(rdb, cfg.JWT), transactionHandler.GetTransactionHistory)
+		baseline.GET("/history/all", middleware.VerifyToken(rdb, cfg.JWT), transactionHandler.GetAllTransactionHistory)
+		baseline.DELETE("/:id", middleware.VerifyToken(rdb, cfg.JWT), transactionHandler.DeleteTransaction)
+		baseline.DELETE("/topup/:id", middleware.VerifyToken(rdb, cfg.JWT), transactionHandler.DeleteTopup)
+	}
 }
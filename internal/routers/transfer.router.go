@@ -1,19 +1,24 @@
 package routers
 
 import (
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
 	"github.com/Belalai-E-Wallet-Backend/internal/handler"
 	"github.com/Belalai-E-Wallet-Backend/internal/middleware"
 	"github.com/Belalai-E-Wallet-Backend/internal/repository"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
 
-func InitTransferRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
-	transferRouter := router.Group("/transfer")
+func InitTransferRouter(router, legacy gin.IRouter, db *pgxpool.Pool, rdb *redis.Client, mailer utils.Mailer, bg *utils.Background, cfg *configs.Config) {
 	transferRepository := repository.NewTransferRepository(db, rdb)
-	uh := handler.NewTransferHandler(transferRepository)
+	budgetHandler := handler.NewBudgetHandler(repository.NewBudgetRepository(db, rdb), repository.NewChartRepository(db), mailer, bg, cfg)
+	uh := handler.NewTransferHandler(transferRepository, mailer, bg, cfg, budgetHandler)
 
-	transferRouter.GET("", middleware.VerifyToken(rdb), uh.FilterUser)
-	transferRouter.POST("", middleware.VerifyToken(rdb), uh.TranferBalance)
+	for _, r := range withLegacy(router, legacy) {
+		transferRouter := r.Group("/transfer")
+		transferRouter.GET("", middleware.VerifyToken(rdb, cfg.JWT), uh.FilterUser)
+		transferRouter.POST("", middleware.VerifyToken(rdb, cfg.JWT), uh.TranferBalance)
+	}
 }
@@ -1,6 +1,7 @@
 package routers
 
 import (
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
 	"github.com/Belalai-E-Wallet-Backend/internal/handler"
 	"github.com/Belalai-E-Wallet-Backend/internal/middleware"
 	"github.com/Belalai-E-Wallet-Backend/internal/repository"
@@ -9,19 +10,21 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-func InitTopUpRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
-	topupRouter := router.Group("/topup")
-	topupRepository := repository.NewTopUpRepository(db)
+func InitTopUpRouter(router, legacy gin.IRouter, db *pgxpool.Pool, rdb *redis.Client, cfg *configs.Config) {
+	topupRepository := repository.NewTopUpRepository(db, rdb)
 	topupHandler := handler.NewTopUpHandler(topupRepository)
 
-	topupRouter.GET("/methods", middleware.VerifyToken(rdb), topupHandler.GetPaymentMethods)
-	// topupRouter.POST("", middleware.VerifyToken(rdb), topupHandler.CreateTopUp)
-	// topupRouter.PATCH("/:id/success", middleware.VerifyToken(rdb), topupHandler.MarkTopUpSuccess)
+	for _, r := range withLegacy(router, legacy) {
+		topupRouter := r.Group("/topup")
+		topupRouter.GET("/methods", middleware.VerifyToken(rdb, cfg.JWT), topupHandler.GetPaymentMethods)
+		// topupRouter.POST("", middleware.VerifyToken(rdb, cfg.JWT), topupHandler.CreateTopUp)
+		// topupRouter.PATCH("/:id/success", middleware.VerifyToken(rdb, cfg.JWT), topupHandler.MarkTopUpSuccess)
 
-	// {
-	// 	"amount": 100000,
-	// 	"tax": 2500,
-	// 	"payment_id": 2
-	// }
-	topupRouter.POST("", middleware.VerifyToken(rdb), topupHandler.CreateTopUpTransaction)
+		// {
+		// 	"amount": 100000,
+		// 	"tax": 2500,
+		// 	"payment_id": 2
+		// }
+		topupRouter.POST("", middleware.VerifyToken(rdb, cfg.JWT), topupHandler.CreateTopUpTransaction)
+	}
 }
@@ -0,0 +1,44 @@
+package routers
+
+import (
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// InitV1Router mount version 1 of API on /v1 router, routes which exist before versioning are also mounted on
+// legacy (root path for old client), nil legacy mount /v1 only. Handler is built once and shared by both.
+// breaking change go to new InitV2Router mounted on /v2, unchanged router can be reused there
+func InitV1Router(router, legacy gin.IRouter, db *pgxpool.Pool, rdb *redis.Client, mailer utils.Mailer, bg *utils.Background, cfg *configs.Config) {
+	InitAuthRouter(router, legacy, db, rdb, mailer, bg, cfg)
+
+	InitTransferRouter(router, legacy, db, rdb, mailer, bg, cfg)
+
+	InitEWalletRouter(router, legacy, db, rdb, cfg)
+
+	InitTransactionRouter(router, legacy, db, rdb, mailer, bg, cfg)
+
+	InitProfileRouter(router, legacy, db, rdb, cfg)
+
+	InitTopUpRouter(router, legacy, db, rdb, cfg)
+
+	InitChartRoouter(router, legacy, db, rdb, cfg)
+
+	// added after versioning, /v1 only
+
+	InitCategoryRouter(router, db, rdb, cfg)
+
+	InitBudgetRouter(router, db, rdb, mailer, bg, cfg)
+
+	InitEventRouter(router, db, rdb, bg, cfg)
+}
+
+// withLegacy return routers where baseline route is mounted, old client only know route before versioning
+func withLegacy(router, legacy gin.IRouter) []gin.IRouter {
+	if legacy == nil {
+		return []gin.IRouter{router}
+	}
+	return []gin.IRouter{router, legacy}
+}
@@ -0,0 +1,221 @@
+// Package seed fill database with demo data: payment methods, users with wallet and a year of history
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/pkg"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EmailDomain is domain of every seeded user, ex: seed1@belalai.test
+const EmailDomain = "belalai.test"
+
+var paymentMethods = []string{"BRI", "BCA", "BNI", "Mandiri", "Dana", "OVO", "Gopay"}
+
+var (
+	firstNames = []string{"Budi", "Siti", "Agus", "Dewi", "Andi", "Rina", "Joko", "Putri", "Rizky", "Ayu", "Dimas", "Intan", "Fajar", "Nur", "Hendra", "Wulan"}
+	lastNames  = []string{"Santoso", "Wijaya", "Saputra", "Lestari", "Pratama", "Hidayat", "Kusuma", "Nugroho", "Permata", "Setiawan", "Utami", "Halim"}
+	notes      = []string{"", "", "makan siang", "bayar kos", "patungan kado", "uang bensin", "titip belanja", "bayar utang", "arisan", "tiket nonton"}
+)
+
+type Options struct {
+	Users  int
+	Months int
+	// plain password & pin of every user, so they can login on the app
+	Password string
+	PIN      string
+	// same seed produce the same data, 0 pick a random one
+	Seed uint64
+}
+
+type Result struct {
+	Seed      uint64
+	Users     int
+	TopUps    int
+	Transfers int
+}
+
+type user struct {
+	id, walletID int
+	balance      int
+}
+
+// event is topup (receiver only) or transfer, simulated in time order so balance never go negative
+type event struct {
+	at       time.Time
+	sender   *user
+	receiver *user
+	amount   int
+}
+
+// Run create everything in one transaction, can be run again to add more users
+func Run(c context.Context, db *pgxpool.Pool, opt Options) (Result, error) {
+	if opt.Users <= 0 || opt.Months <= 0 {
+		return Result{}, errors.New("users and months must be greater than 0")
+	}
+	if opt.Seed == 0 {
+		opt.Seed = rand.Uint64()
+	}
+	r := rand.New(rand.NewPCG(opt.Seed, opt.Seed))
+	result := Result{Seed: opt.Seed}
+
+	// hashing is slow, every user share the same hash
+	hc := pkg.NewHashConfig()
+	hc.UseRecommended()
+	password, err := hc.GenHash(opt.Password)
+	if err != nil {
+		return result, err
+	}
+	pin, err := hc.GenHash(opt.PIN)
+	if err != nil {
+		return result, err
+	}
+
+	tx, err := db.Begin(c)
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback(c)
+
+	paymentIDs, err := seedPaymentMethods(c, tx)
+	if err != nil {
+		return result, fmt.Errorf("seed payment method: %w", err)
+	}
+
+	now := time.Now()
+	start := now.AddDate(0, -opt.Months, 0)
+	users, err := seedUsers(c, tx, r, opt.Users, password, pin, start)
+	if err != nil {
+		return result, fmt.Errorf("seed user: %w", err)
+	}
+	result.Users = len(users)
+
+	batch := &pgx.Batch{}
+	for _, e := range history(r, users, opt.Months, start, now) {
+		if e.sender == nil {
+			status := topupStatus(r)
+			if status == models.TopUpSuccess {
+				e.receiver.balance += e.amount
+			}
+			batch.Queue(`WITH t AS (
+				INSERT INTO topup (amount, tax, payment_id, topup_status, created_at, updated_at) VALUES ($1, 1000, $2, $3, $4, $4) RETURNING id
+			) INSERT INTO wallets_topup (wallets_id, topup_id) SELECT $5, id FROM t`,
+				e.amount, paymentIDs[r.IntN(len(paymentIDs))], status, e.at, e.receiver.walletID)
+			result.TopUps++
+			continue
+		}
+
+		// like the app, transfer is only made when sender has enough balance
+		amount := min(e.amount, e.sender.balance/1000*1000)
+		if amount <= 0 {
+			continue
+		}
+		e.sender.balance -= amount
+		e.receiver.balance += amount
+		batch.Queue(`WITH t AS (
+			INSERT INTO transfer (sender_wallet_id, receiver_wallet_id, amount, transfer_status, notes, created_at, updated_at)
+			VALUES ($1, $2, $3, 'success', $4, $5, $5) RETURNING id
+		) INSERT INTO wallets_transfer (wallets_id, transfer_id) SELECT w, id FROM t, unnest(ARRAY[$1, $2]::int[]) w`,
+			e.sender.walletID, e.receiver.walletID, amount, notes[r.IntN(len(notes))], e.at)
+		result.Transfers++
+	}
+	for _, u := range users {
+		batch.Queue(`UPDATE wallets SET balance = $1, updated_at = $2 WHERE id = $3`, u.balance, now, u.walletID)
+	}
+	if err := tx.SendBatch(c, batch).Close(); err != nil {
+		return result, fmt.Errorf("seed history: %w", err)
+	}
+
+	return result, tx.Commit(c)
+}
+
+// payment method is created once, seeding again reuse it
+func seedPaymentMethods(c context.Context, tx pgx.Tx) ([]int, error) {
+	for _, name := range paymentMethods {
+		sql := `INSERT INTO payment_method (name) SELECT $1::text WHERE NOT EXISTS (SELECT 1 FROM payment_method WHERE name = $1::text)`
+		if _, err := tx.Exec(c, sql, name); err != nil {
+			return nil, err
+		}
+	}
+	rows, err := tx.Query(c, `SELECT id FROM payment_method ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
+
+// email & phone continue after the last seeded user, phone is unique and valid indonesian mobile number
+func seedUsers(c context.Context, tx pgx.Tx, r *rand.Rand, n int, password, pin string, start time.Time) ([]*user, error) {
+	var offset int
+	sql := `SELECT COALESCE(MAX(substring(email FROM '^seed(\d+)@')::int), 0) FROM users WHERE email LIKE 'seed%@' || $1`
+	if err := tx.QueryRow(c, sql, EmailDomain).Scan(&offset); err != nil {
+		return nil, err
+	}
+
+	users := make([]*user, 0, n)
+	for i := offset + 1; i <= offset+n; i++ {
+		// registered a few days before the first history
+		createdAt := start.AddDate(0, 0, -r.IntN(30)-1)
+		u := &user{}
+		sql = `INSERT INTO users (email, password, pin, created_at, updated_at) VALUES ($1, $2, $3, $4, $4) RETURNING id`
+		if err := tx.QueryRow(c, sql, fmt.Sprintf("seed%d@%s", i, EmailDomain), password, pin, createdAt).Scan(&u.id); err != nil {
+			return nil, err
+		}
+		fullname := firstNames[r.IntN(len(firstNames))] + " " + lastNames[r.IntN(len(lastNames))]
+		sql = `INSERT INTO profile (user_id, fullname, phone, created_at, updated_at) VALUES ($1, $2, $3, $4, $4)`
+		if _, err := tx.Exec(c, sql, u.id, fullname, fmt.Sprintf("0812%08d", i), createdAt); err != nil {
+			return nil, err
+		}
+		sql = `INSERT INTO wallets (user_id, created_at, updated_at) VALUES ($1, $2, $2) RETURNING id`
+		if err := tx.QueryRow(c, sql, u.id, createdAt).Scan(&u.walletID); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+// every user topup 1-3 times and transfer 2-6 times a month on average
+func history(r *rand.Rand, users []*user, months int, start, end time.Time) []event {
+	span := end.Sub(start)
+	at := func() time.Time { return start.Add(time.Duration(r.Int64N(int64(span)))) }
+
+	var events []event
+	for _, u := range users {
+		for range months * (1 + r.IntN(3)) {
+			events = append(events, event{at: at(), receiver: u, amount: (5 + r.IntN(196)) * 10000})
+		}
+		if len(users) < 2 {
+			continue
+		}
+		for range months * (2 + r.IntN(5)) {
+			receiver := users[r.IntN(len(users))]
+			for receiver == u {
+				receiver = users[r.IntN(len(users))]
+			}
+			events = append(events, event{at: at(), sender: u, receiver: receiver, amount: (5 + r.IntN(496)) * 1000})
+		}
+	}
+	slices.SortFunc(events, func(a, b event) int { return a.at.Compare(b.at) })
+	return events
+}
+
+// most topup success, failed & pending doesn't add balance
+func topupStatus(r *rand.Rand) models.TopUpStatus {
+	switch n := r.IntN(20); {
+	case n == 0:
+		return models.TopUpFailed
+	case n == 1:
+		return models.TopUpPending
+	default:
+		return models.TopUpSuccess
+	}
+}
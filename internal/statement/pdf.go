@@ -0,0 +1,162 @@
+package statement
+
+import (
+	"io"
+	"strings"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/i18n"
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/receipt"
+	"github.com/Belalai-E-Wallet-Backend/internal/utils"
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	pdfMargin    = 12.0
+	pdfRowHeight = 6.0
+)
+
+// width of date, reference, description, debit, credit & balance column on A4 portrait
+var pdfColumns = []float64{28, 40, 46, 24, 24, 24}
+
+type pdfWriter struct {
+	totals
+	pdf *gofpdf.Fpdf
+	out io.Writer
+	h   Header
+	t   func(key string, args ...any) string
+	// built-in font is cp1252, text is utf-8
+	tr func(string) string
+}
+
+// NewPDF write statement on A4 pages, table header is repeated on every page
+// PDF is only written to w on Close
+func NewPDF(w io.Writer, h Header) Writer {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pw := &pdfWriter{
+		totals: totals{balance: h.Opening},
+		pdf:    pdf,
+		out:    w,
+		h:      h,
+		t:      func(key string, args ...any) string { return i18n.Translate(h.Lang, key, args...) },
+		tr:     pdf.UnicodeTranslatorFromDescriptor(""),
+	}
+
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(false, pdfMargin)
+	pdf.SetTitle(pw.t("statement.title"), true)
+	pdf.SetCreator(h.AppName, true)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pdfMargin)
+		pdf.SetFont("Helvetica", "", 7)
+		pdf.SetTextColor(110, 110, 110)
+		pdf.CellFormat(0, 4, pw.tr(pw.t("statement.page", pdf.PageNo(), "{nb}")), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(0, 8, pw.tr(h.AppName), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(0, 6, pw.tr(pw.t("statement.title")), "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+	pw.summary(pw.t("statement.name"), h.Name)
+	pw.summary(pw.t("statement.period"), h.From.Format("02/01/2006")+" - "+h.To.Format("02/01/2006"))
+	pw.summary(pw.t("statement.opening"), utils.FormatRupiah(h.Opening))
+	pdf.Ln(4)
+	pw.tableHeader()
+	return pw
+}
+
+func (pw *pdfWriter) summary(label, value string) {
+	pw.pdf.SetFont("Helvetica", "", 9)
+	pw.pdf.SetTextColor(110, 110, 110)
+	pw.pdf.CellFormat(35, 5, pw.tr(label), "", 0, "L", false, 0, "")
+	pw.pdf.SetFont("Helvetica", "B", 9)
+	pw.pdf.SetTextColor(0, 0, 0)
+	pw.pdf.CellFormat(0, 5, pw.tr(value), "", 1, "L", false, 0, "")
+}
+
+func (pw *pdfWriter) tableHeader() {
+	labels := []string{"date", "reference", "description", "debit", "credit", "balance"}
+	pw.pdf.SetFont("Helvetica", "B", 8)
+	pw.pdf.SetFillColor(238, 238, 238)
+	pw.pdf.SetTextColor(0, 0, 0)
+	for i, label := range labels {
+		align := "L"
+		if i >= 3 {
+			align = "R"
+		}
+		pw.pdf.CellFormat(pdfColumns[i], pdfRowHeight, pw.tr(pw.t("statement.column."+label)), "", 0, align, true, 0, "")
+	}
+	pw.pdf.Ln(-1)
+}
+
+// row start new page when it doesn't fit above the footer
+func (pw *pdfWriter) row(values []string, bold bool) {
+	_, pageHeight := pw.pdf.GetPageSize()
+	if pw.pdf.GetY()+pdfRowHeight > pageHeight-2*pdfMargin {
+		pw.pdf.AddPage()
+		pw.tableHeader()
+	}
+
+	style := ""
+	if bold {
+		style = "B"
+	}
+	pw.pdf.SetFont("Helvetica", style, 8)
+	for i, value := range values {
+		align := "L"
+		if i >= 3 {
+			align = "R"
+		}
+		pw.pdf.CellFormat(pdfColumns[i], pdfRowHeight, pw.fit(pw.tr(value), pdfColumns[i]-2), "B", 0, align, false, 0, "")
+	}
+	pw.pdf.Ln(-1)
+}
+
+// fit cut text so it doesn't overflow column of width
+func (pw *pdfWriter) fit(text string, width float64) string {
+	if pw.pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	for len(text) > 0 && pw.pdf.GetStringWidth(text+"...") > width {
+		text = text[:len(text)-1]
+	}
+	return text + "..."
+}
+
+func (pw *pdfWriter) Add(e models.StatementEntry) error {
+	pw.add(e)
+	description := pw.t("statement.entry."+entryType(e.Type), e.Counterparty)
+	if e.Notes != "" {
+		description += " - " + e.Notes
+	}
+	pw.row([]string{
+		e.CreatedAt.Format("02/01/2006 15:04"),
+		receipt.Reference(e.Kind, e.ID, e.CreatedAt),
+		description,
+		amount(e.Debit),
+		amount(e.Credit),
+		number(pw.balance),
+	}, false)
+	return pw.pdf.Error()
+}
+
+func (pw *pdfWriter) Close() error {
+	pw.row([]string{pw.h.To.Format("02/01/2006"), "", pw.t("statement.closing"), amount(pw.debit), amount(pw.credit), number(pw.balance)}, true)
+	return pw.pdf.Output(pw.out)
+}
+
+// zero amount is left empty, entry is either debit or credit
+func amount(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return number(n)
+}
+
+// number is rupiah without currency, currency is on the column header
+func number(n int) string {
+	return strings.Replace(utils.FormatRupiah(n), "Rp ", "", 1)
+}
@@ -0,0 +1,128 @@
+// Package statement write account statement: opening balance, every entry with running balance and closing balance.
+// entry is written as it is read from database, so statement of long range doesn't need to be held in memory
+package statement
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/receipt"
+)
+
+// Header is what is known before the first entry
+type Header struct {
+	AppName string
+	// name of account holder
+	Name string
+	// language of PDF, CSV is always in english so it can be parsed
+	Lang string
+	// From & To is date, both inclusive
+	From    time.Time
+	To      time.Time
+	Opening int
+}
+
+// Writer receive entry oldest first, Close write the closing balance
+type Writer interface {
+	Add(e models.StatementEntry) error
+	Close() error
+}
+
+// ContentType & Extension of every format
+var (
+	ContentType = map[string]string{"csv": "text/csv; charset=utf-8", "pdf": "application/pdf"}
+	Extension   = map[string]string{"csv": "csv", "pdf": "pdf"}
+)
+
+// New return writer of format (csv or pdf)
+func New(format string, w io.Writer, h Header) (Writer, error) {
+	switch format {
+	case "csv":
+		return NewCSV(w, h)
+	case "pdf":
+		return NewPDF(w, h), nil
+	}
+	return nil, fmt.Errorf("unknown statement format %q", format)
+}
+
+// Filename of statement, ex: statement-20260101-20260131.pdf
+func Filename(format string, from, to time.Time) string {
+	return fmt.Sprintf("statement-%s-%s.%s", from.Format("20060102"), to.Format("20060102"), Extension[format])
+}
+
+// totals is running balance shared by every format
+type totals struct {
+	balance int
+	debit   int
+	credit  int
+}
+
+func (t *totals) add(e models.StatementEntry) {
+	t.balance += e.Credit - e.Debit
+	t.debit += e.Debit
+	t.credit += e.Credit
+}
+
+// entryType is type of history filter (sent, received, topup) of transaction_type
+func entryType(transactionType string) string {
+	for key, value := range models.HistoryTypes {
+		if value == transactionType {
+			return key
+		}
+	}
+	return transactionType
+}
+
+type csvWriter struct {
+	totals
+	w  *csv.Writer
+	to time.Time
+}
+
+// NewCSV write header & opening balance row, amount is plain number so it can be summed by spreadsheet
+func NewCSV(w io.Writer, h Header) (Writer, error) {
+	cw := &csvWriter{totals: totals{balance: h.Opening}, w: csv.NewWriter(w), to: h.To}
+	if err := cw.w.Write([]string{"date", "reference", "type", "counterparty", "notes", "debit", "credit", "balance"}); err != nil {
+		return nil, err
+	}
+	if err := cw.w.Write([]string{h.From.Format(time.DateOnly), "", "opening_balance", "", "", "", "", strconv.Itoa(h.Opening)}); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvWriter) Add(e models.StatementEntry) error {
+	cw.add(e)
+	return cw.w.Write([]string{
+		e.CreatedAt.Format(time.DateTime),
+		receipt.Reference(e.Kind, e.ID, e.CreatedAt),
+		entryType(e.Type),
+		csvText(e.Counterparty),
+		csvText(e.Notes),
+		strconv.Itoa(e.Debit),
+		strconv.Itoa(e.Credit),
+		strconv.Itoa(cw.balance),
+	})
+}
+
+// csvText escape text written by other user, cell starting with = + - @ tab or CR is run as formula
+// by Excel & Sheets, the ' prefix make it plain text
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func (cw *csvWriter) Close() error {
+	if err := cw.w.Write([]string{cw.to.Format(time.DateOnly), "", "closing_balance", "", "", strconv.Itoa(cw.debit), strconv.Itoa(cw.credit), strconv.Itoa(cw.balance)}); err != nil {
+		return err
+	}
+	cw.w.Flush()
+	return cw.w.Error()
+}
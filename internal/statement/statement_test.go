@@ -0,0 +1,59 @@
+package statement_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/Belalai-E-Wallet-Backend/internal/statement"
+)
+
+func TestCSVFormulaInjection(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{`=HYPERLINK("http://evil.test","klik")`, `'=HYPERLINK("http://evil.test","klik")`},
+		{"+62812", "'+62812"},
+		{"-1+1", "'-1+1"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1+1", "'\t=1+1"},
+		{"\r=1+1", "'\r=1+1"},
+		{"makan siang", "makan siang"},
+		{"bayar = lunas", "bayar = lunas"},
+		{"", ""},
+	}
+
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	w, err := statement.NewCSV(&buf, statement.Header{From: day, To: day})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, tt := range tests {
+		entry := models.StatementEntry{ID: i + 1, Kind: "transfer", Type: "Transfer", Counterparty: tt.value, Notes: tt.value, Credit: 1000, CreatedAt: day}
+		if err := w.Add(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// header, opening balance, entries and closing balance
+	if len(rows) != len(tests)+3 {
+		t.Fatalf("got %d rows, want %d", len(rows), len(tests)+3)
+	}
+	for i, tt := range tests {
+		row := rows[i+2]
+		if row[3] != tt.want || row[4] != tt.want {
+			t.Errorf("counterparty, notes = %q, %q, want %q", row[3], row[4], tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// name of tracer used by every span in this project
+const instrumentation = "github.com/Belalai-E-Wallet-Backend"
+
+// exporter of span
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+type Options struct {
+	ServiceName string
+	Environment string
+	// none, stdout or otlp
+	Exporter string
+	// OTLP/HTTP collector, ex: http://localhost:4318
+	Endpoint string
+	// 0..1, ratio of new trace that is recorded
+	SampleRatio float64
+}
+
+// Init setup global tracer provider and W3C traceparent propagator
+// returned shutdown must be called on exit so buffered span is flushed
+func Init(c context.Context, opt Options) (func(context.Context) error, error) {
+	// propagator is always set, so trace id from upstream is kept even when exporter is none
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch opt.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if opt.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(opt.Endpoint))
+		}
+		exporter, err = otlptracehttp.New(c, opts...)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", opt.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create %s exporter: %w", opt.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(opt.ServiceName),
+		semconv.DeploymentEnvironmentName(opt.Environment),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// follow sampling decision of caller, so one trace is never half recorded
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opt.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Tracer return tracer of this project, from global provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Background run task outside of request lifecycle (ex: sending email)
+// so it can be drained on shutdown instead of killed in the middle
+type Background struct {
+	wg       sync.WaitGroup
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopping chan struct{}
+	stopOnce sync.Once
+}
+
+func NewBackground() *Background {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Background{ctx: ctx, cancel: cancel, stopping: make(chan struct{})}
+}
+
+// Go run task in new goroutine
+// ctx of task keep value of parent (logger, trace) but is not cancelled when request end,
+// it is only cancelled when Wait run out of time
+func (b *Background) Go(parent context.Context, task func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(parent))
+	stop := context.AfterFunc(b.ctx, cancel)
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		defer cancel()
+		defer stop()
+		task(ctx)
+	}()
+}
+
+// Every run task every interval until shutdown begin, a running task is waited like task of Go
+func (b *Background) Every(parent context.Context, interval time.Duration, task func(ctx context.Context)) {
+	b.Go(parent, func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-b.stopping:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				task(ctx)
+			}
+		}
+	})
+}
+
+// Stopping is closed when shutdown begin, long-lived handler (stream, websocket) should return
+func (b *Background) Stopping() <-chan struct{} {
+	return b.stopping
+}
+
+// Stop mark shutdown is started, safe to be called more than once
+func (b *Background) Stop() {
+	b.stopOnce.Do(func() { close(b.stopping) })
+}
+
+// Wait block until all task finish or ctx is done
+// when ctx is done, running task is cancelled and ctx error is returned
+func (b *Background) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		b.cancel()
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	}
+}
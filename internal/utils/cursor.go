@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+)
+
+// CompareCursor order row like ORDER BY created_at DESC, id DESC, kind DESC,
+// negative mean row a come first (newer)
+func CompareCursor(a, b models.Cursor) int {
+	if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(b.ID, a.ID); c != 0 {
+		return c
+	}
+	return cmp.Compare(b.Kind, a.Kind)
+}
+
+// CursorPageOf build page from rows queried after cursor with limit+1,
+// rows of backward cursor is oldest first (the reverse order) and flipped here
+func CursorPageOf[T any](rows []T, cursor *models.Cursor, limit int, cursorOf func(T) models.Cursor) models.CursorPage[T] {
+	backward := cursor != nil && cursor.Backward
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	if backward {
+		slices.Reverse(rows)
+	}
+
+	page := models.CursorPage[T]{Items: rows}
+	at := func(i int, backward bool) *models.Cursor {
+		c := cursorOf(rows[i])
+		c.Backward = backward
+		return &c
+	}
+	if len(rows) == 0 {
+		// nothing after cursor, page before is still reachable from the same position
+		if cursor != nil && !backward {
+			page.Prev = &models.Cursor{CreatedAt: cursor.CreatedAt, ID: cursor.ID, Kind: cursor.Kind, Backward: true}
+		}
+		return page
+	}
+	if backward || hasMore {
+		page.Next = at(len(rows)-1, false)
+	}
+	if (backward && hasMore) || (!backward && cursor != nil) {
+		page.Prev = at(0, true)
+	}
+	return page
+}
+
+// HistoryCursor is position of transaction history row
+func HistoryCursor(h models.TransactionHistory) models.Cursor {
+	return models.Cursor{CreatedAt: h.CreatedAt, ID: h.ID, Kind: h.Kind}
+}
+
+// ProfileCursor is position of user on search result
+func ProfileCursor(p models.ProfileResponse) models.Cursor {
+	var createdAt time.Time
+	if p.CreatedAt != nil {
+		createdAt = *p.CreatedAt
+	}
+	return models.Cursor{CreatedAt: createdAt, ID: p.UserID}
+}
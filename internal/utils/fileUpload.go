@@ -1,33 +1,33 @@
 package utils
 
 import (
-	"errors"
 	"fmt"
 	"mime/multipart"
 	"path/filepath"
 	"regexp"
 	"time"
 
+	"github.com/Belalai-E-Wallet-Backend/internal/apperror"
 	"github.com/gin-gonic/gin"
 )
 
 func FileUpload(c *gin.Context, file *multipart.FileHeader, prefix string) (string, error) {
 	const maxSize = 2 * 1024 * 1024
 	if file.Size > maxSize {
-		return "", errors.New("file too large (max 2MB)")
+		return "", apperror.New(apperror.FileTooLarge)
 	}
 
 	ext := filepath.Ext(file.Filename)
 	re := regexp.MustCompile(`(?i)\.(png|jpg|jpeg|webp)$`)
 	if !re.MatchString(ext) {
-		return "", errors.New("invalid file type (only PNG, JPG, JPEG, WEBP allowed)")
+		return "", apperror.New(apperror.FileTypeInvalid)
 	}
 
 	filename := fmt.Sprintf("%s_%d%s", prefix, time.Now().UnixNano(), ext)
 	location := filepath.Join("public", filename)
 
 	if err := c.SaveUploadedFile(file, location); err != nil {
-		return "", errors.New("failed to save file")
+		return "", apperror.Wrap(apperror.Internal, err)
 	}
 
 	return filename, nil
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+	"github.com/Belalai-E-Wallet-Backend/internal/i18n"
+)
+
+//go:embed mailtemplates
+var mailTemplateFS embed.FS
+
+// name of email template, each template has html and plain-text version for every language
+const (
+	MailWelcome          = "welcome"
+	MailResetPassword    = "reset_password"
+	MailResetPIN         = "reset_pin"
+	MailTransferReceived = "transfer_received"
+	MailSecurityAlert    = "security_alert"
+	MailStatement        = "statement"
+	MailBudgetAlert      = "budget_alert"
+)
+
+// MailData is data passed into every template
+// Lang is filled by RenderMail
+type MailData struct {
+	Brand configs.BrandingConfig
+	Lang  string
+	Name  string
+	// template specific data, ex: link reset, amount transfer
+	Data map[string]any
+}
+
+// mailLang return supported language of recipient, unsupported language fallback to i18n.Default
+// like message of API, so mail and response of the same user never disagree
+func mailLang(lang string) string {
+	if lang = i18n.Normalize(lang); lang != "" {
+		return lang
+	}
+	return i18n.Default
+}
+
+var mailFuncs = map[string]any{
+	"rupiah": FormatRupiah,
+	"datetime": func(t time.Time) string {
+		return t.Format("02 Jan 2006 15:04 MST")
+	},
+}
+
+// RenderMail render html & plain-text template in the given language
+// the result is ready to be sent with Send
+func RenderMail(name, lang string, data MailData) (SendOptions, error) {
+	data.Lang = mailLang(lang)
+
+	// plain-text template define "subject" and "body"
+	txtTmpl, err := texttemplate.New(name).Funcs(mailFuncs).ParseFS(mailTemplateFS, fmt.Sprintf("mailtemplates/%s/%s.txt", data.Lang, name))
+	if err != nil {
+		return SendOptions{}, fmt.Errorf("parse text template %s: %w", name, err)
+	}
+	var subject, text bytes.Buffer
+	if err := txtTmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return SendOptions{}, fmt.Errorf("render subject %s: %w", name, err)
+	}
+	if err := txtTmpl.ExecuteTemplate(&text, "body", data); err != nil {
+		return SendOptions{}, fmt.Errorf("render text %s: %w", name, err)
+	}
+
+	// html template define "content", wrapped by layout
+	htmlTmpl, err := htmltemplate.New("layout.html").Funcs(mailFuncs).ParseFS(mailTemplateFS, "mailtemplates/layout.html", fmt.Sprintf("mailtemplates/%s/%s.html", data.Lang, name))
+	if err != nil {
+		return SendOptions{}, fmt.Errorf("parse html template %s: %w", name, err)
+	}
+	var html bytes.Buffer
+	if err := htmlTmpl.Execute(&html, data); err != nil {
+		return SendOptions{}, fmt.Errorf("render html %s: %w", name, err)
+	}
+
+	return SendOptions{
+		Subject:    strings.TrimSpace(subject.String()),
+		Body:       html.String(),
+		BodyIsHTML: true,
+		AltBody:    strings.TrimSpace(text.String()),
+	}, nil
+}
+
+// SendTemplate render template and send it to recipient using mailer
+func SendTemplate(c context.Context, mailer Mailer, to string, name, lang string, data MailData) error {
+	opt, err := RenderMail(name, lang, data)
+	if err != nil {
+		return err
+	}
+	opt.To = []string{to}
+	return mailer.Send(c, opt)
+}
+
+// FormatRupiah format amount into "Rp 1.000.000"
+func FormatRupiah(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := fmt.Sprintf("%d", amount)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	return sign + "Rp " + b.String()
+}
@@ -0,0 +1,264 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
+)
+
+// transport of email, selected by config MAIL_TRANSPORT
+const (
+	MailTransportSMTP   = "smtp"
+	MailTransportFile   = "file"
+	MailTransportMemory = "memory"
+)
+
+var ErrMailNotFound = errors.New("mail not found")
+
+// Mailer is transport used to deliver email
+// inject it into handler instead of dial SMTP directly, so local development & test doesn't need real server
+type Mailer interface {
+	Send(c context.Context, opt SendOptions) error
+}
+
+// MailCapture is mailer that keep sent mail, used by dev endpoint to view captured mail
+type MailCapture interface {
+	Mailer
+	List(c context.Context) ([]CapturedMail, error)
+	Get(c context.Context, id string) (*CapturedMail, error)
+	Clear(c context.Context) error
+}
+
+// MailPinger is mailer that can check its transport is reachable, used by readiness check
+type MailPinger interface {
+	Ping(c context.Context) error
+}
+
+type CapturedMail struct {
+	ID         string   `json:"id"`
+	From       string   `json:"from"`
+	To         []string `json:"to"`
+	Cc         []string `json:"cc,omitempty"`
+	Bcc        []string `json:"bcc,omitempty"`
+	Subject    string   `json:"subject"`
+	Body       string   `json:"body"`
+	BodyIsHTML bool     `json:"body_is_html"`
+	AltBody    string   `json:"alt_body,omitempty"`
+	// name of attached file
+	Attachments []string  `json:"attachments,omitempty"`
+	SentAt      time.Time `json:"sent_at"`
+}
+
+func newCapturedMail(from string, opt SendOptions) CapturedMail {
+	now := time.Now()
+	var attachments []string
+	for _, f := range opt.Attachments {
+		attachments = append(attachments, filepath.Base(f))
+	}
+	for _, f := range opt.Files {
+		attachments = append(attachments, f.Name)
+	}
+	return CapturedMail{
+		ID:          fmt.Sprintf("%d", now.UnixNano()),
+		From:        from,
+		To:          opt.To,
+		Cc:          opt.Cc,
+		Bcc:         opt.Bcc,
+		Subject:     opt.Subject,
+		Body:        opt.Body,
+		BodyIsHTML:  opt.BodyIsHTML,
+		AltBody:     opt.AltBody,
+		Attachments: attachments,
+		SentAt:      now,
+	}
+}
+
+// NewMailer create mailer based on config mail transport
+func NewMailer(cfg configs.MailConfig) (Mailer, error) {
+	switch cfg.Transport {
+	case MailTransportSMTP:
+		return NewSMTPMailer(cfg.SMTP), nil
+	case MailTransportFile:
+		return NewFileMailer(cfg.Dir, cfg.SMTP.From)
+	case MailTransportMemory:
+		return NewMemoryMailer(cfg.SMTP.From, 100), nil
+	default:
+		return nil, fmt.Errorf("unknown MAIL_TRANSPORT %q", cfg.Transport)
+	}
+}
+
+// ===================== { In memory } =====================
+
+// MemoryMailer keep last N mail in memory, never send anything
+type MemoryMailer struct {
+	mu    sync.RWMutex
+	from  string
+	limit int
+	mails []CapturedMail
+}
+
+func NewMemoryMailer(from string, limit int) *MemoryMailer {
+	return &MemoryMailer{from: from, limit: limit}
+}
+
+func (m *MemoryMailer) Send(c context.Context, opt SendOptions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mails = append(m.mails, newCapturedMail(m.from, opt))
+	if m.limit > 0 && len(m.mails) > m.limit {
+		m.mails = m.mails[len(m.mails)-m.limit:]
+	}
+	return nil
+}
+
+// List return captured mail, newest first
+func (m *MemoryMailer) List(c context.Context) ([]CapturedMail, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	mails := make([]CapturedMail, 0, len(m.mails))
+	for i := len(m.mails) - 1; i >= 0; i-- {
+		mails = append(mails, m.mails[i])
+	}
+	return mails, nil
+}
+
+func (m *MemoryMailer) Get(c context.Context, id string) (*CapturedMail, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, mail := range m.mails {
+		if mail.ID == id {
+			return &mail, nil
+		}
+	}
+	return nil, ErrMailNotFound
+}
+
+func (m *MemoryMailer) Clear(c context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mails = nil
+	return nil
+}
+
+// ===================== { File / maildir } =====================
+
+// FileMailer write every mail into maildir layout <dir>/new/<id>.eml
+// .eml can be opened with any email client, <dir>/<id>.json is used by dev endpoint
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("create mail dir: %w", err)
+		}
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+func (f *FileMailer) Send(c context.Context, opt SendOptions) error {
+	captured := newCapturedMail(f.from, opt)
+
+	// write on tmp first then move into new, so reader never see half written mail
+	tmpPath := filepath.Join(f.dir, "tmp", captured.ID+".eml")
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("write mail: %w", err)
+	}
+	if _, err := buildMessage(f.from, opt).WriteTo(file); err != nil {
+		file.Close()
+		return fmt.Errorf("write mail: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("write mail: %w", err)
+	}
+	if err := os.Rename(tmpPath, filepath.Join(f.dir, "new", captured.ID+".eml")); err != nil {
+		return fmt.Errorf("write mail: %w", err)
+	}
+
+	bt, err := json.Marshal(captured)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(f.dir, captured.ID+".json"), bt, 0o644)
+}
+
+// Ping check mail dir is still exist
+func (f *FileMailer) Ping(c context.Context) error {
+	info, err := os.Stat(filepath.Join(f.dir, "new"))
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", filepath.Join(f.dir, "new"))
+	}
+	return nil
+}
+
+// List return captured mail, newest first
+func (f *FileMailer) List(c context.Context) ([]CapturedMail, error) {
+	files, err := filepath.Glob(filepath.Join(f.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	mails := make([]CapturedMail, 0, len(files))
+	for _, file := range files {
+		mail, err := f.read(file)
+		if err != nil {
+			return nil, err
+		}
+		mails = append(mails, *mail)
+	}
+	sort.Slice(mails, func(i, j int) bool { return mails[i].SentAt.After(mails[j].SentAt) })
+	return mails, nil
+}
+
+func (f *FileMailer) Get(c context.Context, id string) (*CapturedMail, error) {
+	// id is generated from unix nano, reject anything else so it can't escape the dir
+	if id == "" || strings.ContainsAny(id, `./\`) {
+		return nil, ErrMailNotFound
+	}
+	mail, err := f.read(filepath.Join(f.dir, id+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrMailNotFound
+	}
+	return mail, err
+}
+
+func (f *FileMailer) Clear(c context.Context) error {
+	for _, pattern := range []string{"*.json", "new/*.eml", "cur/*.eml"} {
+		files, err := filepath.Glob(filepath.Join(f.dir, pattern))
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			if err := os.Remove(file); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *FileMailer) read(path string) (*CapturedMail, error) {
+	bt, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var mail CapturedMail
+	if err := json.Unmarshal(bt, &mail); err != nil {
+		return nil, err
+	}
+	return &mail, nil
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
+	"github.com/Belalai-E-Wallet-Backend/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// All realtime channel should writen with start like this
+// Belalai-E-wallet:events:<user id>
+// every API instance subscribe to the same channel, so event is fanned out to all devices
+func UserEventChannel(userID int) string {
+	return fmt.Sprintf("Belalai-E-wallet:events:%d", userID)
+}
+
+// publish event to all connected devices of a user
+func PublishUserEvent(reqCntxt context.Context, rdb redis.Client, userID int, eventType models.EventType, data any) error {
+	bt, err := json.Marshal(models.UserEvent{
+		Type:      eventType,
+		Data:      data,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		logger.FromContext(reqCntxt).Error("Failed marshal user event", "err", err)
+		return err
+	}
+	if err := rdb.Publish(reqCntxt, UserEventChannel(userID), bt).Err(); err != nil {
+		logger.FromContext(reqCntxt).Error("Redis Error when publish user event", "err", err)
+		return err
+	}
+	return nil
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"strings"
 	"time"
 
+	"github.com/Belalai-E-Wallet-Backend/internal/logger"
+	"github.com/Belalai-E-Wallet-Backend/internal/metrics"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -16,13 +18,27 @@ import (
 func BlackListTokenRedish(reqCntxt context.Context, rdb redis.Client, token string) error {
 	err := rdb.Set(reqCntxt, "Belalai-E-wallet:blacklist:"+token, "true", 30*time.Minute).Err()
 	if err != nil {
-		log.Println("Redis Error when blacklist token:", err)
+		logger.FromContext(reqCntxt).Error("Redis Error when blacklist token", "err", err)
 		return err
 	}
 	// return error nil, if success
 	return nil
 }
 
+// UserLanguageKey is key of user language preference, read by VerifyToken on every authenticated request
+func UserLanguageKey(userID int) string {
+	return fmt.Sprintf("Belalai-E-wallet:lang:%d", userID)
+}
+
+// SaveUserLanguage mirror profile language on redis, no expiration because it is always written when changed.
+// empty lang (user hasn't picked one) remove it, so message follow Accept-Language
+func SaveUserLanguage(ctx context.Context, rdb redis.Client, userID int, lang string) error {
+	if lang == "" {
+		return rdb.Del(ctx, UserLanguageKey(userID)).Err()
+	}
+	return rdb.Set(ctx, UserLanguageKey(userID), lang, 0).Err()
+}
+
 // get redis data return as slice of model
 func RedisGetData[M any](reqCntxt context.Context, rdb redis.Client, rediskey string) (*M, error) {
 	// Store unmarshalling result on generic type
@@ -32,20 +48,23 @@ func RedisGetData[M any](reqCntxt context.Context, rdb redis.Client, rediskey st
 	cmd := rdb.Get(reqCntxt, rediskey)
 	if err := cmd.Err(); err != nil {
 		if err == redis.Nil {
-			log.Printf("Redis key %s not found\n", rediskey)
+			logger.FromContext(reqCntxt).Debug("Redis key not found", "key", rediskey)
+			metrics.ObserveCache(cacheName(rediskey), metrics.CacheMiss)
 			return nil, nil // cache miss
 		}
-		log.Println("Redis Error.\nCause:", err.Error())
+		logger.FromContext(reqCntxt).Error("Redis Error", "err", err)
+		metrics.ObserveCache(cacheName(rediskey), metrics.CacheError)
 		return nil, err
 	} else {
 		// cache hit
+		metrics.ObserveCache(cacheName(rediskey), metrics.CacheHit)
 		cmdByte, err := cmd.Bytes()
 		if err != nil {
-			log.Println("Error reading Redis bytes.\nCause:", err.Error())
+			logger.FromContext(reqCntxt).Error("Error reading Redis bytes", "err", err)
 			return nil, err
 		} else {
 			if err := json.Unmarshal(cmdByte, &result); err != nil {
-				log.Println("Error unmarshalling Redis data.\nCause:", err.Error())
+				logger.FromContext(reqCntxt).Error("Error unmarshalling Redis data", "err", err)
 				return nil, err
 			}
 		}
@@ -54,15 +73,22 @@ func RedisGetData[M any](reqCntxt context.Context, rdb redis.Client, rediskey st
 	return &result, nil
 }
 
+// name of cache used as metric label, ex: Belalai-E-wallet:user-profile:5 -> user-profile
+func cacheName(rediskey string) string {
+	name := strings.TrimPrefix(rediskey, "Belalai-E-wallet:")
+	name, _, _ = strings.Cut(name, ":")
+	return name
+}
+
 // Renew cache redis
 func RedisRenewData[m any](reqCntxt context.Context, redc redis.Client, rediskey string, anyModel m, tt time.Duration) error {
 	// convert any model into byte
 	bt, err := json.Marshal(anyModel)
 	if err != nil {
-		log.Println("Internal Server Error.\nCause: ", err.Error())
+		logger.FromContext(reqCntxt).Error("Internal Server Error", "err", err)
 	} else {
 		if err := redc.Set(reqCntxt, rediskey, string(bt), tt).Err(); err != nil {
-			log.Println("Redis Error.\nCause: ", err.Error())
+			logger.FromContext(reqCntxt).Error("Redis Error", "err", err)
 		}
 	}
 	// return nil nil, if not error
@@ -78,13 +104,13 @@ func DeleteAllCache(reqContxt context.Context, rdb redis.Client) error {
 	cmd := rdb.Del(reqContxt, rdbKeys...)
 	deletedCount, err := cmd.Result()
 	if err != nil {
-		log.Println("Redis Error.\nCause:", err.Error())
+		logger.FromContext(reqContxt).Error("Redis Error", "err", err)
 		return err
 	}
 	if deletedCount == 0 {
-		log.Println("No keys were deleted.")
+		logger.FromContext(reqContxt).Debug("No keys were deleted")
 	} else {
-		log.Printf("Successfully deleted %d keys.\n", deletedCount)
+		logger.FromContext(reqContxt).Info("Successfully deleted cache keys", "count", deletedCount)
 	}
 	// return error nill if success
 	return nil
@@ -107,12 +133,12 @@ func InvalidateUserListCache(ctx context.Context, rdb redis.Client) error {
 	cmd := rdb.Del(ctx, keysToDelete...)
 	deletedCount, err := cmd.Result()
 	if err != nil {
-		log.Println("Redis Error when invalidating user list cache.\nCause:", err.Error())
+		logger.FromContext(ctx).Error("Redis Error when invalidating user list cache", "err", err)
 		return err
 	}
 
 	if deletedCount > 0 {
-		log.Printf("Successfully invalidated %d user list cache keys after registration.\n", deletedCount)
+		logger.FromContext(ctx).Info("Successfully invalidated user list cache keys after registration", "count", deletedCount)
 	}
 
 	return nil
@@ -122,13 +148,13 @@ func InvalidateUserListCache(ctx context.Context, rdb redis.Client) error {
 func HandleRegistrationCache[U any](ctx context.Context, rdb redis.Client, userID int64, userData U) error {
 	// Cache the new user data
 	if err := CacheNewUser(ctx, rdb, userID, userData); err != nil {
-		log.Println("Warning: Failed to cache new user data:", err)
+		logger.FromContext(ctx).Warn("Failed to cache new user data", "err", err)
 		// Don't return error, continue with invalidation
 	}
 
 	// Invalidate user list caches
 	if err := InvalidateUserListCache(ctx, rdb); err != nil {
-		log.Println("Warning: Failed to invalidate user list cache:", err)
+		logger.FromContext(ctx).Warn("Failed to invalidate user list cache", "err", err)
 		return err
 	}
 
@@ -148,12 +174,12 @@ func InvalidateUserProfileCache(ctx context.Context, rdb redis.Client, userID in
 	cmd := rdb.Del(ctx, keysToDelete...)
 	deletedCount, err := cmd.Result()
 	if err != nil {
-		log.Println("Redis Error when invalidating user profile cache.\nCause:", err.Error())
+		logger.FromContext(ctx).Error("Redis Error when invalidating user profile cache", "err", err)
 		return err
 	}
 
 	if deletedCount > 0 {
-		log.Printf("Successfully invalidated %d user profile cache keys after update.\n", deletedCount)
+		logger.FromContext(ctx).Info("Successfully invalidated user profile cache keys after update", "count", deletedCount)
 	}
 
 	return nil
@@ -169,13 +195,13 @@ func CacheUpdatedProfile[P any](ctx context.Context, rdb redis.Client, userID in
 func HandleUpdateProfileCache[P any](ctx context.Context, rdb redis.Client, userID int64, profileData P) error {
 
 	if err := InvalidateUserProfileCache(ctx, rdb, userID); err != nil {
-		log.Println("Warning: Failed to invalidate profile cache:", err)
+		logger.FromContext(ctx).Warn("Failed to invalidate profile cache", "err", err)
 
 	}
 
 	// Cache the updated profile data
 	if err := CacheUpdatedProfile(ctx, rdb, userID, profileData); err != nil {
-		log.Println("Warning: Failed to cache updated profile data:", err)
+		logger.FromContext(ctx).Warn("Failed to cache updated profile data", "err", err)
 		return err
 	}
 
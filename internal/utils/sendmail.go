@@ -1,36 +1,70 @@
 package utils
 
 import (
+	"bytes"
+	"context"
 	"fmt"
-	"os"
+	"net"
+	"strconv"
 
+	"github.com/Belalai-E-Wallet-Backend/internal/configs"
 	"gopkg.in/mail.v2"
 )
 
 type SendOptions struct {
-	To          []string
-	Cc          []string
-	Bcc         []string
-	Subject     string
-	Body        string
-	BodyIsHTML  bool
+	To         []string
+	Cc         []string
+	Bcc        []string
+	Subject    string
+	Body       string
+	BodyIsHTML bool
+	// plain-text alternative of HTML body, for email client that can't render HTML
+	AltBody     string
 	Attachments []string
+	// file generated in memory (ex: statement), attached like Attachments
+	Files []MailFile
 }
 
-func Send(opt SendOptions) error {
-	host := os.Getenv("SMTP_HOST")
-	port := os.Getenv("SMTP_PORT")
-	user := os.Getenv("SMTP_USER")
-	pass := os.Getenv("SMTP_PASS")
-	from := os.Getenv("SMTP_FROM")
+type MailFile struct {
+	Name string
+	Data []byte
+}
+
+// SMTPMailer send email through SMTP server
+type SMTPMailer struct {
+	Host string
+	Port int
+	User string
+	Pass string
+	From string
+}
+
+func NewSMTPMailer(cfg configs.SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{Host: cfg.Host, Port: cfg.Port, User: cfg.User, Pass: cfg.Pass, From: cfg.From}
+}
 
-	if host == "" || port == "" || user == "" || pass == "" || from == "" {
-		return fmt.Errorf("missing SMTP env config")
+func (s *SMTPMailer) Send(c context.Context, opt SendOptions) error {
+	m := buildMessage(s.From, opt)
+	d := mail.NewDialer(s.Host, s.Port, s.User, s.Pass)
+
+	if err := d.DialAndSend(m); err != nil {
+		return fmt.Errorf("send mail: %w", err)
 	}
+	return nil
+}
 
-	var portNum int
-	fmt.Sscanf(port, "%d", &portNum)
+// Ping only open TCP connection to SMTP server, doesn't login
+func (s *SMTPMailer) Ping(c context.Context) error {
+	var d net.Dialer
+	conn, err := d.DialContext(c, "tcp", net.JoinHostPort(s.Host, strconv.Itoa(s.Port)))
+	if err != nil {
+		return fmt.Errorf("dial smtp: %w", err)
+	}
+	return conn.Close()
+}
 
+// build MIME message, also used by FileMailer to write .eml file
+func buildMessage(from string, opt SendOptions) *mail.Message {
 	m := mail.NewMessage()
 	m.SetHeader("From", from)
 	if len(opt.To) > 0 {
@@ -44,7 +78,10 @@ func Send(opt SendOptions) error {
 	}
 	m.SetHeader("Subject", opt.Subject)
 
-	if opt.BodyIsHTML {
+	if opt.BodyIsHTML && opt.AltBody != "" {
+		m.SetBody("text/plain", opt.AltBody)
+		m.AddAlternative("text/html", opt.Body)
+	} else if opt.BodyIsHTML {
 		m.SetBody("text/html", opt.Body)
 	} else {
 		m.SetBody("text/plain", opt.Body)
@@ -53,11 +90,8 @@ func Send(opt SendOptions) error {
 	for _, f := range opt.Attachments {
 		m.Attach(f)
 	}
-
-	d := mail.NewDialer(host, portNum, user, pass)
-
-	if err := d.DialAndSend(m); err != nil {
-		return fmt.Errorf("send mail: %w", err)
+	for _, f := range opt.Files {
+		m.AttachReader(f.Name, bytes.NewReader(f.Data))
 	}
-	return nil
+	return m
 }
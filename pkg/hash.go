@@ -64,7 +64,6 @@ func (h *HashConfig) genSalt() ([]byte, error) {
 
 func (h *HashConfig) CompareHashAndPassword(password, hashedPassword string) (bool, error) {
 	result := strings.Split(hashedPassword, "$")
-	fmt.Println(len(result), result)
 	// cek panjang hasil split, kalau bukan 6 maka format hash invalid
 	if len(result) != 6 {
 		return false, errors.New("invalid hash format")
@@ -2,7 +2,6 @@ package pkg
 
 import (
 	"errors"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -15,19 +14,18 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-func NewJWTClaims(userid int, role string) *Claims {
+func NewJWTClaims(userid int, role, issuer string, ttl time.Duration) *Claims {
 	return &Claims{
 		UserId: userid,
 		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 30)),
-			Issuer:    os.Getenv("JWT_ISSUER"),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			Issuer:    issuer,
 		},
 	}
 }
 
-func (c *Claims) GenToken() (string, error) {
-	jwtSecret := os.Getenv("JWT_SECRET")
+func (c *Claims) GenToken(jwtSecret string) (string, error) {
 	if jwtSecret == "" {
 		return "", errors.New("no secret found")
 	}
@@ -35,8 +33,7 @@ func (c *Claims) GenToken() (string, error) {
 	return token.SignedString([]byte(jwtSecret))
 }
 
-func (c *Claims) VerifyToken(token string) error {
-	jwtSecret := os.Getenv("JWT_SECRET")
+func (c *Claims) VerifyToken(token, jwtSecret, issuer string) error {
 	if jwtSecret == "" {
 		return errors.New("no secret found")
 	}
@@ -51,7 +48,7 @@ func (c *Claims) VerifyToken(token string) error {
 	if err != nil {
 		return err
 	}
-	if iss != os.Getenv("JWT_ISSUER") {
+	if iss != issuer {
 		return jwt.ErrTokenInvalidIssuer
 	}
 	return nil